@@ -0,0 +1,476 @@
+package swagger2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// Type is a data type of parameters, headers and schemas, with its format.
+type Type struct {
+	Name   string // The type, such as "integer"
+	Format string // The format, such as "int64", if any
+}
+
+// The common data types of the specification.
+var (
+	Integer  = Type{"integer", "int32"}
+	Long     = Type{"integer", "int64"}
+	Float    = Type{"number", "float"}
+	Double   = Type{"number", "double"}
+	String   = Type{"string", ""}
+	Byte     = Type{"string", "byte"}
+	Binary   = Type{"string", "binary"}
+	Boolean  = Type{"boolean", ""}
+	Date     = Type{"string", "date"}
+	DateTime = Type{"string", "date-time"}
+	Password = Type{"string", "password"}
+	File     = Type{"file", ""}
+)
+
+// BoolPtr returns a pointer to a bool, for fields such as Required.
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// FloatPtr returns a pointer to a float64, for fields such as Maximum.
+func FloatPtr(f float64) *float64 {
+	return &f
+}
+
+// IntPtr returns a pointer to an int, for fields such as MaxLength.
+func IntPtr(i int) *int {
+	return &i
+}
+
+// StringPtr returns a pointer to a string, for fields such as Pattern.
+func StringPtr(s string) *string {
+	return &s
+}
+
+// Builder builds a document with chained calls, such as
+//
+//	swagger2.New("Pets", "1.0").Path("/pets/{id}").Get().Param(swagger2.PathParam("id", swagger2.Integer)).Response(200, swagger2.Ref("Pet"))
+//
+// Path returns a PathBuilder for a path and the methods of a PathBuilder, such as Get, return an OperationBuilder
+// for an operation. Both also have the methods of the builders they come from, so that a chain can go on with
+// another operation, another path or the document.
+type Builder struct {
+	swag *Swagger
+}
+
+// New starts a document with a title and a version.
+func New(title, version string) *Builder {
+	return &Builder{swag: &Swagger{Swagger: "2.0", Info: Info{Title: title, Version: version}, Paths: make(Paths)}}
+}
+
+// Description sets the description of the API.
+func (b *Builder) Description(text string) *Builder {
+	b.swag.Info.Description = text
+	return b
+}
+
+// Host sets the host, and optionally the port, serving the API.
+func (b *Builder) Host(host string) *Builder {
+	b.swag.Host = host
+	return b
+}
+
+// BasePath sets the path the paths of the API are relative to.
+func (b *Builder) BasePath(path string) *Builder {
+	b.swag.BasePath = path
+	return b
+}
+
+// Schemes sets the transfer protocols of the API, such as "https".
+func (b *Builder) Schemes(schemes ...string) *Builder {
+	b.swag.Schemes = schemes
+	return b
+}
+
+// Consumes sets the media types that the operations consume by default.
+func (b *Builder) Consumes(types ...string) *Builder {
+	b.swag.Consumes = types
+	return b
+}
+
+// Produces sets the media types that the operations produce by default.
+func (b *Builder) Produces(types ...string) *Builder {
+	b.swag.Produces = types
+	return b
+}
+
+// Tag declares a tag with a description.
+func (b *Builder) Tag(name, description string) *Builder {
+	b.swag.Tags = append(b.swag.Tags, Tag{Name: name, Description: description})
+	return b
+}
+
+// Definition adds a definition, which Ref refers to.
+func (b *Builder) Definition(name string, s *Schema) *Builder {
+	if b.swag.Definitions == nil {
+		b.swag.Definitions = make(Definitions)
+	}
+	b.swag.Definitions[name] = *s
+	return b
+}
+
+// SharedParam adds a parameter that operations can share, which ParamRef refers to.
+func (b *Builder) SharedParam(name string, p Parameter) *Builder {
+	if b.swag.Parameters == nil {
+		b.swag.Parameters = make(Parameters)
+	}
+	b.swag.Parameters[name] = p
+	return b
+}
+
+// SecurityDefinition adds a security scheme, such as APIKey("X-Key", "header"), which Security refers to.
+func (b *Builder) SecurityDefinition(name string, def SecurityDefinition) *Builder {
+	if b.swag.SecurityDefinitions == nil {
+		b.swag.SecurityDefinitions = make(SecurityDefinitions)
+	}
+	b.swag.SecurityDefinitions[name] = def
+	return b
+}
+
+// Security adds a security requirement that applies to the operations by default. The requirements added are
+// alternatives: meeting one of them is enough.
+func (b *Builder) Security(name string, scopes ...string) *Builder {
+	b.swag.Security = append(b.swag.Security, securityRequirement(name, scopes))
+	return b
+}
+
+// Path returns a builder for the operations of a path, adding the path if needed.
+func (b *Builder) Path(path string) *PathBuilder {
+	if _, ok := b.swag.Paths[path]; !ok {
+		b.swag.Paths[path] = PathItem{}
+	}
+	return &PathBuilder{Builder: b, path: path}
+}
+
+// Build checks the document and returns it. Besides the checks of Validate, references must point to the
+// definitions, shared parameters and security schemes that were added, and the parameters of the paths, such as id
+// in "/pets/{id}", must be declared by each operation or by its path.
+func (b *Builder) Build() (*Swagger, error) {
+	swag, err := clone(b.swag)
+	if err != nil {
+		return nil, err
+	}
+	errs := append(swag.Validate(), swag.checkReferences()...)
+	if len(errs) > 0 {
+		return nil, errors.New(ErrorList(errs).String())
+	}
+	return swag, nil
+}
+
+// checkReferences returns errors for references to what the document does not define and for path parameters that
+// are not declared
+func (s *Swagger) checkReferences() []error {
+	errs := make([]error, 0)
+	check := func(ptr, ref string) {
+		if ref == "" {
+			return
+		}
+		if name, ok := s.Definitions.RefName(ref); ok {
+			if _, found := s.Definitions[name]; found {
+				return
+			}
+		} else if section, name := ParseRef(ref); section == "parameters" {
+			if _, found := s.Parameters[name]; found {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %s is not defined", ptr, ref))
+	}
+	security := func(ptr string, list []Security) {
+		for _, sec := range list {
+			for _, name := range keys(sec) {
+				if _, ok := s.SecurityDefinitions[name]; !ok {
+					errs = append(errs, fmt.Errorf("%s: security scheme %s is not defined", ptr, name))
+				}
+			}
+		}
+	}
+	security("/security", s.Security)
+	Walk(s, &Hooks{
+		Operation: Hook{Enter: func(c *Cursor) error {
+			op, item := c.Node.(*Operation), c.Parent().(*PathItem)
+			security(pointer(c.Pointer, "security"), op.Security)
+			tokens, _ := pointerTokens(c.Pointer)
+			path := tokens[1]
+			declared := make(map[string]bool)
+			for _, p := range s.OperationParameters(path, item, op) {
+				if p.In == "path" {
+					declared[p.Name] = true
+				}
+			}
+			for _, m := range pathParam.FindAllStringSubmatch(path, -1) {
+				if !declared[m[1]] {
+					errs = append(errs, fmt.Errorf("%s: path parameter %s is not declared", c.Pointer, m[1]))
+				}
+			}
+			return nil
+		}},
+		Parameter: Hook{Enter: func(c *Cursor) error { check(c.Pointer, c.Node.(*Parameter).Ref); return nil }},
+		Schema:    Hook{Enter: func(c *Cursor) error { check(c.Pointer, c.Node.(*Schema).Ref); return nil }},
+		Items:     Hook{Enter: func(c *Cursor) error { check(c.Pointer, c.Node.(*ItemsDef).Ref); return nil }},
+	})
+	return errs
+}
+
+// pathParam matches the parameters of a path, such as {id}
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// PathBuilder builds the operations of a path.
+type PathBuilder struct {
+	*Builder
+	path string
+}
+
+// Param adds parameters that apply to all the operations of the path.
+func (b *PathBuilder) Param(params ...Parameter) *PathBuilder {
+	item := b.swag.Paths[b.path]
+	item.Parameters = append(item.Parameters, params...)
+	b.swag.Paths[b.path] = item
+	return b
+}
+
+// Operation returns a builder for the operation of a lower-case HTTP method, adding the operation if needed.
+func (b *PathBuilder) Operation(method string) *OperationBuilder {
+	item := b.swag.Paths[b.path]
+	op := item.Operation(method)
+	if op == nil {
+		op = &Operation{Responses: make(Responses)}
+		item.SetOperation(method, op)
+		b.swag.Paths[b.path] = item
+	}
+	return &OperationBuilder{PathBuilder: b, op: op}
+}
+
+// Get returns a builder for the GET operation of the path.
+func (b *PathBuilder) Get() *OperationBuilder {
+	return b.Operation("get")
+}
+
+// Put returns a builder for the PUT operation of the path.
+func (b *PathBuilder) Put() *OperationBuilder {
+	return b.Operation("put")
+}
+
+// Post returns a builder for the POST operation of the path.
+func (b *PathBuilder) Post() *OperationBuilder {
+	return b.Operation("post")
+}
+
+// Delete returns a builder for the DELETE operation of the path.
+func (b *PathBuilder) Delete() *OperationBuilder {
+	return b.Operation("delete")
+}
+
+// Options returns a builder for the OPTIONS operation of the path.
+func (b *PathBuilder) Options() *OperationBuilder {
+	return b.Operation("options")
+}
+
+// Head returns a builder for the HEAD operation of the path.
+func (b *PathBuilder) Head() *OperationBuilder {
+	return b.Operation("head")
+}
+
+// Patch returns a builder for the PATCH operation of the path.
+func (b *PathBuilder) Patch() *OperationBuilder {
+	return b.Operation("patch")
+}
+
+// OperationBuilder builds an operation.
+type OperationBuilder struct {
+	*PathBuilder
+	op *Operation
+}
+
+// OperationId sets the unique name of the operation.
+func (b *OperationBuilder) OperationId(id string) *OperationBuilder {
+	b.op.OperationId = id
+	return b
+}
+
+// Summary sets the summary of the operation.
+func (b *OperationBuilder) Summary(text string) *OperationBuilder {
+	b.op.Summary = text
+	return b
+}
+
+// Description sets the description of the operation.
+func (b *OperationBuilder) Description(text string) *OperationBuilder {
+	b.op.Description = text
+	return b
+}
+
+// Tags adds tags to the operation.
+func (b *OperationBuilder) Tags(names ...string) *OperationBuilder {
+	b.op.Tags = append(b.op.Tags, names...)
+	return b
+}
+
+// Deprecated marks the operation as deprecated.
+func (b *OperationBuilder) Deprecated() *OperationBuilder {
+	b.op.Deprecated = true
+	return b
+}
+
+// Consumes sets the media types that the operation consumes.
+func (b *OperationBuilder) Consumes(types ...string) *OperationBuilder {
+	b.op.Consumes = types
+	return b
+}
+
+// Produces sets the media types that the operation produces.
+func (b *OperationBuilder) Produces(types ...string) *OperationBuilder {
+	b.op.Produces = types
+	return b
+}
+
+// Param adds parameters to the operation.
+func (b *OperationBuilder) Param(params ...Parameter) *OperationBuilder {
+	b.op.Parameters = append(b.op.Parameters, params...)
+	return b
+}
+
+// ParamRef adds a reference to a parameter added with SharedParam.
+func (b *OperationBuilder) ParamRef(name string) *OperationBuilder {
+	return b.Param(Parameter{ItemsDef: ItemsDef{Ref: MakeRef("parameters", name)}})
+}
+
+// Security adds a security requirement to the operation, replacing those of the document. The requirements added
+// are alternatives: meeting one of them is enough.
+func (b *OperationBuilder) Security(name string, scopes ...string) *OperationBuilder {
+	b.op.Security = append(b.op.Security, securityRequirement(name, scopes))
+	return b
+}
+
+// NoSecurity removes the security requirements of the document from the operation.
+func (b *OperationBuilder) NoSecurity() *OperationBuilder {
+	b.op.Security = make([]Security, 0)
+	return b
+}
+
+// Extension sets a vendor extension of the operation, such as x-audience.
+func (b *OperationBuilder) Extension(name string, value interface{}) *OperationBuilder {
+	if b.op.Extensions == nil {
+		b.op.Extensions = make(Extensions)
+	}
+	b.op.Extensions[name] = value
+	return b
+}
+
+// Response adds a response for a status code, with the standard text of the code as its description and a schema
+// that can be nil.
+func (b *OperationBuilder) Response(code int, schema *Schema) *OperationBuilder {
+	return b.AddResponse(strconv.Itoa(code), Response{Description: http.StatusText(code), Schema: schema})
+}
+
+// DefaultResponse adds the response for the status codes that have no response of their own.
+func (b *OperationBuilder) DefaultResponse(description string, schema *Schema) *OperationBuilder {
+	return b.AddResponse("default", Response{Description: description, Schema: schema})
+}
+
+// AddResponse adds a response for a status code such as "404", or "default".
+func (b *OperationBuilder) AddResponse(code string, r Response) *OperationBuilder {
+	b.op.Responses[code] = r
+	return b
+}
+
+// securityRequirement returns a security requirement, with an empty list of scopes rather than nil
+func securityRequirement(name string, scopes []string) Security {
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+	return Security{name: scopes}
+}
+
+// typed returns items of a type
+func typed(t Type) ItemsDef {
+	return ItemsDef{Type: t.Name, Format: t.Format}
+}
+
+// PathParam returns a parameter in the path, which is required.
+func PathParam(name string, t Type) Parameter {
+	return Parameter{Name: name, In: "path", Required: BoolPtr(true), ItemsDef: typed(t)}
+}
+
+// QueryParam returns an optional parameter in the query string.
+func QueryParam(name string, t Type) Parameter {
+	return Parameter{Name: name, In: "query", ItemsDef: typed(t)}
+}
+
+// HeaderParam returns an optional parameter in a header.
+func HeaderParam(name string, t Type) Parameter {
+	return Parameter{Name: name, In: "header", ItemsDef: typed(t)}
+}
+
+// FormParam returns an optional parameter in a form.
+func FormParam(name string, t Type) Parameter {
+	return Parameter{Name: name, In: "formData", ItemsDef: typed(t)}
+}
+
+// BodyParam returns a required body parameter with a schema.
+func BodyParam(name string, s *Schema) Parameter {
+	return Parameter{Name: name, In: "body", Required: BoolPtr(true), Schema: s}
+}
+
+// ArrayParam returns an optional parameter with a list of values of a type, such as
+// ArrayParam("tags", "query", String, "multi").
+func ArrayParam(name, in string, t Type, collectionFormat string) Parameter {
+	items := typed(t)
+	return Parameter{Name: name, In: in, ItemsDef: ItemsDef{Type: "array", Items: &items, CollectionFormat: collectionFormat}}
+}
+
+// Ref returns a schema that refers to a definition.
+func Ref(name string) *Schema {
+	return &Schema{ItemsDef: ItemsDef{Ref: MakeRef("definitions", name)}}
+}
+
+// SchemaOf returns the schema of a type.
+func SchemaOf(t Type) *Schema {
+	return &Schema{ItemsDef: typed(t)}
+}
+
+// ArrayOf returns the schema of an array. Since the items of a schema cannot have properties, items that need them
+// should refer to a definition.
+func ArrayOf(items *Schema) *Schema {
+	i := items.ItemsDef
+	return &Schema{ItemsDef: ItemsDef{Type: "array", Items: &i}}
+}
+
+// MapOf returns the schema of an object with values of a schema and any property names.
+func MapOf(values *Schema) *Schema {
+	v := values.ItemsDef
+	return &Schema{ItemsDef: ItemsDef{Type: "object", AdditionalProperties: &v}}
+}
+
+// Object returns the schema of an object with properties, some of which are required.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	s := &Schema{ItemsDef: ItemsDef{Type: "object"}, Properties: make(map[string]Schema, len(properties)), Required: required}
+	for name, p := range properties {
+		s.Properties[name] = *p
+	}
+	return s
+}
+
+// APIKey returns a security scheme with an API key in a header or in the query string.
+func APIKey(name, in string) SecurityDefinition {
+	return SecurityDefinition{Type: "apiKey", Name: name, In: in}
+}
+
+// BasicAuth returns a security scheme with a user name and a password.
+func BasicAuth() SecurityDefinition {
+	return SecurityDefinition{Type: "basic"}
+}
+
+// OAuth2 returns an OAuth 2 security scheme with a flow ("implicit", "password", "application" or "accessCode"),
+// the URLs it needs and its scopes with their descriptions.
+func OAuth2(flow, authorizationUrl, tokenUrl string, scopes map[string]string) SecurityDefinition {
+	return SecurityDefinition{Type: "oauth2", Flow: flow, AuthorizationUrl: authorizationUrl, TokenUrl: tokenUrl, Scopes: scopes}
+}
@@ -0,0 +1,73 @@
+package swagger2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	limit := QueryParam("limit", Integer)
+	limit.Maximum = FloatPtr(100)
+	swag, err := New("Pets", "1.0").
+		Description("A pet shelter.").
+		BasePath("/v1").
+		Produces("application/json").
+		Tag("pets", "Pets in the shelter.").
+		SecurityDefinition("key", APIKey("X-Key", "header")).
+		SecurityDefinition("oauth", OAuth2("implicit", "https://example.com/auth", "", map[string]string{"admin": "Administration."})).
+		Security("key").
+		SharedParam("limit", limit).
+		Definition("Pet", Object(map[string]*Schema{"name": SchemaOf(String), "tags": ArrayOf(SchemaOf(String))}, "name")).
+		Definition("Error", Object(map[string]*Schema{"message": SchemaOf(String)})).
+		Path("/pets").
+		Get().OperationId("listPets").Tags("pets").ParamRef("limit").Param(ArrayParam("tag", "query", String, "multi")).
+		Response(200, ArrayOf(Ref("Pet"))).DefaultResponse("Error", Ref("Error")).
+		Post().OperationId("addPet").Tags("pets").Param(BodyParam("pet", Ref("Pet"))).Response(201, nil).
+		Path("/pets/{id}").Param(PathParam("id", Long)).
+		Get().OperationId("getPet").Response(200, Ref("Pet")).Response(404, Ref("Error")).
+		Delete().OperationId("deletePet").Security("oauth", "admin").Extension("x-audience", "internal").Response(204, nil).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swag.Swagger != "2.0" || swag.Info.Description != "A pet shelter." || len(swag.Paths) != 2 || len(swag.Tags) != 1 {
+		t.Errorf("unexpected document %+v", swag)
+	}
+	list := swag.Paths["/pets"].Get
+	if list.Parameters[0].Ref != "#/parameters/limit" || list.Parameters[1].Items.Type != "string" ||
+		list.Responses["200"].Description != "OK" || list.Responses["200"].Schema.Items.Ref != "#/definitions/Pet" || list.Responses["default"].Description != "Error" {
+		t.Errorf("unexpected operation %+v", list)
+	}
+	if add := swag.Paths["/pets"].Post; !*add.Parameters[0].Required || add.Responses["201"].Description != "Created" {
+		t.Errorf("unexpected operation %+v", add)
+	}
+	item := swag.Paths["/pets/{id}"]
+	if id := item.Parameters[0]; id.In != "path" || !*id.Required || id.Format != "int64" {
+		t.Errorf("unexpected parameter %+v", id)
+	}
+	if del := item.Delete; len(del.Security) != 1 || del.Security[0]["oauth"][0] != "admin" || del.Extensions.String("x-audience") != "internal" {
+		t.Errorf("unexpected operation %+v", del)
+	}
+	if pet := swag.Definitions["Pet"]; len(pet.Required) != 1 || pet.Properties["tags"].Items.Type != "string" || *swag.Parameters["limit"].Maximum != 100 {
+		t.Errorf("unexpected definitions %+v", swag.Definitions)
+	}
+	if len(swag.Security) != 1 || swag.Security[0]["key"] == nil {
+		t.Errorf("unexpected security %+v", swag.Security)
+	}
+
+	_, err = New("Pets", "").
+		Security("key").
+		Path("/pets/{id}").Get().ParamRef("limit").Response(200, Ref("Pet")).Security("oauth").
+		Build()
+	want := []string{
+		"info.version is required",
+		"/security: security scheme key is not defined",
+		"/paths/~1pets~1{id}/get/security: security scheme oauth is not defined",
+		"/paths/~1pets~1{id}/get: path parameter id is not declared",
+		"/paths/~1pets~1{id}/get/parameters/0: #/parameters/limit is not defined",
+		"/paths/~1pets~1{id}/get/responses/200/schema: #/definitions/Pet is not defined",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+// Package changelog writes release notes from the changes between two versions of a Swagger 2 document.
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// Severity tells how much a change matters to the clients of an API.
+type Severity string
+
+// The severities of changes, from the most to the least important.
+const (
+	Breaking      Severity = "breaking"      // Changes that can break existing clients
+	Deprecation   Severity = "deprecation"   // Operations that became deprecated
+	Addition      Severity = "addition"      // Things that were added without breaking clients
+	Change        Severity = "change"        // Other changes that do not break clients
+	Documentation Severity = "documentation" // Descriptions, summaries, examples and other documentation
+)
+
+// Severities lists the severities in the order the sections of a changelog are written.
+var Severities = []Severity{Breaking, Deprecation, Addition, Change, Documentation}
+
+// headings holds the Markdown headings of the sections
+var headings = map[Severity]string{
+	Breaking:      "Breaking changes",
+	Deprecation:   "Deprecations",
+	Addition:      "Additions",
+	Change:        "Changes",
+	Documentation: "Documentation",
+}
+
+// Changelog holds the changes between two versions of a document.
+type Changelog struct {
+	Title      string   `json:"title"`      // The title of the new version of the API
+	OldVersion string   `json:"oldVersion"` // The version of the API before the changes
+	NewVersion string   `json:"newVersion"` // The version of the API after the changes
+	Deprecated []string `json:"deprecated"` // The operations that became deprecated, such as "GET /pets"
+	Groups     []*Group `json:"groups"`     // The changes, grouped by tag
+	Breaking   bool     `json:"breaking"`   // Whether any change can break existing clients
+}
+
+// Group holds the changes to the operations of a tag, or those of the whole document for the group without a tag.
+type Group struct {
+	Tag      string     `json:"tag,omitempty"` // The tag, or "" for changes to the document and to operations without tags
+	Sections []*Section `json:"sections"`      // The changes by severity, in the order of Severities
+}
+
+// Section holds the changes of a group with the same severity.
+type Section struct {
+	Severity Severity         `json:"severity"`
+	Changes  swagger2.Changes `json:"changes"`
+}
+
+// New compares two versions of a document and sorts the changes by tag and severity. Changes to operations go
+// under the first tag of the operation, and other changes under a group without a tag that comes first. Tags are
+// ordered as they are declared in the new version, followed by the tags that are not declared in sorted order. The
+// change of version is not listed, since it is part of the changelog itself.
+func New(old, new *swagger2.Swagger) *Changelog {
+	c := &Changelog{Title: new.Info.Title, OldVersion: old.Info.Version, NewVersion: new.Info.Version, Deprecated: make([]string, 0)}
+	groups := make(map[string]*Group)
+	for _, ch := range swagger2.Diff(old, new) {
+		if ch.Pointer == "/info/version" {
+			continue
+		}
+		severity := Classify(ch)
+		if severity == Breaking {
+			c.Breaking = true
+		}
+		if severity == Deprecation && ch.Method != "" {
+			c.Deprecated = append(c.Deprecated, strings.ToUpper(ch.Method)+" "+ch.Path)
+		}
+		tag := tagOf(new, ch)
+		if tag == "" {
+			tag = tagOf(old, ch)
+		}
+		g, ok := groups[tag]
+		if !ok {
+			g = &Group{Tag: tag, Sections: make([]*Section, 0)}
+			groups[tag] = g
+		}
+		g.add(severity, ch)
+	}
+	c.Groups = order(new, groups)
+	return c
+}
+
+// Classify returns the severity of a change. Deprecation is for operations that became deprecated, documentation
+// for changes to descriptions, summaries, titles, examples and the like that are not breaking, and addition for new
+// paths, operations, parameters, properties and the like. A value given to a field, such as a new maximum, is a
+// change.
+func Classify(ch swagger2.Change) Severity {
+	if ch.Breaking {
+		return Breaking
+	}
+	tokens := strings.Split(ch.Pointer, "/")
+	last, parent := tokens[len(tokens)-1], ""
+	if len(tokens) > 1 {
+		parent = tokens[len(tokens)-2]
+	}
+	switch {
+	case last == "deprecated" && ch.New == true:
+		return Deprecation
+	case documentation[last] && parent != "properties" || parent == "scopes" && ch.Kind == swagger2.Changed:
+		return Documentation
+	case ch.Kind == swagger2.Added && (containers[parent] || tokens[1] == "paths" && len(tokens) <= 4):
+		return Addition
+	}
+	return Change
+}
+
+// containers lists what holds the paths, operations, parameters, properties and other things that can be added,
+// as opposed to the fields that hold values
+var containers = map[string]bool{
+	"paths":               true,
+	"parameters":          true,
+	"responses":           true,
+	"headers":             true,
+	"properties":          true,
+	"definitions":         true,
+	"securityDefinitions": true,
+	"scopes":              true,
+	"tags":                true,
+}
+
+// documentation lists the fields that only document an API
+var documentation = map[string]bool{
+	"description":    true,
+	"summary":        true,
+	"title":          true,
+	"example":        true,
+	"examples":       true,
+	"externalDocs":   true,
+	"termsOfService": true,
+	"contact":        true,
+	"license":        true,
+}
+
+// add appends a change to the section of its severity
+func (g *Group) add(severity Severity, ch swagger2.Change) {
+	for _, s := range g.Sections {
+		if s.Severity == severity {
+			s.Changes = append(s.Changes, ch)
+			return
+		}
+	}
+	g.Sections = append(g.Sections, &Section{Severity: severity, Changes: swagger2.Changes{ch}})
+	rank := make(map[Severity]int, len(Severities))
+	for i, s := range Severities {
+		rank[s] = i
+	}
+	sort.SliceStable(g.Sections, func(i, j int) bool { return rank[g.Sections[i].Severity] < rank[g.Sections[j].Severity] })
+}
+
+// tagOf returns the first tag of the operation a change is part of, or of the first operation of its path for
+// changes to the path itself
+func tagOf(swag *swagger2.Swagger, ch swagger2.Change) string {
+	item, ok := swag.Paths[ch.Path]
+	if !ok {
+		return ""
+	}
+	for _, m := range swagger2.Methods {
+		if ch.Method != "" && m != ch.Method {
+			continue
+		}
+		if op := item.Operation(m); op != nil && len(op.Tags) > 0 {
+			return op.Tags[0]
+		}
+	}
+	return ""
+}
+
+// order lists the groups: the one without a tag, then those of the declared tags, then the others by name
+func order(swag *swagger2.Swagger, groups map[string]*Group) []*Group {
+	list := make([]*Group, 0, len(groups))
+	if g, ok := groups[""]; ok {
+		list = append(list, g)
+		delete(groups, "")
+	}
+	for _, t := range swag.Tags {
+		if g, ok := groups[t.Name]; ok {
+			list = append(list, g)
+			delete(groups, t.Name)
+		}
+	}
+	rest := make([]string, 0, len(groups))
+	for name := range groups {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		list = append(list, groups[name])
+	}
+	return list
+}
+
+// JSON returns the changelog as indented JSON.
+func (c *Changelog) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// Markdown returns the changelog as a Markdown page. Its heading gives the change of version, followed by the
+// operations that became deprecated and a warning when there are breaking changes but the version is the same.
+func (c *Changelog) Markdown() []byte {
+	var b bytes.Buffer
+	if c.OldVersion != c.NewVersion {
+		fmt.Fprintf(&b, "# %s %s\n\nChanges since version %s.\n\n", c.Title, c.NewVersion, c.OldVersion)
+	} else {
+		fmt.Fprintf(&b, "# %s %s\n\n", c.Title, c.NewVersion)
+		if c.Breaking {
+			b.WriteString("> **Warning:** there are breaking changes, but the version did not change.\n\n")
+		}
+	}
+	if len(c.Deprecated) > 0 {
+		b.WriteString("**Newly deprecated:** ")
+		for i, op := range c.Deprecated {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "`%s`", op)
+		}
+		b.WriteString("\n\n")
+	}
+	if len(c.Groups) == 0 {
+		b.WriteString("No changes.\n")
+	}
+	for _, g := range c.Groups {
+		if g.Tag == "" {
+			b.WriteString("## General\n\n")
+		} else {
+			fmt.Fprintf(&b, "## %s\n\n", g.Tag)
+		}
+		for _, s := range g.Sections {
+			fmt.Fprintf(&b, "### %s\n\n", headings[s.Severity])
+			for _, ch := range s.Changes {
+				fmt.Fprintf(&b, "- %s\n", strings.Replace(ch.Text, "\n", " ", -1))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
@@ -0,0 +1,145 @@
+package changelog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+const v1 = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+basePath: /v1
+tags:
+  - {name: pets}
+  - {name: owners}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      summary: List pets.
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat, dog]}
+      responses:
+        200: {description: The pets.}
+  /pets/{petId}:
+    delete:
+      tags: [pets]
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+      responses:
+        204: {description: Deleted.}
+  /owners:
+    get:
+      tags: [owners]
+      responses:
+        200: {description: The owners.}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+`
+
+const v2 = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.1", description: Adopt a pet.}
+basePath: /v1
+tags:
+  - {name: pets}
+  - {name: owners}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      summary: List the pets waiting for a home.
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat, dog, bird]}
+        - {name: limit, in: query, type: integer}
+      responses:
+        200: {description: The pets.}
+  /owners:
+    get:
+      tags: [owners]
+      deprecated: true
+      responses:
+        200: {description: The owners.}
+  /health:
+    get:
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string, maxLength: 50}
+`
+
+func load(t *testing.T, doc string) *swagger2.Swagger {
+	swag, err := swagger2.LoadYaml([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return swag
+}
+
+func TestMarkdown(t *testing.T) {
+	out := string(New(load(t, v1), load(t, v2)).Markdown())
+	want := "# Pet Shelter 1.1\n\nChanges since version 1.0.\n\n" +
+		"**Newly deprecated:** `GET /owners`\n\n" +
+		"## General\n\n" +
+		"### Additions\n\n- GET /health was added\n\n" +
+		"### Changes\n\n- definition `Pet`: maxLength of property `name` was set to 50\n\n" +
+		"### Documentation\n\n- description was added\n\n" +
+		"## pets\n\n" +
+		"### Breaking changes\n\n- DELETE /pets/{petId} was removed\n\n" +
+		"### Additions\n\n- GET /pets: parameter `limit` was added\n\n" +
+		"### Changes\n\n- GET /pets: enum of parameter `kind` changed from [\"cat\",\"dog\"] to [\"cat\",\"dog\",\"bird\"]\n\n" +
+		"### Documentation\n\n- summary of GET /pets changed\n\n" +
+		"## owners\n\n" +
+		"### Deprecations\n\n- GET /owners became deprecated\n"
+	if out != want {
+		t.Errorf("unexpected changelog:\n%s", out)
+	}
+}
+
+func TestSameVersion(t *testing.T) {
+	old := load(t, v2)
+	new := load(t, v2)
+	new.Info.Version = "1.0"
+	old.Info.Version = "1.0"
+	if out := string(New(old, new).Markdown()); out != "# Pet Shelter 1.0\n\nNo changes.\n" {
+		t.Errorf("unexpected changelog:\n%s", out)
+	}
+	delete(new.Paths, "/health")
+	if out := string(New(old, new).Markdown()); !strings.Contains(out, "> **Warning:** there are breaking changes, but the version did not change.") {
+		t.Errorf("missing warning:\n%s", out)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	b, err := New(load(t, v1), load(t, v2)).JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c struct {
+		OldVersion, NewVersion string
+		Breaking               bool
+		Groups                 []struct {
+			Tag      string
+			Sections []struct {
+				Severity string
+				Changes  []struct{ Text, Pointer string }
+			}
+		}
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.OldVersion != "1.0" || c.NewVersion != "1.1" || !c.Breaking || len(c.Groups) != 3 || c.Groups[1].Tag != "pets" ||
+		c.Groups[1].Sections[0].Severity != "breaking" || c.Groups[1].Sections[0].Changes[0].Pointer != "/paths/~1pets~1{petId}/delete" {
+		t.Errorf("unexpected changelog:\n%s", b)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/changelog"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input files to be interpreted as the given type (json or yaml)")
+	format := flag.String("format", "markdown", "Format of the changelog (markdown or json)")
+	out := flag.String("o", "", "Output file; defaults to standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagchangelog [options] old new")
+		fmt.Fprintln(os.Stderr, "Writes the changelog between two versions of a Swagger 2 document, grouped by tag and severity.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintln(os.Stderr, "The -format option must be markdown or json")
+		os.Exit(2)
+	}
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	c := changelog.New(load(flag.Arg(0), *force), load(flag.Arg(1), *force))
+	var doc []byte
+	if *format == "json" {
+		var err error
+		if doc, err = c.JSON(); err != nil {
+			log.Fatal(err)
+		}
+		doc = append(doc, '\n')
+	} else {
+		doc = c.Markdown()
+	}
+	if *out == "" {
+		os.Stdout.Write(doc)
+	} else if err := ioutil.WriteFile(*out, doc, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads a document, as YAML or JSON depending on its extension unless the type is forced
+func load(f, force string) *swagger2.Swagger {
+	ext := filepath.Ext(f)
+	if force != "" {
+		ext = "." + force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatalf("%s: %s", f, err)
+	}
+	return swag
+}
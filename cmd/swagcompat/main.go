@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input files to be interpreted as the given type (json or yaml)")
+	all := flag.Bool("all", false, "List every change, marking those that can break clients, instead of only the breaking ones")
+	asJson := flag.Bool("json", false, "Write the changes as JSON")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagcompat [options] old new")
+		fmt.Fprintln(os.Stderr, "Lists the changes between two versions of a Swagger 2 document that can break existing clients.")
+		fmt.Fprintln(os.Stderr, "Exits with status 1 when there are any, and with status 2 when the documents cannot be read.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	old := load(flag.Arg(0), *force)
+	new := load(flag.Arg(1), *force)
+	changes := swagger2.Diff(old, new)
+	breaking := changes.Breaking()
+	if !*all {
+		changes = breaking
+	}
+	if *asJson {
+		b, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, c := range changes {
+			if *all && c.Breaking {
+				fmt.Println("BREAKING " + c.Text)
+			} else if *all {
+				fmt.Println("         " + c.Text)
+			} else {
+				fmt.Println(c.Text)
+			}
+		}
+	}
+	if len(breaking) > 0 {
+		os.Exit(1)
+	}
+}
+
+// load reads a document, as YAML or JSON depending on its extension unless the type is forced
+func load(f, force string) *swagger2.Swagger {
+	ext := filepath.Ext(f)
+	if force != "" {
+		ext = "." + force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		fatal(fmt.Sprintf("%s: %s", f, err))
+	}
+	return swag
+}
+
+// fatal logs an error and exits with status 2, since status 1 means that there are breaking changes
+func fatal(v interface{}) {
+	log.Print(v)
+	os.Exit(2)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/openapi3"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	out := flag.String("out", "json", "Output format (json or yaml)")
+	to := flag.String("to", "openapi3", "Target specification (openapi3 or swagger2)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagconv [options] file")
+		fmt.Fprintln(os.Stderr, "Converts a Swagger 2 document to OpenAPI 3.0, or back with -to swagger2, and writes it to standard output.")
+		fmt.Fprintln(os.Stderr, "Anything that could not be converted exactly is reported on standard error.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *out != "yaml" && *out != "json" {
+		fmt.Fprintln(os.Stderr, "The -out option must be json or yaml")
+		os.Exit(2)
+	}
+	if *to != "openapi3" && *to != "swagger2" {
+		fmt.Fprintln(os.Stderr, "The -to option must be openapi3 or swagger2")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	inYaml := ext == ".yaml" || ext == ".yml"
+	var buf []byte
+	var lossy []error
+	if *to == "swagger2" {
+		buf, lossy, err = down(b, inYaml, *out == "yaml")
+	} else {
+		buf, lossy, err = up(b, inYaml, *out == "yaml")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(lossy) > 0 {
+		fmt.Fprintf(os.Stderr, "%s:\n", f)
+		fmt.Fprintln(os.Stderr, swagger2.ErrorList(lossy).Indent("\t"))
+	}
+	os.Stdout.Write(buf)
+}
+
+// up converts Swagger 2 to OpenAPI 3
+func up(b []byte, inYaml, outYaml bool) ([]byte, []error, error) {
+	var swag *swagger2.Swagger
+	var err error
+	if inYaml {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, lossy := openapi3.FromSwagger2(swag)
+	if outYaml {
+		b, err = doc.Yaml()
+	} else {
+		b, err = doc.Json()
+	}
+	return b, lossy, err
+}
+
+// down converts OpenAPI 3 to Swagger 2
+func down(b []byte, inYaml, outYaml bool) ([]byte, []error, error) {
+	var doc *openapi3.OpenAPI
+	var err error
+	if inYaml {
+		doc, err = openapi3.LoadYaml(b)
+	} else {
+		doc, err = openapi3.LoadJson(b)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	swag, lossy := openapi3.ToSwagger2(doc)
+	if outYaml {
+		b, err = swag.Yaml()
+	} else {
+		b, err = swag.Json()
+	}
+	return b, lossy, err
+}
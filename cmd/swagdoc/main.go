@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/docgen"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	format := flag.String("format", "markdown", "Format of the documentation (markdown or html)")
+	out := flag.String("o", "", "Output file; defaults to standard output")
+	split := flag.String("split", "", "Directory to write a Markdown page for each tag to, with an index page named "+docgen.IndexFile)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagdoc [options] file")
+		fmt.Fprintln(os.Stderr, "Renders a Swagger 2 document as Markdown or HTML reference documentation.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *format != "markdown" && *format != "html" {
+		fmt.Fprintln(os.Stderr, "The -format option must be markdown or html")
+		os.Exit(2)
+	}
+	if *split != "" && (*out != "" || *format != "markdown") {
+		fmt.Fprintln(os.Stderr, "The -split option cannot be used with -o or -format html")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *split != "" {
+		if err := os.MkdirAll(*split, 0755); err != nil {
+			log.Fatal(err)
+		}
+		for name, page := range docgen.MarkdownByTag(swag) {
+			if err := ioutil.WriteFile(filepath.Join(*split, name), page, 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+		return
+	}
+	var doc []byte
+	if *format == "html" {
+		if doc, err = docgen.HTML(swag); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		doc = docgen.Markdown(swag)
+	}
+	if *out == "" {
+		os.Stdout.Write(doc)
+	} else if err := ioutil.WriteFile(*out, doc, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml), without loading the files it refers to")
+	out := flag.String("out", "json", "Output format (json or yaml)")
+	tags := flag.String("tags", "", "Comma separated tags of the operations to keep")
+	paths := flag.String("paths", "", "Comma separated path patterns of the operations to keep, where * matches a segment and ** any number of them")
+	methods := flag.String("methods", "", "Comma separated HTTP methods of the operations to keep")
+	ext := flag.String("ext", "", "Comma separated name=value vendor extensions of the operations to keep, such as x-audience=public")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagfilter [options] file")
+		fmt.Fprintln(os.Stderr, "Keeps the operations of a Swagger 2 document that match all the options given, removes what they no longer use, and writes it to standard output.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *out != "yaml" && *out != "json" {
+		fmt.Fprintln(os.Stderr, "The -out option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts := swagger2.FilterOptions{Tags: list(*tags), Paths: list(*paths), Methods: list(*methods)}
+	for _, e := range list(*ext) {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "x-") {
+			fmt.Fprintln(os.Stderr, "The -ext option must list name=value pairs whose names begin with x-")
+			os.Exit(2)
+		}
+		if opts.Extensions == nil {
+			opts.Extensions = make(map[string][]string)
+		}
+		opts.Extensions[parts[0]] = append(opts.Extensions[parts[0]], parts[1])
+	}
+	f := flag.Arg(0)
+	swag, err := load(f, *force)
+	if err != nil {
+		log.Fatalf("%s: %s", f, err)
+	}
+	filtered, err := swagger2.Filter(swag, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var b []byte
+	if *out == "yaml" {
+		b, err = filtered.Yaml()
+	} else {
+		b, err = filtered.Json()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(b)
+}
+
+// list splits a comma separated option, returning nil for an empty one
+func list(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// load reads a document along with the files it refers to, or only the document as the given type when forced
+func load(f, force string) (*swagger2.Swagger, error) {
+	if force == "" {
+		return swagger2.LoadFile(f)
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if force == "yaml" {
+		return swagger2.LoadYaml(b)
+	}
+	return swagger2.LoadJson(b)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/gogen"
+	"github.com/babelrpc/swagger2/tsgen"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	lang := flag.String("lang", "go", "Language to generate: go or ts (TypeScript)")
+	pkg := flag.String("package", "api", "Name of the generated Go package")
+	typesModule := flag.String("types", "./types", "Module the TypeScript client imports the types from")
+	out := flag.String("o", "", "Output file; defaults to standard output")
+	gen := flag.String("gen", "types", "What to generate: types, or a client or server to place in the same package")
+	byTag := flag.Bool("bytag", false, "Declare a server interface for each operation tag")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swaggen [options] file")
+		fmt.Fprintln(os.Stderr, "Generates Go or TypeScript source code from a Swagger 2 document.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	goOpts := gogen.Options{Package: *pkg, ByTag: *byTag}
+	tsOpts := tsgen.Options{TypesModule: *typesModule}
+	generators := map[string]func(*swagger2.Swagger) ([]byte, error){
+		"go/types":  func(s *swagger2.Swagger) ([]byte, error) { return gogen.Types(s, goOpts) },
+		"go/client": func(s *swagger2.Swagger) ([]byte, error) { return gogen.Client(s, goOpts) },
+		"go/server": func(s *swagger2.Swagger) ([]byte, error) { return gogen.Server(s, goOpts) },
+		"ts/types":  func(s *swagger2.Swagger) ([]byte, error) { return tsgen.Types(s, tsOpts) },
+		"ts/client": func(s *swagger2.Swagger) ([]byte, error) { return tsgen.Client(s, tsOpts) },
+	}
+	generate, ok := generators[*lang+"/"+*gen]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "The -lang option must be go or ts, and -gen must be types, client or server (go only)")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(swag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input files to be interpreted as the given type (json or yaml)")
+	out := flag.String("out", "json", "Output format (json or yaml)")
+	strategy := flag.String("strategy", "error", "What to do with definitions of the same name that differ (error, prefix or keep-first)")
+	names := flag.String("names", "", "Comma separated names of the documents, which prefix renamed definitions; defaults to their titles")
+	prefix := flag.Bool("prefix-base-path", false, "Prefix the paths of each document with its base path")
+	title := flag.String("title", "", "Title of the merged document; defaults to the title of the first document")
+	version := flag.String("version", "", "Version of the merged document, when a title is given")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagmerge [options] file...")
+		fmt.Fprintln(os.Stderr, "Merges Swagger 2 documents into one and writes it to standard output.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *out != "yaml" && *out != "json" {
+		fmt.Fprintln(os.Stderr, "The -out option must be json or yaml")
+		os.Exit(2)
+	}
+	opts := swagger2.MergeOptions{PrefixBasePath: *prefix, Info: swagger2.Info{Title: *title, Version: *version}}
+	switch *strategy {
+	case "error":
+		opts.Strategy = swagger2.ConflictError
+	case "prefix":
+		opts.Strategy = swagger2.ConflictPrefix
+	case "keep-first":
+		opts.Strategy = swagger2.ConflictKeepFirst
+	default:
+		fmt.Fprintln(os.Stderr, "The -strategy option must be error, prefix or keep-first")
+		os.Exit(2)
+	}
+	if *names != "" {
+		opts.Names = strings.Split(*names, ",")
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	docs := make([]*swagger2.Swagger, flag.NArg())
+	for i, f := range flag.Args() {
+		ext := filepath.Ext(f)
+		if *force != "" {
+			ext = "." + *force
+		}
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if ext == ".yaml" || ext == ".yml" {
+			docs[i], err = swagger2.LoadYaml(b)
+		} else {
+			docs[i], err = swagger2.LoadJson(b)
+		}
+		if err != nil {
+			log.Fatalf("%s: %s", f, err)
+		}
+	}
+	merged, conflicts, err := swagger2.Merge(opts, docs...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, c := range conflicts {
+		fmt.Fprintln(os.Stderr, c)
+	}
+	var b []byte
+	if *out == "yaml" {
+		b, err = merged.Yaml()
+	} else {
+		b, err = merged.Json()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(b)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml), without loading the files it refers to")
+	out := flag.String("out", "json", "Output format (json or yaml)")
+	kind := flag.String("type", "overlay", "Type of the patch files (json-patch, merge-patch or overlay)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagpatch [options] file patch...")
+		fmt.Fprintln(os.Stderr, "Applies JSON Patch, JSON Merge Patch or overlay files in order to a Swagger 2 document and writes it to standard output.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if *out != "yaml" && *out != "json" {
+		fmt.Fprintln(os.Stderr, "The -out option must be json or yaml")
+		os.Exit(2)
+	}
+	if *kind != "json-patch" && *kind != "merge-patch" && *kind != "overlay" {
+		fmt.Fprintln(os.Stderr, "The -type option must be json-patch, merge-patch or overlay")
+		os.Exit(2)
+	}
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	swag, err := load(f, *force)
+	if err != nil {
+		log.Fatalf("%s: %s", f, err)
+	}
+	for _, p := range flag.Args()[1:] {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch *kind {
+		case "json-patch":
+			swag, err = swagger2.JsonPatch(swag, b)
+		case "merge-patch":
+			swag, err = swagger2.MergePatch(swag, b)
+		default:
+			var o *swagger2.Overlay
+			if o, err = swagger2.LoadOverlay(b); err == nil {
+				swag, err = swagger2.ApplyOverlay(swag, o)
+			}
+		}
+		if err != nil {
+			log.Fatalf("%s: %s", p, err)
+		}
+	}
+	var b []byte
+	if *out == "yaml" {
+		b, err = swag.Yaml()
+	} else {
+		b, err = swag.Json()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Stdout.Write(b)
+}
+
+// load reads a document along with the files it refers to, or only the document as the given type when forced
+func load(f, force string) (*swagger2.Swagger, error) {
+	if force == "" {
+		return swagger2.LoadFile(f)
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if force == "yaml" {
+		return swagger2.LoadYaml(b)
+	}
+	return swagger2.LoadJson(b)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/postman"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	name := flag.String("name", "", "Name of the collection; defaults to the document's title")
+	baseURL := flag.String("base-url", "", "Value of the baseUrl variable; defaults to the document's schemes, host and base path")
+	out := flag.String("o", "", "Output file; defaults to standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagpostman [options] file")
+		fmt.Fprintln(os.Stderr, "Exports a Swagger 2 document as a Postman v2.1 collection.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	collection, lossy := postman.Collection(swag, postman.Options{Name: *name, BaseURL: *baseURL})
+	if len(lossy) > 0 {
+		fmt.Fprintf(os.Stderr, "%s:\n", f)
+		fmt.Fprintln(os.Stderr, swagger2.ErrorList(lossy).Indent("\t"))
+	}
+	if collection == nil {
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(collection)
+	} else if err := ioutil.WriteFile(*out, collection, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
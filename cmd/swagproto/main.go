@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/protogen"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	pkg := flag.String("package", "api", "Name of the proto package")
+	goPkg := flag.String("go_package", "", "Value of the go_package option")
+	service := flag.String("service", "", "Name of the service; defaults to the document's title followed by Service")
+	lockFile := flag.String("lock", "", "Lock file that keeps field numbers stable between exports; it is created if missing and updated")
+	out := flag.String("o", "", "Output file; defaults to standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagproto [options] file")
+		fmt.Fprintln(os.Stderr, "Exports a Swagger 2 document as a .proto file with a gRPC service.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := protogen.Options{Package: *pkg, GoPackage: *goPkg, Service: *service}
+	if *lockFile != "" {
+		opts.Lock = protogen.NewLock()
+		if buf, err := ioutil.ReadFile(*lockFile); err == nil {
+			if opts.Lock, err = protogen.LoadLock(buf); err != nil {
+				log.Fatal(err)
+			}
+		} else if !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+	}
+	src, lossy := protogen.Proto(swag, opts)
+	if len(lossy) > 0 {
+		fmt.Fprintf(os.Stderr, "%s:\n", f)
+		fmt.Fprintln(os.Stderr, swagger2.ErrorList(lossy).Indent("\t"))
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+	} else if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if opts.Lock != nil {
+		buf, err := opts.Lock.Marshal()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*lockFile, buf, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
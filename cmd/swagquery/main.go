@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml), without loading the files it refers to")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagquery [options] expression file")
+		fmt.Fprintln(os.Stderr, "Prints the JSON Pointer and the value of each part of a Swagger 2 document that a JSONPath expression such as $..parameters[?(@.in == 'header')] matches, or the value a JSON Pointer such as /paths/~1pets/get points at.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	expr, f := flag.Arg(0), flag.Arg(1)
+	swag, err := load(f, *force)
+	if err != nil {
+		log.Fatalf("%s: %s", f, err)
+	}
+	var matches []swagger2.Match
+	if strings.HasPrefix(expr, "/") || expr == "" {
+		v, err := swagger2.Get(swag, expr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		matches = []swagger2.Match{{Pointer: expr, Value: v}}
+	} else if matches, err = swagger2.Query(swag, expr); err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range matches {
+		b, err := json.Marshal(m.Value)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s\t%s\n", m.Pointer, b)
+	}
+}
+
+// load reads a document along with the files it refers to, or only the document as the given type when forced
+func load(f, force string) (*swagger2.Swagger, error) {
+	if force == "" {
+		return swagger2.LoadFile(f)
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if force == "yaml" {
+		return swagger2.LoadYaml(b)
+	}
+	return swagger2.LoadJson(b)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/babelrpc/swagger2"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	force := flag.String("force", "", "Forces the input file to be interpreted as the given type (json or yaml)")
+	out := flag.String("o", "", "Directory to write the files to")
+	root := flag.String("root", "swagger.yaml", "Name of the root file; a .json name writes JSON files")
+	paths := flag.Bool("paths", false, "Write each path item to its own file too")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: swagsplit [options] -o dir file")
+		fmt.Fprintln(os.Stderr, "Splits a Swagger 2 document into a root file and a file for each definition, shared parameter and shared response.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *force != "" && *force != "yaml" && *force != "json" {
+		fmt.Fprintln(os.Stderr, "The -force option must be json or yaml")
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f := flag.Arg(0)
+	ext := filepath.Ext(f)
+	if *force != "" {
+		ext = "." + *force
+	}
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var swag *swagger2.Swagger
+	if ext == ".yaml" || ext == ".yml" {
+		swag, err = swagger2.LoadYaml(b)
+	} else {
+		swag, err = swagger2.LoadJson(b)
+	}
+	if err != nil {
+		log.Fatalf("%s: %s", f, err)
+	}
+	files, err := swagger2.Split(swag, swagger2.SplitOptions{Root: *root, Paths: *paths})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, content := range files {
+		file := filepath.Join(*out, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, content, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
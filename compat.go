@@ -0,0 +1,272 @@
+package swagger2
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Breaking returns the changes that can break existing clients.
+func (c Changes) Breaking() Changes {
+	breaking := make(Changes, 0)
+	for _, ch := range c {
+		if ch.Breaking {
+			breaking = append(breaking, ch)
+		}
+	}
+	return breaking
+}
+
+// side tells whether a change is part of what clients send, what they receive, or both
+type side struct {
+	request, response bool
+}
+
+// classify marks the changes that can break existing clients:
+//
+//   - Removed paths, operations, successful responses, response bodies and headers, and security definitions or
+//     scopes.
+//   - Changed host or base path, and removed schemes.
+//   - Parameters and request properties that became required, and response properties that were removed or are no
+//     longer required.
+//   - Changed types, formats, references, items and collection formats. A type or format added to a request, or
+//     removed from a response, counts as changed.
+//   - Enums narrowed in requests or widened in responses.
+//   - Constraints tightened in requests, such as a lower maximum or a new pattern.
+//   - Security requirements that clients may no longer meet.
+//   - Media types removed from consumes or produces.
+//
+// Definitions are on the request side when a parameter refers to them, and on the response side when a response
+// does, directly or through other definitions, in either document.
+func classify(old, new *Swagger, changes Changes) {
+	requests, responses := make(map[string]bool), make(map[string]bool)
+	for _, s := range []*Swagger{old, new} {
+		s.usage(requests, responses)
+	}
+	for i := range changes {
+		c := &changes[i]
+		tokens := strings.Split(c.Pointer, "/")[1:]
+		for j := range tokens {
+			tokens[j] = unescapePointer(tokens[j])
+		}
+		var where side
+		switch {
+		case tokens[0] == "paths" && len(tokens) > 2 && tokens[2] == "parameters",
+			tokens[0] == "paths" && len(tokens) > 3 && tokens[3] == "parameters",
+			tokens[0] == "parameters":
+			where.request = true
+		case tokens[0] == "paths" && len(tokens) > 3 && tokens[3] == "responses",
+			tokens[0] == "responses":
+			where.response = true
+		case tokens[0] == "definitions":
+			where.request, where.response = requests[tokens[1]], responses[tokens[1]]
+		}
+		c.Breaking = breaks(old, new, c, tokens, where)
+	}
+}
+
+// breaks tells whether a change can break clients
+func breaks(old, new *Swagger, c *Change, tokens []string, where side) bool {
+	last := tokens[len(tokens)-1]
+	parent := ""
+	if len(tokens) > 1 {
+		parent = tokens[len(tokens)-2]
+	}
+	switch {
+	case len(tokens) == 1:
+		switch last {
+		case "host", "basePath":
+			return c.Kind != Added
+		case "schemes":
+			return c.Kind == Changed && !subset(c.Old, c.New)
+		case "consumes", "produces":
+			return c.Kind == Changed && !subset(c.Old, c.New)
+		case "security":
+			return securityBreaks(old.Security, new.Security)
+		}
+		return false
+	case len(tokens) == 2:
+		switch tokens[0] {
+		case "paths", "securityDefinitions":
+			return c.Kind == Removed
+		}
+		return false
+	case tokens[0] == "paths" && len(tokens) == 3:
+		return c.Kind == Removed
+	case tokens[0] == "securityDefinitions":
+		if parent == "scopes" {
+			return c.Kind == Removed
+		}
+		switch last {
+		case "type", "name", "in", "flow", "authorizationUrl", "tokenUrl":
+			return true
+		}
+		return false
+	case parent == "properties":
+		return c.Kind == Removed && where.response
+	case parent == "parameters" && c.Kind == Added:
+		p, _ := c.New.(Parameter)
+		return p.In == "path" || p.Required != nil && *p.Required
+	case parent == "responses" && c.Kind == Removed:
+		return strings.HasPrefix(last, "2")
+	case parent == "headers":
+		return c.Kind == Removed && where.response
+	}
+	switch last {
+	case "required":
+		set, _ := c.New.(bool)
+		return set && where.request || !set && where.response
+	case "type", "$ref", "format":
+		return c.Kind == Changed || c.Kind == Added && where.request || c.Kind == Removed && where.response
+	case "items", "additionalProperties", "collectionFormat":
+		return true
+	case "schema":
+		return c.Kind == Removed && where.response
+	case "enum":
+		return where.request && (c.Old == nil || !subset(c.Old, c.New)) || where.response && c.Old != nil && (c.New == nil || !subset(c.New, c.Old))
+	case "maximum", "maxLength", "maxItems", "maxProperties":
+		return where.request && (c.Kind == Added || c.Kind == Changed && number(c.New) < number(c.Old))
+	case "minimum", "minLength", "minItems", "minProperties":
+		return where.request && (c.Kind == Added || c.Kind == Changed && number(c.New) > number(c.Old))
+	case "pattern", "multipleOf":
+		return where.request && c.Kind != Removed
+	case "exclusiveMaximum", "exclusiveMinimum", "uniqueItems":
+		return where.request && c.New == true
+	case "consumes", "produces":
+		if c.Method == "" {
+			return false
+		}
+		inherited := old.Produces
+		if last == "consumes" {
+			inherited = old.Consumes
+		}
+		was, is := c.Old, c.New
+		if was == nil {
+			was = plain(inherited)
+		}
+		if is == nil {
+			is = plain(inherited)
+		}
+		return was != nil && !subset(was, is)
+	case "security":
+		var was, is []Security
+		if item, ok := old.Paths[c.Path]; ok {
+			if op := item.Operation(c.Method); op != nil {
+				was = op.Security
+			}
+		}
+		if item, ok := new.Paths[c.Path]; ok {
+			if op := item.Operation(c.Method); op != nil {
+				is = op.Security
+			}
+		}
+		if was == nil {
+			was = old.Security
+		}
+		if is == nil {
+			is = new.Security
+		}
+		return securityBreaks(was, is)
+	}
+	return false
+}
+
+// securityBreaks tells whether clients that meet one of the old security requirements may not meet the new ones
+func securityBreaks(old, new []Security) bool {
+	if len(new) == 0 {
+		return false
+	}
+	if len(old) == 0 {
+		return true
+	}
+	for _, o := range old {
+		found := false
+		for _, n := range new {
+			if reflect.DeepEqual(plain(o), plain(n)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
+// subset tells whether every element of a list, as found in a change, is in another one
+func subset(list, of interface{}) bool {
+	l, _ := list.([]interface{})
+	o, _ := of.([]interface{})
+	for _, e := range l {
+		found := false
+		for _, f := range o {
+			if reflect.DeepEqual(e, f) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// number returns the numeric value of a change
+func number(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+// usage adds the definitions that parameters refer to, and those that responses refer to, directly or through other
+// definitions
+func (s *Swagger) usage(requests, responses map[string]bool) {
+	var walk func(schema *Schema, used map[string]bool)
+	walkItems := func(i *ItemsDef, used map[string]bool) {
+		if i != nil {
+			walk(&Schema{ItemsDef: *i}, used)
+		}
+	}
+	walk = func(schema *Schema, used map[string]bool) {
+		if schema == nil {
+			return
+		}
+		if name, ok := s.Definitions.RefName(schema.Ref); ok && !used[name] {
+			used[name] = true
+			if def, found := s.Definitions[name]; found {
+				walk(&def, used)
+			}
+		}
+		walkItems(schema.Items, used)
+		walkItems(schema.AdditionalProperties, used)
+		for i := range schema.AllOf {
+			walk(&schema.AllOf[i], used)
+		}
+		for _, p := range schema.Properties {
+			walk(&p, used)
+		}
+	}
+	for _, p := range s.Parameters {
+		walk(p.Schema, requests)
+	}
+	for _, r := range s.Responses {
+		walk(r.Schema, responses)
+	}
+	for _, item := range s.Paths {
+		for _, p := range item.Parameters {
+			walk(p.Schema, requests)
+		}
+		for _, m := range Methods {
+			op := item.Operation(m)
+			if op == nil {
+				continue
+			}
+			for _, p := range op.Parameters {
+				walk(p.Schema, requests)
+			}
+			for _, r := range op.Responses {
+				walk(r.Schema, responses)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package swagger2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBreaking(t *testing.T) {
+	old, err := LoadYaml([]byte(diffOld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := LoadYaml([]byte(diffNew))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"base path changed from /v1 to /v2",
+		`produces changed from ["application/json","application/xml"] to ["application/json"]`,
+		"GET /pets: enum of parameter `kind` changed from [\"cat\",\"dog\"] to [\"cat\"]",
+		"GET /pets: parameter `limit` became required",
+		"security of GET /pets changed from the default to oauth (read:pets)",
+		"DELETE /pets was removed",
+		"GET /pets/{petId}: type of parameter `petId` changed from integer to string",
+		"GET /pets/{petId}: format of parameter `petId` was set to uuid",
+		"definition `Pet`: property `owner.name` was removed",
+		"shared parameter `trace` became required",
+		"name of security definition `key` changed from X-Key to X-Api-Key",
+		"security definition `oauth`: scope `write:pets` was removed",
+	}
+	if text := Diff(old, new).Breaking().String(); text != strings.Join(want, "\n") {
+		t.Errorf("unexpected breaking changes:\n%s", text)
+	}
+	// Going back, removed properties and operations break clients, while widened enums and loosened formats do not
+	want = []string{
+		"base path changed from /v2 to /v1",
+		"GET /owners was removed",
+		"GET /pets: maximum of parameter `limit` was set to 100",
+		"security of GET /pets changed from oauth (read:pets) to the default",
+		"GET /pets/{petId}: type of parameter `petId` changed from string to integer",
+		"definition `Pet`: property `owner.email` was removed",
+		"definition `Pet`: property `tag` is no longer required",
+		"name of security definition `key` changed from X-Api-Key to X-Key",
+	}
+	if text := Diff(new, old).Breaking().String(); text != strings.Join(want, "\n") {
+		t.Errorf("unexpected breaking changes going back:\n%s", text)
+	}
+}
@@ -0,0 +1,690 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind tells whether something was added to a document, removed from it or changed.
+type ChangeKind string
+
+// The kinds of changes.
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a single difference between two versions of a document.
+type Change struct {
+	Kind     ChangeKind  `json:"kind"`
+	Pointer  string      `json:"pointer"`            // JSON Pointer to what changed, in the new document unless it was removed
+	Path     string      `json:"path,omitempty"`     // The path the change is part of, if any
+	Method   string      `json:"method,omitempty"`   // The lower-case method of the operation the change is part of, if any
+	Old      interface{} `json:"old,omitempty"`      // The old value, unless it was added
+	New      interface{} `json:"new,omitempty"`      // The new value, unless it was removed
+	Text     string      `json:"text"`               // A description for readers, such as "GET /pets: parameter `limit` became required"
+	Breaking bool        `json:"breaking,omitempty"` // Whether the change can break existing clients
+}
+
+// String returns the description of the change.
+func (c Change) String() string {
+	return c.Text
+}
+
+// Changes is a list of changes between two documents.
+type Changes []Change
+
+// String returns the descriptions of the changes, one per line.
+func (c Changes) String() string {
+	lines := make([]string, len(c))
+	for i, ch := range c {
+		lines[i] = ch.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares two versions of a document and returns what changed, from the metadata to the properties of the
+// definitions. Operation parameters are matched by name and location and compared with references to shared
+// parameters resolved, unless both refer to the same one, whose changes are reported once under the document's
+// parameters. References to definitions are compared as they are, since the definitions are compared on their own.
+// The changes are listed in a stable order: metadata, tags, paths, definitions, shared parameters and responses,
+// security definitions and then security. Changes that can break existing clients are marked as such, see Breaking.
+func Diff(old, new *Swagger) Changes {
+	d := &differ{old: old, new: new, changes: make(Changes, 0)}
+	d.metadata()
+	d.tags()
+	d.paths()
+	d.definitions()
+	d.sharedParameters()
+	d.sharedResponses()
+	d.securityDefinitions()
+	d.context("", "", "")
+	d.security("/security", "", old.Security, new.Security, "none")
+	classify(old, new, d.changes)
+	return d.changes
+}
+
+// differ collects the changes between two documents
+type differ struct {
+	old, new     *Swagger
+	changes      Changes
+	path, method string // the operation being compared
+	scope        string // what is being compared, such as "GET /pets", which prefixes the text of changes
+}
+
+// context sets what the next changes are part of
+func (d *differ) context(path, method, scope string) {
+	d.path, d.method, d.scope = path, method, scope
+}
+
+// add records a change. The subject is what changed, within the current scope, and the predicate what happened to
+// it, as in "parameter `limit`" and "became required".
+func (d *differ) add(kind ChangeKind, ptr string, old, new interface{}, subject, predicate string) {
+	text := subject + " " + predicate
+	switch {
+	case subject == "":
+		text = d.scope + " " + predicate
+	case d.scope != "":
+		text = d.scope + ": " + text
+	}
+	d.changes = append(d.changes, Change{Kind: kind, Pointer: ptr, Path: d.path, Method: d.method, Old: old, New: new, Text: strings.TrimSpace(text)})
+}
+
+// presence records that a subject was added or removed, and returns whether it is in both documents
+func (d *differ) presence(ptr, subject string, old, new interface{}, inOld, inNew bool) bool {
+	switch {
+	case inOld && !inNew:
+		d.add(Removed, ptr, old, nil, subject, "was removed")
+	case !inOld && inNew:
+		d.add(Added, ptr, nil, new, subject, "was added")
+	}
+	return inOld && inNew
+}
+
+// quiet lists the fields whose values are too long to quote in the text of a change
+var quiet = map[string]bool{"description": true, "summary": true, "title": true, "example": true, "examples": true, "externalDocs": true}
+
+// flags lists how boolean fields read when they are set and cleared
+var flags = map[string][2]string{
+	"required":   {"became required", "is no longer required"},
+	"deprecated": {"became deprecated", "is no longer deprecated"},
+	"readOnly":   {"became read only", "is no longer read only"},
+}
+
+// value compares a field of a subject. Missing values, nil pointers and empty strings and lists are the same.
+func (d *differ) value(ptr, field, subject string, old, new interface{}) {
+	old, new = plain(old), plain(new)
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	kind := Changed
+	switch {
+	case old == nil:
+		kind = Added
+	case new == nil:
+		kind = Removed
+	}
+	if f, ok := flags[field]; ok {
+		set, _ := new.(bool)
+		if set {
+			d.add(kind, ptr, old, new, subject, f[0])
+		} else {
+			d.add(kind, ptr, old, new, subject, f[1])
+		}
+		return
+	}
+	if subject == "" {
+		scope := d.scope
+		d.scope = ""
+		defer func() { d.scope = scope }()
+		subject = scope
+	}
+	name := of(field, subject)
+	var predicate string
+	switch {
+	case kind == Added && quiet[field]:
+		predicate = "was added"
+	case kind == Added:
+		predicate = "was set to " + display(new)
+	case kind == Removed:
+		predicate = "was removed"
+	case quiet[field]:
+		predicate = "changed"
+	default:
+		predicate = "changed from " + display(old) + " to " + display(new)
+	}
+	d.add(kind, ptr, old, new, name, predicate)
+}
+
+// plain dereferences pointers and turns empty values into nil, so that equivalent values compare equal
+func plain(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if r.IsNil() {
+			return nil
+		}
+		return plain(r.Elem().Interface())
+	case reflect.String, reflect.Slice, reflect.Map:
+		if r.Len() == 0 {
+			return nil
+		}
+	case reflect.Bool:
+		return v
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	// Going through JSON makes numbers decoded from YAML and JSON alike, and structs comparable with maps
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return v
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return v
+	}
+	return generic
+}
+
+// display writes a value for the text of a change
+func display(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// pointer appends reference tokens to a JSON Pointer
+func pointer(ptr string, tokens ...string) string {
+	for _, t := range tokens {
+		ptr += "/" + escapePointer(t)
+	}
+	return ptr
+}
+
+// keys returns the keys of maps with string keys, sorted and without duplicates
+func keys(maps ...interface{}) []string {
+	seen := make(map[string]bool)
+	list := make([]string, 0)
+	for _, m := range maps {
+		r := reflect.ValueOf(m)
+		if r.Kind() != reflect.Map {
+			continue
+		}
+		for _, k := range r.MapKeys() {
+			if s := k.String(); !seen[s] {
+				seen[s] = true
+				list = append(list, s)
+			}
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// metadata compares the information about the API and where it is served
+func (d *differ) metadata() {
+	o, n := d.old, d.new
+	d.value("/info/title", "title", "", o.Info.Title, n.Info.Title)
+	d.value("/info/description", "description", "", o.Info.Description, n.Info.Description)
+	d.value("/info/termsOfService", "terms of service", "", o.Info.TermsOfService, n.Info.TermsOfService)
+	d.value("/info/contact", "contact", "", o.Info.Contact, n.Info.Contact)
+	d.value("/info/license", "license", "", o.Info.License, n.Info.License)
+	d.value("/info/version", "version", "", o.Info.Version, n.Info.Version)
+	d.value("/host", "host", "", o.Host, n.Host)
+	d.value("/basePath", "base path", "", o.BasePath, n.BasePath)
+	d.value("/schemes", "schemes", "", o.Schemes, n.Schemes)
+	d.value("/consumes", "consumes", "", o.Consumes, n.Consumes)
+	d.value("/produces", "produces", "", o.Produces, n.Produces)
+	d.value("/externalDocs", "externalDocs", "", o.ExternalDocs, n.ExternalDocs)
+}
+
+// tags compares the declared tags by name
+func (d *differ) tags() {
+	find := func(tags []Tag, name string) (int, *Tag) {
+		for i := range tags {
+			if tags[i].Name == name {
+				return i, &tags[i]
+			}
+		}
+		return -1, nil
+	}
+	names := make([]string, 0)
+	for _, list := range [][]Tag{d.old.Tags, d.new.Tags} {
+		for _, t := range list {
+			names = append(names, t.Name)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		oi, o := find(d.old.Tags, name)
+		ni, n := find(d.new.Tags, name)
+		d.context("", "", "")
+		ptr := pointer("/tags", strconv.Itoa(ni))
+		if n == nil {
+			ptr = pointer("/tags", strconv.Itoa(oi))
+		}
+		if !d.presence(ptr, "tag `"+name+"`", o, n, o != nil, n != nil) {
+			continue
+		}
+		d.context("", "", "tag `"+name+"`")
+		d.value(ptr+"/description", "description", "", o.Description, n.Description)
+		d.value(ptr+"/externalDocs", "externalDocs", "", o.ExternalDocs, n.ExternalDocs)
+	}
+}
+
+// paths compares the paths and their operations. Added and removed paths are reported as their operations.
+func (d *differ) paths() {
+	for _, path := range keys(d.old.Paths, d.new.Paths) {
+		o, inOld := d.old.Paths[path]
+		n, inNew := d.new.Paths[path]
+		ptr := pointer("/paths", path)
+		d.context(path, "", "")
+		if !inOld || !inNew {
+			some := false
+			for _, m := range Methods {
+				oo, no := o.Operation(m), n.Operation(m)
+				if oo != nil || no != nil {
+					some = true
+					d.context(path, m, "")
+					d.presence(pointer(ptr, m), strings.ToUpper(m)+" "+path, oo, no, oo != nil, no != nil)
+				}
+			}
+			if !some {
+				d.presence(ptr, "path "+path, &o, &n, inOld, inNew)
+			}
+			continue
+		}
+		d.context(path, "", "path "+path)
+		d.parameters(ptr+"/parameters", o.Parameters, n.Parameters)
+		for _, m := range Methods {
+			oo, no := o.Operation(m), n.Operation(m)
+			d.context(path, m, "")
+			if d.presence(pointer(ptr, m), strings.ToUpper(m)+" "+path, oo, no, oo != nil, no != nil) {
+				d.context(path, m, strings.ToUpper(m)+" "+path)
+				d.operation(pointer(ptr, m), oo, no)
+			}
+		}
+	}
+}
+
+// operation compares two versions of an operation
+func (d *differ) operation(ptr string, o, n *Operation) {
+	d.value(ptr+"/summary", "summary", "", o.Summary, n.Summary)
+	d.value(ptr+"/description", "description", "", o.Description, n.Description)
+	d.value(ptr+"/operationId", "operationId", "", o.OperationId, n.OperationId)
+	d.value(ptr+"/deprecated", "deprecated", "", o.Deprecated, n.Deprecated)
+	d.value(ptr+"/tags", "tags", "", o.Tags, n.Tags)
+	d.value(ptr+"/consumes", "consumes", "", o.Consumes, n.Consumes)
+	d.value(ptr+"/produces", "produces", "", o.Produces, n.Produces)
+	d.value(ptr+"/schemes", "schemes", "", o.Schemes, n.Schemes)
+	d.value(ptr+"/externalDocs", "externalDocs", "", o.ExternalDocs, n.ExternalDocs)
+	d.parameters(ptr+"/parameters", o.Parameters, n.Parameters)
+	d.responses(ptr+"/responses", o.Responses, n.Responses)
+	d.security(ptr+"/security", "", o.Security, n.Security, "the default")
+	for _, k := range keys(o.Extensions, n.Extensions) {
+		d.value(pointer(ptr, k), k, "", o.Extensions[k], n.Extensions[k])
+	}
+}
+
+// security compares security requirements. A nil list is described as missing, which differs from an empty one.
+func (d *differ) security(ptr, subject string, o, n []Security, missing string) {
+	if (o == nil) == (n == nil) && reflect.DeepEqual(plain(o), plain(n)) {
+		return
+	}
+	kind := Changed
+	switch {
+	case o == nil:
+		kind = Added
+	case n == nil:
+		kind = Removed
+	}
+	if subject == "" {
+		scope := d.scope
+		d.scope = ""
+		defer func() { d.scope = scope }()
+		subject = scope
+	}
+	d.add(kind, ptr, securityValue(o), securityValue(n), of("security", subject), "changed from "+securityText(o, missing)+" to "+securityText(n, missing))
+}
+
+// securityValue returns security requirements as a value for a change, nil if they are missing
+func securityValue(s []Security) interface{} {
+	if s == nil {
+		return nil
+	}
+	list := make([]interface{}, len(s))
+	for i, req := range s {
+		m := make(map[string]interface{}, len(req))
+		for k, v := range req {
+			scopes := make([]interface{}, len(v))
+			for j, sc := range v {
+				scopes[j] = sc
+			}
+			m[k] = scopes
+		}
+		list[i] = m
+	}
+	return list
+}
+
+// securityText describes security requirements, such as "key or oauth (write:pets)"
+func securityText(s []Security, missing string) string {
+	if s == nil {
+		return missing
+	}
+	if len(s) == 0 {
+		return "none"
+	}
+	alternatives := make([]string, len(s))
+	for i, req := range s {
+		names := keys(map[string][]string(req))
+		for j, name := range names {
+			if len(req[name]) > 0 {
+				names[j] += " (" + strings.Join(req[name], ", ") + ")"
+			}
+		}
+		alternatives[i] = strings.Join(names, " and ")
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// parameters compares lists of parameters, matched by location and name
+func (d *differ) parameters(ptr string, o, n []Parameter) {
+	type entry struct {
+		index int
+		raw   Parameter
+	}
+	index := func(s *Swagger, list []Parameter) map[string]entry {
+		m := make(map[string]entry, len(list))
+		for i, p := range list {
+			r := s.ResolveParameter(p)
+			m[r.In+":"+r.Name] = entry{i, p}
+		}
+		return m
+	}
+	olds, news := index(d.old, o), index(d.new, n)
+	for _, key := range keys(olds, news) {
+		oe, inOld := olds[key]
+		ne, inNew := news[key]
+		op, np := d.old.ResolveParameter(oe.raw), d.new.ResolveParameter(ne.raw)
+		subject := "parameter `" + key[strings.Index(key, ":")+1:] + "`"
+		at := pointer(ptr, strconv.Itoa(ne.index))
+		if !inNew {
+			at = pointer(ptr, strconv.Itoa(oe.index))
+		}
+		if !d.presence(at, subject, op, np, inOld, inNew) {
+			continue
+		}
+		if oe.raw.Ref != "" && oe.raw.Ref == ne.raw.Ref {
+			continue
+		}
+		d.parameter(at, subject, &op, &np)
+	}
+}
+
+// parameter compares two versions of a parameter
+func (d *differ) parameter(ptr, subject string, o, n *Parameter) {
+	d.value(ptr+"/description", "description", subject, o.Description, n.Description)
+	d.value(ptr+"/required", "required", subject, o.Required != nil && *o.Required, n.Required != nil && *n.Required)
+	d.itemsDef(ptr, subject, &o.ItemsDef, &n.ItemsDef)
+	if d.presence(ptr+"/schema", of("schema", subject), o.Schema, n.Schema, o.Schema != nil, n.Schema != nil) {
+		d.schema(ptr+"/schema", subject, "", o.Schema, n.Schema)
+	}
+}
+
+// responses compares responses by status code
+func (d *differ) responses(ptr string, o, n Responses) {
+	for _, code := range keys(o, n) {
+		or, inOld := o[code]
+		nr, inNew := n[code]
+		at, subject := pointer(ptr, code), "response "+code
+		if d.presence(at, subject, or, nr, inOld, inNew) {
+			d.response(at, subject, &or, &nr)
+		}
+	}
+}
+
+// response compares two versions of a response
+func (d *differ) response(ptr, subject string, o, n *Response) {
+	d.value(ptr+"/description", "description", subject, o.Description, n.Description)
+	if d.presence(ptr+"/schema", of("schema", subject), o.Schema, n.Schema, o.Schema != nil, n.Schema != nil) {
+		d.schema(ptr+"/schema", subject, "", o.Schema, n.Schema)
+	}
+	for _, name := range keys(o.Headers, n.Headers) {
+		oh, inOld := o.Headers[name]
+		nh, inNew := n.Headers[name]
+		at, header := pointer(ptr, "headers", name), of("header `"+name+"`", subject)
+		if d.presence(at, header, oh, nh, inOld, inNew) {
+			d.value(at+"/description", "description", header, oh.Description, nh.Description)
+			d.itemsDef(at, header, &oh.ItemsDef, &nh.ItemsDef)
+		}
+	}
+	d.value(ptr+"/examples", "examples", subject, o.Examples, n.Examples)
+}
+
+// itemsDef compares the type and constraints of a parameter, header or items, including those of their items
+func (d *differ) itemsDef(ptr, subject string, o, n *ItemsDef) {
+	d.constraints(ptr, subject, o, n)
+	if d.presence(ptr+"/items", of("items", subject), o.Items, n.Items, o.Items != nil, n.Items != nil) {
+		d.itemsDef(ptr+"/items", of("items", subject), o.Items, n.Items)
+	}
+	if d.presence(ptr+"/additionalProperties", of("values", subject), o.AdditionalProperties, n.AdditionalProperties, o.AdditionalProperties != nil, n.AdditionalProperties != nil) {
+		d.itemsDef(ptr+"/additionalProperties", of("values", subject), o.AdditionalProperties, n.AdditionalProperties)
+	}
+}
+
+// constraints compares the fields of items definitions that hold single values
+func (d *differ) constraints(ptr, subject string, o, n *ItemsDef) {
+	d.value(ptr+"/$ref", "reference", subject, canonicalRef(d.old, o.Ref), canonicalRef(d.new, n.Ref))
+	d.value(ptr+"/type", "type", subject, o.Type, n.Type)
+	d.value(ptr+"/format", "format", subject, o.Format, n.Format)
+	d.value(ptr+"/collectionFormat", "collectionFormat", subject, o.CollectionFormat, n.CollectionFormat)
+	d.value(ptr+"/default", "default", subject, o.Default, n.Default)
+	d.value(ptr+"/maximum", "maximum", subject, o.Maximum, n.Maximum)
+	d.value(ptr+"/exclusiveMaximum", "exclusiveMaximum", subject, o.ExclusiveMaximum, n.ExclusiveMaximum)
+	d.value(ptr+"/minimum", "minimum", subject, o.Minimum, n.Minimum)
+	d.value(ptr+"/exclusiveMinimum", "exclusiveMinimum", subject, o.ExclusiveMinimum, n.ExclusiveMinimum)
+	d.value(ptr+"/maxLength", "maxLength", subject, o.MaxLength, n.MaxLength)
+	d.value(ptr+"/minLength", "minLength", subject, o.MinLength, n.MinLength)
+	d.value(ptr+"/pattern", "pattern", subject, o.Pattern, n.Pattern)
+	d.value(ptr+"/maxItems", "maxItems", subject, o.MaxItems, n.MaxItems)
+	d.value(ptr+"/minItems", "minItems", subject, o.MinItems, n.MinItems)
+	d.value(ptr+"/uniqueItems", "uniqueItems", subject, o.UniqueItems, n.UniqueItems)
+	d.value(ptr+"/enum", "enum", subject, o.Enum, n.Enum)
+	d.value(ptr+"/multipleOf", "multipleOf", subject, o.MultipleOf, n.MultipleOf)
+}
+
+// canonicalRef returns the long form of a reference to a definition, so that "Pet" and "#/definitions/Pet" are alike
+func canonicalRef(s *Swagger, ref string) string {
+	if name, ok := s.Definitions.RefName(ref); ok {
+		return MakeRef("definitions", name)
+	}
+	return ref
+}
+
+// schema compares two versions of a schema. The root names what the schema belongs to, such as "response 200",
+// and name is the dotted path of the property being compared within it, with [] for items and * for the values of
+// maps.
+func (d *differ) schema(ptr, root, name string, o, n *Schema) {
+	subject := schemaSubject(root, name)
+	d.constraints(ptr, subject, &o.ItemsDef, &n.ItemsDef)
+	if d.presence(ptr+"/items", schemaSubject(root, name+"[]"), o.Items, n.Items, o.Items != nil, n.Items != nil) {
+		d.schema(ptr+"/items", root, name+"[]", &Schema{ItemsDef: *o.Items}, &Schema{ItemsDef: *n.Items})
+	}
+	values := join(name, "*")
+	if d.presence(ptr+"/additionalProperties", schemaSubject(root, values), o.AdditionalProperties, n.AdditionalProperties, o.AdditionalProperties != nil, n.AdditionalProperties != nil) {
+		d.schema(ptr+"/additionalProperties", root, values, &Schema{ItemsDef: *o.AdditionalProperties}, &Schema{ItemsDef: *n.AdditionalProperties})
+	}
+	d.value(ptr+"/title", "title", subject, o.Title, n.Title)
+	d.value(ptr+"/description", "description", subject, o.Description, n.Description)
+	d.value(ptr+"/maxProperties", "maxProperties", subject, o.MaxProperties, n.MaxProperties)
+	d.value(ptr+"/minProperties", "minProperties", subject, o.MinProperties, n.MinProperties)
+	d.value(ptr+"/discriminator", "discriminator", subject, o.Discriminator, n.Discriminator)
+	d.value(ptr+"/readOnly", "readOnly", subject, o.ReadOnly != nil && *o.ReadOnly, n.ReadOnly != nil && *n.ReadOnly)
+	d.value(ptr+"/xml", "xml", subject, o.Xml, n.Xml)
+	d.value(ptr+"/externalDocs", "externalDocs", subject, o.ExternalDocs, n.ExternalDocs)
+	d.value(ptr+"/example", "example", subject, o.Example, n.Example)
+	required := func(list []string, p string) bool {
+		for _, r := range list {
+			if r == p {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range keys(o.Properties, n.Properties, set(o.Required), set(n.Required)) {
+		op, inOld := o.Properties[p]
+		np, inNew := n.Properties[p]
+		at, prop := pointer(ptr, "properties", p), schemaSubject(root, join(name, p))
+		if d.presence(at, prop, op, np, inOld, inNew) {
+			d.schema(at, root, join(name, p), &op, &np)
+		}
+		if inNew || !inOld {
+			d.value(ptr+"/required", "required", prop, required(o.Required, p), required(n.Required, p))
+		}
+	}
+	for i := 0; i < len(o.AllOf) || i < len(n.AllOf); i++ {
+		at := pointer(ptr, "allOf", strconv.Itoa(i))
+		var os, ns *Schema
+		if i < len(o.AllOf) {
+			os = &o.AllOf[i]
+		}
+		if i < len(n.AllOf) {
+			ns = &n.AllOf[i]
+		}
+		if d.presence(at, of("allOf["+strconv.Itoa(i)+"]", subject), os, ns, os != nil, ns != nil) {
+			d.schema(at, root, name, os, ns)
+		}
+	}
+	for _, k := range keys(o.Extensions, n.Extensions) {
+		if strings.HasPrefix(k, "x-") {
+			d.value(pointer(ptr, k), k, subject, o.Extensions[k], n.Extensions[k])
+		}
+	}
+}
+
+// schemaSubject names a property of a schema, such as "property `owner.name` of response 200"
+func schemaSubject(root, name string) string {
+	switch name {
+	case "":
+		return root
+	case "[]":
+		return of("items", root)
+	case "*":
+		return of("values", root)
+	}
+	return of("property `"+name+"`", root)
+}
+
+// of names part of a subject, such as "items of parameter `ids`"
+func of(part, subject string) string {
+	if subject == "" {
+		return part
+	}
+	return part + " of " + subject
+}
+
+// join appends a property name to a dotted path
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// set turns a list of names into a map, to list its keys along with those of other maps
+func set(list []string) map[string]bool {
+	m := make(map[string]bool, len(list))
+	for _, s := range list {
+		m[s] = true
+	}
+	return m
+}
+
+// definitions compares the definitions by name
+func (d *differ) definitions() {
+	for _, name := range keys(d.old.Definitions, d.new.Definitions) {
+		o, inOld := d.old.Definitions[name]
+		n, inNew := d.new.Definitions[name]
+		ptr := pointer("/definitions", name)
+		d.context("", "", "")
+		if d.presence(ptr, "definition `"+name+"`", o, n, inOld, inNew) {
+			d.context("", "", "definition `"+name+"`")
+			d.schema(ptr, "", "", &o, &n)
+		}
+	}
+}
+
+// sharedParameters compares the parameters of the document by name
+func (d *differ) sharedParameters() {
+	for _, name := range keys(d.old.Parameters, d.new.Parameters) {
+		o, inOld := d.old.Parameters[name]
+		n, inNew := d.new.Parameters[name]
+		ptr := pointer("/parameters", name)
+		d.context("", "", "")
+		if d.presence(ptr, "shared parameter `"+name+"`", o, n, inOld, inNew) {
+			d.context("", "", "shared parameter `"+name+"`")
+			d.parameter(ptr, "", &o, &n)
+		}
+	}
+}
+
+// sharedResponses compares the responses of the document by name
+func (d *differ) sharedResponses() {
+	for _, name := range keys(d.old.Responses, d.new.Responses) {
+		o, inOld := d.old.Responses[name]
+		n, inNew := d.new.Responses[name]
+		ptr := pointer("/responses", name)
+		d.context("", "", "")
+		if d.presence(ptr, "shared response `"+name+"`", o, n, inOld, inNew) {
+			d.context("", "", "shared response `"+name+"`")
+			d.response(ptr, "", &o, &n)
+		}
+	}
+}
+
+// securityDefinitions compares the security schemes by name
+func (d *differ) securityDefinitions() {
+	for _, name := range keys(d.old.SecurityDefinitions, d.new.SecurityDefinitions) {
+		o, inOld := d.old.SecurityDefinitions[name]
+		n, inNew := d.new.SecurityDefinitions[name]
+		ptr := pointer("/securityDefinitions", name)
+		d.context("", "", "")
+		if !d.presence(ptr, "security definition `"+name+"`", o, n, inOld, inNew) {
+			continue
+		}
+		d.context("", "", "security definition `"+name+"`")
+		d.value(ptr+"/type", "type", "", o.Type, n.Type)
+		d.value(ptr+"/description", "description", "", o.Description, n.Description)
+		d.value(ptr+"/name", "name", "", o.Name, n.Name)
+		d.value(ptr+"/in", "in", "", o.In, n.In)
+		d.value(ptr+"/flow", "flow", "", o.Flow, n.Flow)
+		d.value(ptr+"/authorizationUrl", "authorizationUrl", "", o.AuthorizationUrl, n.AuthorizationUrl)
+		d.value(ptr+"/tokenUrl", "tokenUrl", "", o.TokenUrl, n.TokenUrl)
+		for _, scope := range keys(o.Scopes, n.Scopes) {
+			os, inOld := o.Scopes[scope]
+			ns, inNew := n.Scopes[scope]
+			at := pointer(ptr, "scopes", scope)
+			if d.presence(at, "scope `"+scope+"`", os, ns, inOld, inNew) {
+				d.value(at, "description", "scope `"+scope+"`", os, ns)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+const diffOld = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+host: shelter.example.com
+basePath: /v1
+produces: [application/json, application/xml]
+tags:
+  - {name: pets, description: Pets.}
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+  oauth: {type: oauth2, flow: implicit, authorizationUrl: "https://shelter.example.com/auth", scopes: {"read:pets": Read, "write:pets": Write}}
+security:
+  - key: []
+parameters:
+  trace: {name: X-Trace, in: header, type: string}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, type: integer, maximum: 100}
+        - {name: kind, in: query, type: string, enum: [cat, dog]}
+        - $ref: "#/parameters/trace"
+      responses:
+        200:
+          description: The pets.
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+    delete:
+      responses:
+        204: {description: Deleted.}
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+      responses:
+        200: {description: The pet., schema: {$ref: "#/definitions/Pet"}}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string}
+      tag: {type: string}
+      owner:
+        type: object
+        properties:
+          name: {type: string}
+  Error:
+    type: object
+`
+
+const diffNew = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "2.0"}
+host: shelter.example.com
+basePath: /v2
+produces: [application/json]
+tags:
+  - {name: pets, description: Pets waiting for a home.}
+  - {name: owners}
+securityDefinitions:
+  key: {type: apiKey, name: X-Api-Key, in: header}
+  oauth: {type: oauth2, flow: implicit, authorizationUrl: "https://shelter.example.com/auth", scopes: {"read:pets": Read}}
+security:
+  - key: []
+parameters:
+  trace: {name: X-Trace, in: header, type: string, required: true}
+paths:
+  /pets:
+    get:
+      deprecated: true
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat]}
+        - $ref: "#/parameters/trace"
+        - {name: limit, in: query, type: integer, required: true}
+      security:
+        - oauth: ["read:pets"]
+      responses:
+        200:
+          description: The pets.
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+        default: {description: An error.}
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, type: string, format: uuid}
+      responses:
+        200: {description: The pet., schema: {$ref: "#/definitions/Pet"}}
+  /owners:
+    get:
+      responses:
+        200: {description: The owners.}
+definitions:
+  Pet:
+    type: object
+    required: [name, tag]
+    properties:
+      name: {type: string}
+      tag: {type: string}
+      owner:
+        type: object
+        properties:
+          email: {type: string}
+`
+
+func TestDiff(t *testing.T) {
+	old, err := LoadYaml([]byte(diffOld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := LoadYaml([]byte(diffNew))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := Diff(old, new)
+	want := []string{
+		"version changed from 1.0 to 2.0",
+		"base path changed from /v1 to /v2",
+		`produces changed from ["application/json","application/xml"] to ["application/json"]`,
+		"tag `owners` was added",
+		"description of tag `pets` changed",
+		"GET /owners was added",
+		"GET /pets became deprecated",
+		"GET /pets: enum of parameter `kind` changed from [\"cat\",\"dog\"] to [\"cat\"]",
+		"GET /pets: parameter `limit` became required",
+		"GET /pets: maximum of parameter `limit` was removed",
+		"GET /pets: response default was added",
+		"security of GET /pets changed from the default to oauth (read:pets)",
+		"DELETE /pets was removed",
+		"GET /pets/{petId}: type of parameter `petId` changed from integer to string",
+		"GET /pets/{petId}: format of parameter `petId` was set to uuid",
+		"definition `Error` was removed",
+		"definition `Pet`: property `owner.email` was added",
+		"definition `Pet`: property `owner.name` was removed",
+		"definition `Pet`: property `tag` became required",
+		"shared parameter `trace` became required",
+		"name of security definition `key` changed from X-Key to X-Api-Key",
+		"security definition `oauth`: scope `write:pets` was removed",
+	}
+	if text := changes.String(); text != strings.Join(want, "\n") {
+		t.Errorf("unexpected changes:\n%s", text)
+	}
+	for _, c := range changes {
+		if c.Text == "GET /pets: parameter `limit` became required" {
+			if c.Kind != Changed || c.Pointer != "/paths/~1pets/get/parameters/2/required" || c.Path != "/pets" || c.Method != "get" || c.Old != false || c.New != true {
+				t.Errorf("unexpected change %+v", c)
+			}
+		}
+		if c.Text == "DELETE /pets was removed" && (c.Kind != Removed || c.Pointer != "/paths/~1pets/delete" || c.Old == nil || c.New != nil) {
+			t.Errorf("unexpected change %+v", c)
+		}
+	}
+	if _, err := json.Marshal(changes); err != nil {
+		t.Error(err)
+	}
+	if same := Diff(old, old); len(same) != 0 {
+		t.Errorf("unexpected changes:\n%s", same)
+	}
+}
+
+func TestDiffFormats(t *testing.T) {
+	files, err := getTestFiles("yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		old, err := LoadYaml(buf)
+		if err != nil {
+			t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+		}
+		if buf, err = old.Json(); err != nil {
+			t.Fatal(err)
+		}
+		new, err := LoadJson(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changes := Diff(old, new); len(changes) != 0 {
+			t.Errorf("%s: the same document loaded from YAML and JSON differs:\n%s", file, changes)
+		}
+	}
+}
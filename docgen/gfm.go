@@ -0,0 +1,598 @@
+package docgen
+
+import (
+	"bytes"
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The renderer below turns the GitHub Flavored Markdown of descriptions into HTML. It handles headings, paragraphs,
+// block quotes, lists and task lists, fenced and indented code, tables, thematic breaks, emphasis, strikethrough,
+// code spans, links, images and autolinks. Raw HTML is escaped and shown as text, so a description cannot add
+// scripts or styles to the page.
+
+var (
+	fenceLine    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	atxHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextLine   = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	breakLine    = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	quoteLine    = regexp.MustCompile(`^ {0,3}> ?`)
+	itemLine     = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	delimiterRow = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	taskBox      = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	entity       = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	emailLink    = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+	uriLink      = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	bareLink     = regexp.MustCompile(`^(?:https?://|www\.)[^\s<]*`)
+)
+
+// gfm renders Markdown text as HTML blocks
+func gfm(text string) template.HTML {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimRight(text, "\n"), "\r\n", "\n"), "\n")
+	var b bytes.Buffer
+	blocks(&b, lines, false)
+	return template.HTML(b.String())
+}
+
+// gfmInline renders a line of Markdown text as HTML, without a paragraph around it
+func gfmInline(text string) template.HTML {
+	var b bytes.Buffer
+	inlines(&b, strings.TrimSpace(text))
+	return template.HTML(b.String())
+}
+
+// blocks renders lines of Markdown as HTML blocks. In a tight list, paragraphs are written without tags.
+func blocks(b *bytes.Buffer, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := strings.ReplaceAll(lines[i], "\t", "    ")
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fenceLine.MatchString(line):
+			i = fenced(b, lines, i)
+		case atxHeading.MatchString(line):
+			m := atxHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">")
+			inlines(b, strings.TrimSpace(m[2]))
+			b.WriteString("</h" + level + ">\n")
+			i++
+		case breakLine.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case quoteLine.MatchString(line):
+			j := i
+			quoted := make([]string, 0)
+			for ; j < len(lines) && quoteLine.MatchString(lines[j]); j++ {
+				quoted = append(quoted, quoteLine.ReplaceAllString(lines[j], ""))
+			}
+			b.WriteString("<blockquote>\n")
+			blocks(b, quoted, false)
+			b.WriteString("</blockquote>\n")
+			i = j
+		case itemLine.MatchString(line):
+			i = list(b, lines, i)
+		case strings.HasPrefix(line, "    "):
+			j := i
+			code := make([]string, 0)
+			for ; j < len(lines) && (strings.HasPrefix(lines[j], "    ") || strings.HasPrefix(lines[j], "\t") || strings.TrimSpace(lines[j]) == ""); j++ {
+				l := strings.ReplaceAll(lines[j], "\t", "    ")
+				if len(l) >= 4 {
+					l = l[4:]
+				} else {
+					l = ""
+				}
+				code = append(code, l)
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+			i = j
+		case i+1 < len(lines) && strings.Contains(line, "|") && delimiterRow.MatchString(lines[i+1]) &&
+			len(cells(line)) == len(cells(lines[i+1])):
+			i = table(b, lines, i)
+		default:
+			i = paragraph(b, lines, i, tight)
+		}
+	}
+}
+
+// fenced renders a fenced code block starting at line i and returns the line after it
+func fenced(b *bytes.Buffer, lines []string, i int) int {
+	m := fenceLine.FindStringSubmatch(lines[i])
+	indent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+	code := make([]string, 0)
+	j := i + 1
+	for ; j < len(lines); j++ {
+		t := strings.TrimSpace(lines[j])
+		if strings.HasPrefix(t, m[1]) && strings.Trim(t, m[1][:1]) == "" {
+			j++
+			break
+		}
+		l := lines[j]
+		for k := 0; k < indent && strings.HasPrefix(l, " "); k++ {
+			l = l[1:]
+		}
+		code = append(code, l)
+	}
+	b.WriteString("<pre><code")
+	if m[2] != "" {
+		b.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+	}
+	b.WriteString(">")
+	if len(code) > 0 {
+		b.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return j
+}
+
+// list renders the items of a list starting at line i and returns the line after it
+func list(b *bytes.Buffer, lines []string, i int) int {
+	first := itemLine.FindStringSubmatch(lines[i])
+	bullet := first[2][len(first[2])-1:]
+	ordered := bullet == "." || bullet == ")"
+	items := make([][]string, 0)
+	tight := true
+	j := i
+	for j < len(lines) {
+		m := itemLine.FindStringSubmatch(lines[j])
+		if m == nil || m[2][len(m[2])-1:] != bullet || breakLine.MatchString(lines[j]) {
+			break
+		}
+		width := len(m[0])
+		if strings.TrimSpace(lines[j][width:]) == "" || len(m[3]) > 4 {
+			width = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{strings.TrimLeft(lines[j][len(m[0]):], " ")}
+		if len(m[3]) > 4 {
+			item[0] = lines[j][width:]
+		}
+		j++
+		for j < len(lines) {
+			l := strings.ReplaceAll(lines[j], "\t", "    ")
+			if strings.TrimSpace(l) == "" {
+				// a blank line continues the item only if an indented line follows
+				k := j
+				for k < len(lines) && strings.TrimSpace(lines[k]) == "" {
+					k++
+				}
+				if k == len(lines) || indentOf(lines[k]) < width {
+					break
+				}
+				tight = false
+				for ; j < k; j++ {
+					item = append(item, "")
+				}
+				continue
+			}
+			if indentOf(l) >= width {
+				item = append(item, l[width:])
+			} else if itemLine.MatchString(l) || breakLine.MatchString(l) || quoteLine.MatchString(l) ||
+				fenceLine.MatchString(l) || atxHeading.MatchString(l) || len(item) > 0 && strings.TrimSpace(item[len(item)-1]) == "" {
+				break
+			} else {
+				// a lazy continuation of the paragraph
+				item = append(item, strings.TrimSpace(l))
+			}
+			j++
+		}
+		items = append(items, item)
+		// a blank line between items makes the list loose
+		if j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			k := j
+			for k < len(lines) && strings.TrimSpace(lines[k]) == "" {
+				k++
+			}
+			if k < len(lines) {
+				if n := itemLine.FindStringSubmatch(lines[k]); n != nil && n[2][len(n[2])-1:] == bullet && !breakLine.MatchString(lines[k]) {
+					tight = false
+					j = k
+				}
+			}
+		}
+	}
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if start, _ := strconv.Atoi(first[2][:len(first[2])-1]); start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		if m := taskBox.FindStringSubmatch(item[0]); m != nil {
+			if m[1] == " " {
+				b.WriteString(`<input type="checkbox" disabled> `)
+			} else {
+				b.WriteString(`<input type="checkbox" checked disabled> `)
+			}
+			item[0] = item[0][len(m[0]):]
+		}
+		var inner bytes.Buffer
+		blocks(&inner, item, tight)
+		b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return j
+}
+
+// indentOf returns the number of spaces a line starts with, counting a tab as four
+func indentOf(line string) int {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// table renders a table whose header is at line i and returns the line after it
+func table(b *bytes.Buffer, lines []string, i int) int {
+	header := cells(lines[i])
+	aligns := make([]string, len(header))
+	for k, c := range cells(lines[i+1]) {
+		switch left, right := strings.HasPrefix(c, ":"), strings.HasSuffix(c, ":"); {
+		case left && right:
+			aligns[k] = ` align="center"`
+		case left:
+			aligns[k] = ` align="left"`
+		case right:
+			aligns[k] = ` align="right"`
+		}
+	}
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for k, c := range header {
+		b.WriteString("<th" + aligns[k] + ">")
+		inlines(b, c)
+		b.WriteString("</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n")
+	j := i + 2
+	if j < len(lines) && strings.TrimSpace(lines[j]) != "" {
+		b.WriteString("<tbody>\n")
+		for ; j < len(lines) && strings.TrimSpace(lines[j]) != "" && !quoteLine.MatchString(lines[j]) && !fenceLine.MatchString(lines[j]); j++ {
+			row := cells(lines[j])
+			b.WriteString("<tr>")
+			for k := range header {
+				b.WriteString("<td" + aligns[k] + ">")
+				if k < len(row) {
+					inlines(b, row[k])
+				}
+				b.WriteString("</td>")
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+	return j
+}
+
+// cells splits a table row into its cells, keeping escaped pipes
+func cells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	out := make([]string, 0)
+	start := 0
+	for k := 0; k < len(line); k++ {
+		switch line[k] {
+		case '\\':
+			k++
+		case '|':
+			out = append(out, strings.TrimSpace(strings.ReplaceAll(line[start:k], "\\|", "|")))
+			start = k + 1
+		}
+	}
+	return append(out, strings.TrimSpace(strings.ReplaceAll(line[start:], "\\|", "|")))
+}
+
+// paragraph renders the paragraph starting at line i, or a setext heading, and returns the line after it
+func paragraph(b *bytes.Buffer, lines []string, i int, tight bool) int {
+	j := i
+	text := make([]string, 0)
+	for ; j < len(lines); j++ {
+		l := lines[j]
+		if strings.TrimSpace(l) == "" {
+			break
+		}
+		if j > i {
+			if m := setextLine.FindStringSubmatch(l); m != nil {
+				level := "1"
+				if m[1][0] == '-' {
+					level = "2"
+				}
+				b.WriteString("<h" + level + ">")
+				inlines(b, strings.TrimSpace(strings.Join(text, "\n")))
+				b.WriteString("</h" + level + ">\n")
+				return j + 1
+			}
+			if breakLine.MatchString(l) || quoteLine.MatchString(l) || fenceLine.MatchString(l) || atxHeading.MatchString(l) {
+				break
+			}
+			if m := itemLine.FindStringSubmatch(l); m != nil && strings.TrimSpace(l[len(m[0]):]) != "" &&
+				(len(m[2]) == 1 || strings.HasPrefix(m[2], "1")) {
+				break
+			}
+		}
+		text = append(text, strings.TrimLeft(l, " \t"))
+	}
+	if !tight {
+		b.WriteString("<p>")
+	}
+	inlines(b, strings.TrimRight(strings.Join(text, "\n"), " \t"))
+	if !tight {
+		b.WriteString("</p>")
+	}
+	b.WriteString("\n")
+	return j
+}
+
+// inlines renders the spans of a block of text
+func inlines(b *bytes.Buffer, s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '\n':
+			if strings.HasSuffix(s[:i], "  ") {
+				trimmed := bytes.TrimRight(b.Bytes(), " ")
+				b.Truncate(len(trimmed))
+				b.WriteString("<br>")
+			}
+			b.WriteString("\n")
+			i++
+		case c == '`':
+			i = codeSpan(b, s, i)
+		case c == '*' || c == '_' || c == '~':
+			i = emphasis(b, s, i)
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if n := linkSpan(b, s, i+1, true); n > i+1 {
+				i = n
+			} else {
+				b.WriteString("!")
+				i++
+			}
+		case c == '[':
+			if n := linkSpan(b, s, i, false); n > i {
+				i = n
+			} else {
+				b.WriteString("[")
+				i++
+			}
+		case c == '<':
+			if m := uriLink.FindStringSubmatch(s[i:]); m != nil {
+				writeLink(b, m[1], html.EscapeString(m[1]))
+				i += len(m[0])
+			} else if m := emailLink.FindStringSubmatch(s[i:]); m != nil {
+				writeLink(b, "mailto:"+m[1], html.EscapeString(m[1]))
+				i += len(m[0])
+			} else {
+				b.WriteString("&lt;")
+				i++
+			}
+		case c == '&':
+			if m := entity.FindString(s[i:]); m != "" {
+				b.WriteString(html.EscapeString(html.UnescapeString(m)))
+				i += len(m)
+			} else {
+				b.WriteString("&amp;")
+				i++
+			}
+		case (c == 'h' || c == 'w') && (i == 0 || strings.IndexByte(" \t\n*_~(", s[i-1]) >= 0) && bareLink.MatchString(s[i:]):
+			url := trimLink(bareLink.FindString(s[i:]))
+			href := url
+			if strings.HasPrefix(url, "www.") {
+				href = "http://" + url
+			}
+			writeLink(b, href, html.EscapeString(url))
+			i += len(url)
+		default:
+			b.WriteString(html.EscapeString(s[i : i+1]))
+			i++
+		}
+	}
+}
+
+// trimLink drops the trailing punctuation and unbalanced closing parentheses of a bare link
+func trimLink(url string) string {
+	for len(url) > 0 {
+		last := url[len(url)-1]
+		if strings.IndexByte("?!.,:*_~'\"", last) >= 0 {
+			url = url[:len(url)-1]
+		} else if last == ')' && strings.Count(url, ")") > strings.Count(url, "(") {
+			url = url[:len(url)-1]
+		} else {
+			break
+		}
+	}
+	return url
+}
+
+// codeSpan renders the code span starting at i and returns the index after it
+func codeSpan(b *bytes.Buffer, s string, i int) int {
+	n := i
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	ticks := s[i:n]
+	for k := n; k < len(s); {
+		end := strings.Index(s[k:], ticks)
+		if end < 0 {
+			break
+		}
+		end += k
+		if end+len(ticks) < len(s) && s[end+len(ticks)] == '`' {
+			// a longer run of backticks does not close the span
+			for k = end; k < len(s) && s[k] == '`'; k++ {
+			}
+			continue
+		}
+		code := strings.ReplaceAll(s[n:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+		return end + len(ticks)
+	}
+	b.WriteString(ticks)
+	return n
+}
+
+// emphasis renders the emphasis, strong emphasis or strikethrough starting at i and returns the index after it
+func emphasis(b *bytes.Buffer, s string, i int) int {
+	c := s[i]
+	n := i
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	run := n - i
+	// an underscore inside a word is not emphasis, and a delimiter followed by a space does not open any
+	if n == len(s) || s[n] == ' ' || s[n] == '\n' || c == '_' && i > 0 && isWord(s[i-1]) || c == '~' && run != 2 {
+		b.WriteString(s[i:n])
+		return n
+	}
+	tags := map[int][]string{1: {"em"}, 2: {"strong"}, 3: {"strong", "em"}}[run]
+	if c == '~' {
+		tags = []string{"del"}
+	}
+	if tags == nil {
+		b.WriteString(s[i:n])
+		return n
+	}
+	delim := s[i:n]
+	for k := n; k < len(s); {
+		end := strings.Index(s[k:], delim)
+		if end < 0 {
+			break
+		}
+		end += k
+		after := end + run
+		if s[end-1] == ' ' || s[end-1] == '\n' || after < len(s) && s[after] == c || c == '_' && after < len(s) && isWord(s[after]) {
+			k = end + 1
+			for k < len(s) && s[k] == c {
+				k++
+			}
+			continue
+		}
+		for _, t := range tags {
+			b.WriteString("<" + t + ">")
+		}
+		inlines(b, s[n:end])
+		for k := len(tags) - 1; k >= 0; k-- {
+			b.WriteString("</" + tags[k] + ">")
+		}
+		return after
+	}
+	b.WriteString(s[i:n])
+	return n
+}
+
+// isWord tells whether a byte is part of a word
+func isWord(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// linkSpan renders the link or image whose text starts with the bracket at i and returns the index after it, or i
+// if there is no link
+func linkSpan(b *bytes.Buffer, s string, i int, image bool) int {
+	depth := 0
+	close := -1
+	for k := i; k < len(s) && close < 0; k++ {
+		switch s[k] {
+		case '\\':
+			k++
+		case '`':
+			// brackets inside code spans do not count
+			if end := strings.IndexByte(s[k+1:], '`'); end >= 0 {
+				k += end + 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				close = k
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return i
+	}
+	depth = 0
+	end := -1
+	for k := close + 1; k < len(s) && end < 0; k++ {
+		switch s[k] {
+		case '\\':
+			k++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = k
+			}
+		}
+	}
+	if end < 0 {
+		return i
+	}
+	dest, title := strings.TrimSpace(s[close+2:end]), ""
+	if k := strings.IndexAny(dest, " \t\n"); k >= 0 {
+		title = strings.TrimSpace(dest[k:])
+		dest = dest[:k]
+		if len(title) < 2 || !(title[0] == '"' && title[len(title)-1] == '"' || title[0] == '\'' && title[len(title)-1] == '\'') {
+			return i
+		}
+		title = title[1 : len(title)-1]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	text := s[i+1 : close]
+	if image {
+		b.WriteString(`<img src="` + html.EscapeString(safeURL(dest)) + `" alt="` + html.EscapeString(text) + `"`)
+		if title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(">")
+		return end + 1
+	}
+	var inner bytes.Buffer
+	inlines(&inner, text)
+	b.WriteString(`<a href="` + html.EscapeString(safeURL(dest)) + `"`)
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString(">" + inner.String() + "</a>")
+	return end + 1
+}
+
+// writeLink writes a link whose text is already HTML
+func writeLink(b *bytes.Buffer, url, text string) {
+	b.WriteString(`<a href="` + html.EscapeString(safeURL(url)) + `">` + text + "</a>")
+}
+
+// safeURL keeps relative links and those with the http, https, mailto or ftp schemes, and replaces the others,
+// such as javascript: links, with a link to nowhere
+func safeURL(url string) string {
+	if k := strings.IndexAny(url, ":/?#"); k >= 0 && url[k] == ':' {
+		switch strings.ToLower(url[:k]) {
+		case "http", "https", "mailto", "ftp":
+		default:
+			return "#"
+		}
+	}
+	return url
+}
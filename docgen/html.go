@@ -0,0 +1,289 @@
+package docgen
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// HTML renders a document as a single HTML page that needs nothing else to be shown, not even a network
+// connection: the styles are part of the page and it has no scripts. A sidebar links to the tags and their
+// operations, each operation has an anchor, the properties of schemas are shown as trees that can be collapsed, and
+// descriptions are rendered from GitHub Flavored Markdown.
+func HTML(swag *swagger2.Swagger) ([]byte, error) {
+	var b bytes.Buffer
+	if err := page.Execute(&b, build(swag)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// node is a property of a schema tree, with the properties of the object it holds
+type node struct {
+	*property
+	Name     string
+	Children []*node
+}
+
+// tree arranges the properties of an expanded schema by their paths
+func tree(props []*property) []*node {
+	roots := make([]*node, 0)
+	stack := make([]*node, 0)
+	for _, p := range props {
+		parent, name := "", p.Path
+		if k := strings.LastIndex(p.Path, "."); k >= 0 {
+			parent, name = p.Path[:k], p.Path[k+1:]
+		}
+		for strings.HasSuffix(parent, "[]") || strings.HasSuffix(parent, "{}") {
+			parent = parent[:len(parent)-2]
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Path != parent {
+			stack = stack[:len(stack)-1]
+		}
+		n := &node{property: p, Name: name}
+		if len(stack) == 0 {
+			roots = append(roots, n)
+		} else {
+			top := stack[len(stack)-1]
+			top.Children = append(top.Children, n)
+		}
+		stack = append(stack, n)
+	}
+	return roots
+}
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"md":     gfm,
+	"inline": gfmInline,
+	"tree":   tree,
+	"slug":   slug,
+	"lower":  strings.ToLower,
+}).Parse(pageTemplate))
+
+const pageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{with .Title}}{{.}}{{else}}API reference{{end}}</title>
+<style>` + pageStyle + `</style>
+</head>
+<body>
+<nav>
+<a class="title" href="#{{slug .Title}}">{{with .Title}}{{.}}{{else}}API reference{{end}}</a>
+<ul>
+{{- if .Schemes}}
+<li><a href="#security">Security</a></li>
+{{- end}}
+{{- range .Groups}}
+<li><a href="#{{.Slug}}">{{.Name}}</a>
+<ul>
+{{- range .Operations}}
+<li><a href="#{{.Slug}}"{{if .Deprecated}} class="deprecated"{{end}}><span class="method {{lower .Method}}">{{.Method}}</span> {{.Path}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+</ul>
+</nav>
+<main>
+<header>
+<h1 id="{{slug .Title}}">{{with .Title}}{{.}}{{else}}API reference{{end}}</h1>
+{{- with .Version}}
+<p class="version">Version {{.}}</p>
+{{- end}}
+{{with .Description}}{{md .}}{{end}}
+<dl>
+{{- with .BaseURL}}
+<dt>Base URL</dt><dd><code>{{.}}</code></dd>
+{{- end}}
+{{- with .TermsOfService}}
+<dt>Terms of service</dt><dd><a href="{{.}}">{{.}}</a></dd>
+{{- end}}
+{{- with .Contact}}{{if or .Name .Url .Email}}
+<dt>Contact</dt><dd>{{if .Url}}<a href="{{.Url}}">{{with .Name}}{{.}}{{else}}{{.Url}}{{end}}</a>{{else}}{{.Name}}{{end}}{{with .Email}} <a href="mailto:{{.}}">{{.}}</a>{{end}}</dd>
+{{- end}}{{end}}
+{{- with .License}}{{if or .Name .Url}}
+<dt>License</dt><dd>{{if .Url}}<a href="{{.Url}}">{{with .Name}}{{.}}{{else}}{{.Url}}{{end}}</a>{{else}}{{.Name}}{{end}}</dd>
+{{- end}}{{end}}
+{{- with .ExternalDocs}}{{if .Url}}
+<dt>More documentation</dt><dd>{{template "docs" .}}</dd>
+{{- end}}{{end}}
+</dl>
+</header>
+{{- if .Schemes}}
+<section id="security">
+<h2><a class="anchor" href="#security">#</a> Security</h2>
+{{- with .Security}}
+<p>Unless an operation says otherwise, the API requires {{inline .}}.</p>
+{{- end}}
+{{- range .Schemes}}
+<h3>{{.Name}}</h3>
+<p>{{inline .Kind}}</p>
+{{with .Description}}{{md .}}{{end}}
+{{- if .Scopes}}
+<table>
+<thead><tr><th>Scope</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Scopes}}
+<tr><td><code>{{.Name}}</code></td><td>{{inline .Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+{{- range .Groups}}
+<section id="{{.Slug}}">
+<h2><a class="anchor" href="#{{.Slug}}">#</a> {{.Name}}</h2>
+{{with .Description}}{{md .}}{{end}}
+{{- with .ExternalDocs}}{{if .Url}}
+<p>More documentation: {{template "docs" .}}</p>
+{{- end}}{{end}}
+{{- range .Operations}}
+{{template "operation" .}}
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "docs"}}<a href="{{.Url}}">{{with .Description}}{{.}}{{else}}{{.Url}}{{end}}</a>{{end}}
+{{define "operation"}}<article id="{{.Slug}}" class="operation{{if .Deprecated}} deprecated{{end}}">
+<h3><a class="anchor" href="#{{.Slug}}">#</a> <span class="method {{lower .Method}}">{{.Method}}</span> <span class="path">{{.Path}}</span>{{if .Deprecated}} <span class="badge">Deprecated</span>{{end}}</h3>
+{{- with .Summary}}
+<p class="summary">{{inline .}}</p>
+{{- end}}
+{{- if and .Description (ne .Description .Summary)}}
+{{md .Description}}
+{{- end}}
+{{- with .ExternalDocs}}{{if .Url}}
+<p>More documentation: {{template "docs" .}}</p>
+{{- end}}{{end}}
+{{- with .Security}}
+<p>Security: {{inline .}}</p>
+{{- end}}
+{{- if .Parameters}}
+<h4>Parameters</h4>
+<table>
+<thead><tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Parameters}}
+<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td><code>{{.Type}}</code></td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{inline .Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- with .Body}}
+<h4>Request body</h4>
+{{- with $.Consumes}}
+<p>Content type: {{range $i, $c := .}}{{if $i}}, {{end}}<code>{{$c}}</code>{{end}}</p>
+{{- end}}
+{{- if .Required}}
+<p><span class="required">Required.</span></p>
+{{- end}}
+{{- with .Description}}
+<p>{{inline .}}</p>
+{{- end}}
+{{template "schema" .Schema}}
+{{- end}}
+{{- if .Responses}}
+<h4>Responses</h4>
+{{- with .Produces}}
+<p>Content type: {{range $i, $c := .}}{{if $i}}, {{end}}<code>{{$c}}</code>{{end}}</p>
+{{- end}}
+{{- range .Responses}}
+<details class="response"{{if eq (slice .Status 0 1) "2"}} open{{end}}>
+<summary><span class="status">{{.Status}}</span> {{inline .Description}}</summary>
+{{- with .Schema}}
+{{template "schema" .}}
+{{- end}}
+{{- if .Headers}}
+<table>
+<thead><tr><th>Header</th><th>Type</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Headers}}
+<tr><td><code>{{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{inline .Description}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- range .Examples}}
+<p>Example (<code>{{.MediaType}}</code>):</p>
+<pre><code>{{.Text}}</code></pre>
+{{- end}}
+</details>
+{{- end}}
+{{- end}}
+</article>{{end}}
+{{define "schema"}}<div class="schema">
+<p>Type: <code>{{.Type}}</code></p>
+{{- if .Properties}}
+<ul class="tree">
+{{- range tree .Properties}}
+{{template "node" .}}
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Example}}
+<p>Example:</p>
+<pre><code>{{.}}</code></pre>
+{{- end}}
+</div>{{end}}
+{{define "node"}}{{if .Children}}<li><details open><summary>{{template "property" .}}</summary>
+<ul class="tree">
+{{- range .Children}}
+{{template "node" .}}
+{{- end}}
+</ul>
+</details></li>{{else}}<li>{{template "property" .}}</li>{{end}}{{end}}
+{{define "property"}}<code class="name">{{.Name}}</code> <code class="type">{{.Type}}</code>{{if .Required}} <span class="required">required</span>{{end}}{{with .Description}} <span class="description">{{inline .}}</span>{{end}}{{end}}`
+
+const pageStyle = `
+body { margin: 0; font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 18rem; overflow-y: auto; padding: 1rem; box-sizing: border-box; background: #f6f8fa; border-right: 1px solid #d0d7de; font-size: 13px; }
+nav ul { list-style: none; margin: 0; padding: 0; }
+nav ul ul { margin: 0.25rem 0 0.75rem 0.5rem; }
+nav a { color: inherit; text-decoration: none; display: block; padding: 0.1rem 0; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+nav a:hover { color: #0969da; }
+nav .title { font-weight: 600; font-size: 15px; margin-bottom: 1rem; }
+main { margin-left: 18rem; padding: 1rem 2rem 4rem; max-width: 60rem; }
+a { color: #0969da; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 85%; }
+code { background: #eff1f3; padding: 0.1em 0.3em; border-radius: 4px; }
+pre { background: #f6f8fa; padding: 0.75rem 1rem; border-radius: 6px; overflow-x: auto; }
+pre code { background: none; padding: 0; font-size: 100%; }
+table { border-collapse: collapse; margin: 0.5rem 0 1rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.25rem 1rem; }
+dt { font-weight: 600; }
+dd { margin: 0; }
+section { border-top: 1px solid #d0d7de; margin-top: 2rem; }
+article { margin: 1.5rem 0 2.5rem; }
+.anchor { color: #8c959f; text-decoration: none; visibility: hidden; }
+h2:hover .anchor, h3:hover .anchor { visibility: visible; }
+.method { display: inline-block; min-width: 3.5rem; padding: 0 0.3rem; border-radius: 4px; color: #fff; font-size: 11px; font-weight: 600; text-align: center; background: #6e7781; }
+.method.get { background: #1f883d; }
+.method.post { background: #0969da; }
+.method.put { background: #9a6700; }
+.method.patch { background: #8250df; }
+.method.delete { background: #cf222e; }
+.path { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+.deprecated .path, nav a.deprecated { text-decoration: line-through; }
+.badge { font-size: 12px; font-weight: 600; color: #9a6700; border: 1px solid #d4a72c; border-radius: 1em; padding: 0 0.5em; }
+.required { color: #cf222e; font-size: 12px; }
+.response { margin: 0.5rem 0; padding: 0.25rem 0.75rem; border: 1px solid #d0d7de; border-radius: 6px; }
+.response > summary { cursor: pointer; }
+.status { font-weight: 600; }
+.tree { list-style: none; padding-left: 1.25rem; margin: 0.25rem 0; }
+.tree summary { cursor: pointer; }
+.tree li { margin: 0.15rem 0; }
+.tree .type { background: none; color: #57606a; }
+.description { color: #57606a; }
+@media (max-width: 50rem) { nav { position: static; width: auto; border-right: none; } main { margin-left: 0; padding: 1rem; } }
+@media print { nav { display: none; } main { margin-left: 0; } }
+`
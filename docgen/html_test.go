@@ -0,0 +1,72 @@
+package docgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestHTML(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	swag.Info.Description = "Adopt a **pet**.\n\n<script>alert(1)</script>"
+	b, err := HTML(swag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, want := range []string{
+		"<title>Pet Shelter</title>",
+		`<li><a href="#pets">pets</a>`,
+		`<li><a href="#get-pets"><span class="method get">GET</span> /pets</a></li>`,
+		`<li><a href="#delete-petsid" class="deprecated"><span class="method delete">DELETE</span> /pets/{id}</a></li>`,
+		"<p>Adopt a <strong>pet</strong>.</p>\n<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		"<p>Unless an operation says otherwise, the API requires <code>key</code>.</p>",
+		`<article id="get-pets" class="operation">`,
+		`<span class="path">/pets/{id}</span> <span class="badge">Deprecated</span>`,
+		"<td>One of <code>cat</code>, <code>dog</code>.</td>",
+		"<li><details open><summary><code class=\"name\">owner</code> <code class=\"type\">object</code></summary>\n<ul class=\"tree\">\n<li><code class=\"name\">name</code> <code class=\"type\">string</code></li>\n</ul>\n</details></li>",
+		`<code class="name">name</code> <code class="type">string</code> <span class="required">required</span>`,
+		"<p><span class=\"required\">Required.</span></p>\n<p>Pet to add to the store</p>",
+		"<summary><span class=\"status\">204</span> Gone | removed.</summary>",
+		"<pre><code>{\n  &#34;name&#34;: &#34;Rex&#34;\n}</code></pre>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, `<article id="post-adoptions"`) != 1 || !strings.Contains(out, `<article id="post-adoptions-1"`) {
+		t.Errorf("expected an anchor for each tag of POST /adoptions in:\n%s", out)
+	}
+	for _, unwanted := range []string{"<script", "http://", "https://cdn"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("unexpected %q in:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestGFM(t *testing.T) {
+	for _, c := range []struct{ in, out string }{
+		{"# Title #", "<h1>Title</h1>\n"},
+		{"Title\n===", "<h1>Title</h1>\n"},
+		{"*a* **b** ***c*** ~~d~~ `e|f` snake_case", "<p><em>a</em> <strong>b</strong> <strong><em>c</em></strong> <del>d</del> <code>e|f</code> snake_case</p>\n"},
+		{"one  \ntwo\\\nthree\nfour", "<p>one<br>\ntwo<br>\nthree\nfour</p>\n"},
+		{"a < b & c &amp; \\*d\\*", "<p>a &lt; b &amp; c &amp; *d*</p>\n"},
+		{"- a\n- b\n  - c\n- [x] d", "<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul></li>\n<li><input type=\"checkbox\" checked disabled> d</li>\n</ul>\n"},
+		{"3. a\n\n4. b", "<ol start=\"3\">\n<li><p>a</p></li>\n<li><p>b</p></li>\n</ol>\n"},
+		{"> a\n> b", "<blockquote>\n<p>a\nb</p>\n</blockquote>\n"},
+		{"```json\n{\"a\": \"<b>\"}\n```", "<pre><code class=\"language-json\">{&#34;a&#34;: &#34;&lt;b&gt;&#34;}\n</code></pre>\n"},
+		{"    code\n\ntext", "<pre><code>code\n</code></pre>\n<p>text</p>\n"},
+		{"| A | B |\n|:--|--:|\n| 1 \\| 2 | x |", "<table>\n<thead>\n<tr><th align=\"left\">A</th><th align=\"right\">B</th></tr>\n</thead>\n<tbody>\n<tr><td align=\"left\">1 | 2</td><td align=\"right\">x</td></tr>\n</tbody>\n</table>\n"},
+		{"[a *b*](http://x.org \"T\") ![i](p.png) [c](javascript:alert(1))", "<p><a href=\"http://x.org\" title=\"T\">a <em>b</em></a> <img src=\"p.png\" alt=\"i\"> <a href=\"#\">c</a></p>\n"},
+		{"See https://x.org/a_(b). <me@x.org> www.x.org", "<p>See <a href=\"https://x.org/a_(b)\">https://x.org/a_(b)</a>. <a href=\"mailto:me@x.org\">me@x.org</a> <a href=\"http://www.x.org\">www.x.org</a></p>\n"},
+		{"a\n\n---\n\n<b>x</b>", "<p>a</p>\n<hr>\n<p>&lt;b&gt;x&lt;/b&gt;</p>\n"},
+	} {
+		if got := string(gfm(c.in)); got != c.out {
+			t.Errorf("%q: expected\n%s\ngot\n%s", c.in, c.out, got)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// IndexFile is the name of the page MarkdownByTag writes the information and security of the API to.
+const IndexFile = "README.md"
+
+// Markdown renders a document as a single Markdown page: the information about the API, its security schemes,
+// and the operations grouped by tag in the order the tags are declared. Each operation lists its parameters and
+// responses, with the properties of their schemas expanded from the definitions they refer to.
+func Markdown(swag *swagger2.Swagger) []byte {
+	d := build(swag)
+	var b bytes.Buffer
+	writeInfo(&b, d)
+	writeContents(&b, d.Groups, "")
+	writeSecurity(&b, d)
+	for _, g := range d.Groups {
+		writeGroup(&b, g, 2)
+	}
+	return trim(b.Bytes())
+}
+
+// MarkdownByTag renders a document as a page for each tag, named after the tag, and an index page named
+// IndexFile with the information about the API and its security schemes that links to them.
+func MarkdownByTag(swag *swagger2.Swagger) map[string][]byte {
+	d := build(swag)
+	files := make(map[string][]byte)
+	var b bytes.Buffer
+	writeInfo(&b, d)
+	b.WriteString("## Contents\n\n")
+	for _, g := range d.Groups {
+		fmt.Fprintf(&b, "- [%s](%s.md)\n", inline(g.Name), g.Slug)
+	}
+	b.WriteString("\n")
+	writeSecurity(&b, d)
+	files[IndexFile] = trim(b.Bytes())
+	for _, g := range d.Groups {
+		var page bytes.Buffer
+		writeGroup(&page, g, 1)
+		files[g.Slug+".md"] = trim(page.Bytes())
+	}
+	return files
+}
+
+// trim ends a page with a single newline
+func trim(b []byte) []byte {
+	return append(bytes.TrimRight(b, "\n"), '\n')
+}
+
+// writeInfo writes the title, description and details of the API
+func writeInfo(b *bytes.Buffer, d *document) {
+	title := d.Title
+	if title == "" {
+		title = "API reference"
+	}
+	fmt.Fprintf(b, "# %s\n\n", inline(title))
+	if d.Version != "" {
+		fmt.Fprintf(b, "Version %s\n\n", inline(d.Version))
+	}
+	if d.Description != "" {
+		b.WriteString(d.Description + "\n\n")
+	}
+	details := make([]string, 0)
+	if d.BaseURL != "" {
+		details = append(details, "Base URL: `"+d.BaseURL+"`")
+	}
+	if d.TermsOfService != "" {
+		details = append(details, "Terms of service: "+d.TermsOfService)
+	}
+	if c := d.Contact; c != nil && (c.Name != "" || c.Url != "" || c.Email != "") {
+		text := link(c.Name, c.Url)
+		if c.Email != "" {
+			text = strings.TrimSpace(text + " <" + c.Email + ">")
+		}
+		details = append(details, "Contact: "+text)
+	}
+	if l := d.License; l != nil && (l.Name != "" || l.Url != "") {
+		details = append(details, "License: "+link(l.Name, l.Url))
+	}
+	if x := d.ExternalDocs; x != nil && x.Url != "" {
+		details = append(details, "More documentation: "+link(x.Description, x.Url))
+	}
+	for _, line := range details {
+		b.WriteString("- " + line + "\n")
+	}
+	if len(details) > 0 {
+		b.WriteString("\n")
+	}
+}
+
+// link writes a Markdown link, or just the URL if there is no text
+func link(text, url string) string {
+	switch {
+	case url == "":
+		return inline(text)
+	case text == "":
+		return url
+	}
+	return "[" + inline(text) + "](" + url + ")"
+}
+
+// writeContents lists the groups and their operations, linking to them in page
+func writeContents(b *bytes.Buffer, groups []*group, page string) {
+	if len(groups) == 0 {
+		return
+	}
+	b.WriteString("## Contents\n\n")
+	for _, g := range groups {
+		fmt.Fprintf(b, "- [%s](%s#%s)\n", inline(g.Name), page, g.Slug)
+		for _, o := range g.Operations {
+			fmt.Fprintf(b, "  - [%s %s](%s#%s)", o.Method, inline(o.Path), page, o.Slug)
+			if o.Summary != "" {
+				b.WriteString(" " + inline(firstLine(o.Summary)))
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeSecurity describes the security schemes
+func writeSecurity(b *bytes.Buffer, d *document) {
+	if len(d.Schemes) == 0 {
+		return
+	}
+	b.WriteString("## Security\n\n")
+	if d.Security != "" {
+		fmt.Fprintf(b, "Unless an operation says otherwise, the API requires %s.\n\n", d.Security)
+	}
+	for _, s := range d.Schemes {
+		fmt.Fprintf(b, "### %s\n\n%s\n\n", inline(s.Name), s.Kind)
+		if s.Description != "" {
+			b.WriteString(s.Description + "\n\n")
+		}
+		if len(s.Scopes) > 0 {
+			b.WriteString("| Scope | Description |\n| --- | --- |\n")
+			for _, sc := range s.Scopes {
+				fmt.Fprintf(b, "| `%s` | %s |\n", sc.Name, cell(sc.Description))
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+// writeGroup writes the operations of a tag under a heading of the given level
+func writeGroup(b *bytes.Buffer, g *group, level int) {
+	fmt.Fprintf(b, "%s %s\n\n", heading(level), inline(g.Name))
+	if g.Description != "" {
+		b.WriteString(g.Description + "\n\n")
+	}
+	if x := g.ExternalDocs; x != nil && x.Url != "" {
+		fmt.Fprintf(b, "More documentation: %s\n\n", link(x.Description, x.Url))
+	}
+	for _, o := range g.Operations {
+		writeOperation(b, o, level+1)
+	}
+}
+
+// writeOperation writes an operation under a heading of the given level
+func writeOperation(b *bytes.Buffer, o *operation, level int) {
+	title := o.Method + " " + inline(o.Path)
+	if o.Deprecated {
+		title = "~~" + title + "~~"
+	}
+	fmt.Fprintf(b, "%s %s\n\n", heading(level), title)
+	if o.Deprecated {
+		b.WriteString("**Deprecated.**\n\n")
+	}
+	if o.Summary != "" {
+		b.WriteString(o.Summary + "\n\n")
+	}
+	if o.Description != "" && o.Description != o.Summary {
+		b.WriteString(o.Description + "\n\n")
+	}
+	if x := o.ExternalDocs; x != nil && x.Url != "" {
+		fmt.Fprintf(b, "More documentation: %s\n\n", link(x.Description, x.Url))
+	}
+	if o.Security != "" {
+		fmt.Fprintf(b, "Security: %s\n\n", o.Security)
+	}
+	sub := heading(level + 1)
+	if len(o.Parameters) > 0 {
+		fmt.Fprintf(b, "%s Parameters\n\n", sub)
+		writeParameters(b, o.Parameters, true)
+	}
+	if o.Body != nil {
+		fmt.Fprintf(b, "%s Request body\n\n", sub)
+		if len(o.Consumes) > 0 {
+			fmt.Fprintf(b, "Content type: %s\n\n", codeList(o.Consumes))
+		}
+		if o.Body.Required {
+			b.WriteString("**Required.**\n\n")
+		}
+		if o.Body.Description != "" {
+			b.WriteString(o.Body.Description + "\n\n")
+		}
+		writeSchema(b, o.Body.Schema)
+	}
+	if len(o.Responses) > 0 {
+		fmt.Fprintf(b, "%s Responses\n\n", sub)
+		if len(o.Produces) > 0 {
+			fmt.Fprintf(b, "Content type: %s\n\n", codeList(o.Produces))
+		}
+		b.WriteString("| Status | Description | Type |\n| --- | --- | --- |\n")
+		for _, r := range o.Responses {
+			t := ""
+			if r.Schema != nil {
+				t = "`" + r.Schema.Type + "`"
+			}
+			fmt.Fprintf(b, "| %s | %s | %s |\n", r.Status, cell(r.Description), t)
+		}
+		b.WriteString("\n")
+		for _, r := range o.Responses {
+			writeResponse(b, r, level+2)
+		}
+	}
+}
+
+// writeParameters writes a table of parameters or headers
+func writeParameters(b *bytes.Buffer, params []*parameter, location bool) {
+	if location {
+		b.WriteString("| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n")
+	} else {
+		b.WriteString("| Name | Type | Description |\n| --- | --- | --- |\n")
+	}
+	for _, p := range params {
+		if location {
+			fmt.Fprintf(b, "| `%s` | %s | `%s` | %s | %s |\n", p.Name, p.In, p.Type, yesNo(p.Required), cell(p.Description))
+		} else {
+			fmt.Fprintf(b, "| `%s` | `%s` | %s |\n", p.Name, p.Type, cell(p.Description))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeResponse writes the details of a response that has a schema, headers or examples
+func writeResponse(b *bytes.Buffer, r *response, level int) {
+	if (r.Schema == nil || len(r.Schema.Properties) == 0 && r.Schema.Example == "") && len(r.Headers) == 0 && len(r.Examples) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s %s\n\n", heading(level), r.Status)
+	if r.Schema != nil {
+		writeSchema(b, r.Schema)
+	}
+	if len(r.Headers) > 0 {
+		b.WriteString("Headers:\n\n")
+		writeParameters(b, r.Headers, false)
+	}
+	for _, e := range r.Examples {
+		fmt.Fprintf(b, "Example (`%s`):\n\n", e.MediaType)
+		writeCode(b, e.MediaType, e.Text)
+	}
+}
+
+// writeSchema writes the type of a schema, a table of its properties and its example
+func writeSchema(b *bytes.Buffer, s *schema) {
+	if s == nil {
+		return
+	}
+	fmt.Fprintf(b, "Type: `%s`\n\n", s.Type)
+	if len(s.Properties) > 0 {
+		b.WriteString("| Property | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+		for _, p := range s.Properties {
+			fmt.Fprintf(b, "| `%s` | `%s` | %s | %s |\n", p.Path, p.Type, yesNo(p.Required), cell(p.Description))
+		}
+		b.WriteString("\n")
+	}
+	if s.Example != "" {
+		b.WriteString("Example:\n\n")
+		writeCode(b, "application/json", s.Example)
+	}
+}
+
+// writeCode writes a fenced code block, highlighted as JSON or XML when the media type says so
+func writeCode(b *bytes.Buffer, mediaType, text string) {
+	lang := ""
+	switch {
+	case strings.Contains(mediaType, "json"):
+		lang = "json"
+	case strings.Contains(mediaType, "xml"):
+		lang = "xml"
+	}
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(text, "\n"), fence)
+}
+
+// heading returns the Markdown for a heading level
+func heading(level int) string {
+	if level > 6 {
+		level = 6
+	}
+	return strings.Repeat("#", level)
+}
+
+// codeList writes values as a comma separated list of code spans
+func codeList(values []string) string {
+	return "`" + strings.Join(values, "`, `") + "`"
+}
+
+// yesNo writes a boolean for a table
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// cell makes text fit in a table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "|", "\\|")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// inline makes text fit on a single line, such as a heading or a link
+func inline(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// firstLine returns the first line of text
+func firstLine(text string) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
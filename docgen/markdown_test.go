@@ -0,0 +1,188 @@
+package docgen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestExamples(t *testing.T) {
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			out := string(Markdown(swag))
+			if !strings.HasPrefix(out, "# ") || strings.Count(out, "```")%2 != 0 {
+				t.Errorf("%s: unexpected output\n%s", file, out)
+			}
+			if files := MarkdownByTag(swag); files[IndexFile] == nil {
+				t.Errorf("%s: no index page in %v", file, files)
+			}
+		}
+	}
+}
+
+const shelter = `
+swagger: "2.0"
+info:
+  title: Pet Shelter
+  version: "1.0"
+  description: Adopt a pet.
+  license: {name: MIT, url: "https://opensource.org/licenses/MIT"}
+host: shelter.example.com
+basePath: /v1
+schemes: [https]
+tags:
+  - {name: pets, description: Pets waiting for a home.}
+  - {name: adoptions}
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+  oauth:
+    type: oauth2
+    flow: implicit
+    authorizationUrl: https://shelter.example.com/auth
+    scopes: {"read:pets": Read pets, "write:pets": Change pets}
+security:
+  - key: []
+paths:
+  /pets:
+    parameters:
+      - {name: limit, in: query, type: integer, format: int32, description: At most this many.}
+    get:
+      tags: [pets]
+      summary: List pets
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat, dog]}
+      responses:
+        200:
+          description: The pets.
+          headers:
+            X-Total: {type: integer, description: How many pets there are.}
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+    post:
+      tags: [pets]
+      security:
+        - oauth: ["write:pets"]
+      parameters:
+        - {name: pet, in: body, required: true, description: Pet to add to the store, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201:
+          description: The new pet.
+          examples:
+            application/json: {id: 7, name: Rex}
+  /pets/{id}:
+    delete:
+      tags: [pets]
+      deprecated: true
+      security: []
+      parameters:
+        - {name: id, in: path, required: true, type: integer}
+      responses:
+        204: {description: Gone | removed.}
+  /adoptions:
+    post:
+      tags: [adoptions, status]
+      responses:
+        200: {description: OK}
+  /health:
+    get:
+      tags: [status]
+      responses:
+        200: {description: OK}
+  /ping:
+    get:
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    example: {name: Rex}
+    properties:
+      id: {type: integer, format: int64, readOnly: true}
+      name: {type: string, pattern: "^[A-Z]"}
+      owner:
+        type: object
+        properties:
+          name: {type: string}
+      friends: {type: array, items: {$ref: "#/definitions/Pet"}}
+`
+
+func TestMarkdown(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(Markdown(swag))
+	for _, want := range []string{
+		"# Pet Shelter\n\nVersion 1.0\n\nAdopt a pet.\n\n- Base URL: `https://shelter.example.com/v1`\n- License: [MIT](https://opensource.org/licenses/MIT)\n",
+		"- [pets](#pets)\n  - [GET /pets](#get-pets) List pets\n",
+		"- [status](#status)\n  - [POST /adoptions](#post-adoptions-1)\n",
+		"- [status](#status)\n",
+		"- [Other operations](#other-operations)\n",
+		"Unless an operation says otherwise, the API requires `key`.",
+		"| `read:pets` | Read pets |",
+		"## pets\n\nPets waiting for a home.\n\n### GET /pets\n\nList pets\n\n#### Parameters",
+		"| `kind` | query | `string` | no | One of `cat`, `dog`. |\n| `limit` | query | `integer (int32)` | no | At most this many. |",
+		"| `[].id` | `integer (int64)` | no | Read only. |",
+		"| `[].owner.name` | `string` | no |  |",
+		"| `[].friends` | `array of Pet` | no |  |",
+		"| `X-Total` | `integer` | How many pets there are. |",
+		"Security: `oauth` (write:pets)",
+		"#### Request body\n\n**Required.**\n\nPet to add to the store\n\n",
+		"Type: `Pet`",
+		"Example:\n\n```json\n{\n  \"name\": \"Rex\"\n}\n```",
+		"Example (`application/json`):\n\n```json\n",
+		"### ~~DELETE /pets/{id}~~\n\n**Deprecated.**\n\nSecurity: None",
+		"| 204 | Gone \\| removed. |  |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "`[].friends[].") {
+		t.Errorf("a definition that refers to itself was expanded again:\n%s", out)
+	}
+	pets, adoptions := strings.Index(out, "## pets"), strings.Index(out, "## adoptions")
+	status, other := strings.Index(out, "## status"), strings.Index(out, "## Other operations")
+	if !(pets < adoptions && adoptions < status && status < other) {
+		t.Errorf("groups are out of order:\n%s", out)
+	}
+}
+
+func TestMarkdownByTag(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := MarkdownByTag(swag)
+	for _, name := range []string{IndexFile, "pets.md", "adoptions.md", "status.md", "other-operations.md"} {
+		if files[name] == nil {
+			t.Errorf("expected %s in %v", name, files)
+		}
+	}
+	if index := string(files[IndexFile]); !strings.Contains(index, "- [pets](pets.md)\n") || !strings.Contains(index, "## Security") {
+		t.Errorf("unexpected index:\n%s", index)
+	}
+	if page := string(files["pets.md"]); !strings.HasPrefix(page, "# pets\n") || !strings.Contains(page, "\n## GET /pets\n") {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+}
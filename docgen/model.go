@@ -0,0 +1,574 @@
+// Package docgen renders API reference documentation from Swagger 2 documents.
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// document is what the reference shows of a Swagger document, ready to be rendered
+type document struct {
+	Title          string
+	Version        string
+	Description    string
+	TermsOfService string
+	Contact        *swagger2.Contact
+	License        *swagger2.License
+	ExternalDocs   *swagger2.Documentation
+	BaseURL        string
+	Schemes        []*scheme
+	Security       string // what the API requires unless an operation says otherwise
+	Groups         []*group
+}
+
+// scheme describes a security definition
+type scheme struct {
+	Name        string
+	Kind        string // a sentence describing how credentials are sent
+	Description string
+	Scopes      []row // scope name and description
+}
+
+// row is a name with a description
+type row struct {
+	Name        string
+	Description string
+}
+
+// group holds the operations of a tag
+type group struct {
+	Name         string
+	Slug         string
+	Description  string
+	ExternalDocs *swagger2.Documentation
+	Operations   []*operation
+}
+
+// operation describes an operation
+type operation struct {
+	Method       string
+	Path         string
+	Slug         string
+	Summary      string
+	Description  string
+	Deprecated   bool
+	Security     string
+	Consumes     []string
+	Produces     []string
+	Parameters   []*parameter
+	Body         *body
+	Responses    []*response
+	ExternalDocs *swagger2.Documentation
+}
+
+// parameter describes a parameter other than the body
+type parameter struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// body describes the body of a request
+type body struct {
+	Name        string
+	Description string
+	Required    bool
+	Schema      *schema
+}
+
+// response describes a response
+type response struct {
+	Status      string
+	Description string
+	Schema      *schema
+	Headers     []*parameter
+	Examples    []*example
+}
+
+// schema describes a schema with its properties expanded
+type schema struct {
+	Type       string
+	Properties []*property
+	Example    string
+}
+
+// property is a property of an expanded schema, named by its path from the root, such as "owner.name" or
+// "tags[]"
+type property struct {
+	Path        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// example is an example for a media type
+type example struct {
+	MediaType string
+	Text      string
+}
+
+// build collects what the reference shows of a document
+func build(swag *swagger2.Swagger) *document {
+	d := &document{
+		Title:          swag.Info.Title,
+		Version:        swag.Info.Version,
+		Description:    strings.TrimSpace(swag.Info.Description),
+		TermsOfService: swag.Info.TermsOfService,
+		Contact:        swag.Info.Contact,
+		License:        swag.Info.License,
+		ExternalDocs:   swag.ExternalDocs,
+		BaseURL:        baseURL(swag),
+		Security:       security(swag.Security),
+	}
+	names := make([]string, 0, len(swag.SecurityDefinitions))
+	for n := range swag.SecurityDefinitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		d.Schemes = append(d.Schemes, newScheme(n, swag.SecurityDefinitions[n]))
+	}
+	d.Groups = groups(swag)
+	return d
+}
+
+// baseURL returns where the API is served
+func baseURL(swag *swagger2.Swagger) string {
+	if swag.Host == "" {
+		return swag.BasePath
+	}
+	scheme := "http"
+	if len(swag.Schemes) > 0 {
+		scheme = swag.Schemes[0]
+	}
+	return scheme + "://" + swag.Host + swag.BasePath
+}
+
+// newScheme describes a security definition
+func newScheme(name string, def swagger2.SecurityDefinition) *scheme {
+	s := &scheme{Name: name, Description: strings.TrimSpace(def.Description)}
+	switch def.Type {
+	case "basic":
+		s.Kind = "HTTP basic authentication."
+	case "apiKey":
+		where := "header"
+		if def.In == "query" {
+			where = "query parameter"
+		}
+		s.Kind = fmt.Sprintf("An API key sent in the `%s` %s.", def.Name, where)
+	case "oauth2":
+		s.Kind = fmt.Sprintf("OAuth2 with the %s flow.", def.Flow)
+		if def.AuthorizationUrl != "" {
+			s.Kind += fmt.Sprintf(" Authorization URL: %s.", def.AuthorizationUrl)
+		}
+		if def.TokenUrl != "" {
+			s.Kind += fmt.Sprintf(" Token URL: %s.", def.TokenUrl)
+		}
+		scopes := make([]string, 0, len(def.Scopes))
+		for sc := range def.Scopes {
+			scopes = append(scopes, sc)
+		}
+		sort.Strings(scopes)
+		for _, sc := range scopes {
+			s.Scopes = append(s.Scopes, row{Name: sc, Description: def.Scopes[sc]})
+		}
+	default:
+		s.Kind = def.Type + "."
+	}
+	return s
+}
+
+// security describes security requirements, such as "`api_key` or `oauth` (read, write)"
+func security(reqs []swagger2.Security) string {
+	if reqs == nil {
+		return ""
+	}
+	if len(reqs) == 0 {
+		return "None"
+	}
+	alternatives := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		names := make([]string, 0, len(req))
+		for n := range req {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		all := make([]string, 0, len(names))
+		for _, n := range names {
+			text := "`" + n + "`"
+			if scopes := req[n]; len(scopes) > 0 {
+				text += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			all = append(all, text)
+		}
+		if len(all) == 0 {
+			alternatives = append(alternatives, "None")
+		} else {
+			alternatives = append(alternatives, strings.Join(all, " and "))
+		}
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// groups collects the operations under each of their tags, in the order the tags are declared, followed by tags
+// that are not declared in sorted order and then by the operations without tags
+func groups(swag *swagger2.Swagger) []*group {
+	byName := make(map[string]*group)
+	list := make([]*group, 0)
+	add := func(name string) *group {
+		if g, ok := byName[name]; ok {
+			return g
+		}
+		g := &group{Name: name}
+		byName[name] = g
+		list = append(list, g)
+		return g
+	}
+	for _, t := range swag.Tags {
+		g := add(t.Name)
+		g.Description = strings.TrimSpace(t.Description)
+		g.ExternalDocs = t.ExternalDocs
+	}
+	declared := len(list)
+
+	paths := make([]string, 0, len(swag.Paths))
+	for p := range swag.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var untagged []*operation
+	for _, path := range paths {
+		item := swag.Paths[path]
+		for _, method := range swagger2.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			o := newOperation(swag, path, method, &item, op)
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, o)
+			}
+			// each tag gets its own copy, which is written out with its own anchor
+			for _, t := range op.Tags {
+				g := add(t)
+				c := *o
+				g.Operations = append(g.Operations, &c)
+			}
+		}
+	}
+	undeclared := list[declared:]
+	sort.SliceStable(undeclared, func(i, j int) bool { return undeclared[i].Name < undeclared[j].Name })
+	if len(untagged) > 0 {
+		name := "Operations"
+		if len(list) > 0 {
+			name = "Other operations"
+		}
+		list = append(list, &group{Name: name, Operations: untagged})
+	}
+	groups := list[:0]
+	slugs := map[string]int{slug(swag.Info.Title): 1, "contents": 1, "security": 1}
+	for _, g := range list {
+		if len(g.Operations) > 0 {
+			g.Slug = uniqueSlug(slugs, g.Name)
+			groups = append(groups, g)
+		}
+	}
+	for _, g := range groups {
+		for _, o := range g.Operations {
+			o.Slug = uniqueSlug(slugs, o.Method+" "+o.Path)
+		}
+	}
+	return groups
+}
+
+// newOperation describes an operation
+func newOperation(swag *swagger2.Swagger, path, method string, item *swagger2.PathItem, op *swagger2.Operation) *operation {
+	o := &operation{
+		Method:       strings.ToUpper(method),
+		Path:         path,
+		Summary:      strings.TrimSpace(op.Summary),
+		Description:  strings.TrimSpace(op.Description),
+		Deprecated:   op.Deprecated,
+		Consumes:     swag.Consumes,
+		Produces:     swag.Produces,
+		ExternalDocs: op.ExternalDocs,
+	}
+	if op.Consumes != nil {
+		o.Consumes = op.Consumes
+	}
+	if op.Produces != nil {
+		o.Produces = op.Produces
+	}
+	if op.Security != nil {
+		o.Security = security(op.Security)
+	}
+	x := &expander{defs: swag.Definitions}
+	for _, p := range swag.OperationParameters(path, item, op) {
+		if p.In == "body" {
+			o.Body = &body{Name: p.Name, Description: strings.TrimSpace(p.Description), Required: p.Required != nil && *p.Required}
+			if p.Schema != nil {
+				o.Body.Schema = x.schema(p.Schema)
+			}
+			continue
+		}
+		o.Parameters = append(o.Parameters, &parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Type:        itemsType(&p.ItemsDef),
+			Required:    p.In == "path" || (p.Required != nil && *p.Required),
+			Description: describe(p.Description, &p.ItemsDef),
+		})
+	}
+	codes := make([]string, 0, len(op.Responses))
+	for c := range op.Responses {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		r := op.Responses[c]
+		res := &response{Status: c, Description: strings.TrimSpace(r.Description)}
+		if r.Schema != nil {
+			res.Schema = x.schema(r.Schema)
+		}
+		headers := make([]string, 0, len(r.Headers))
+		for h := range r.Headers {
+			headers = append(headers, h)
+		}
+		sort.Strings(headers)
+		for _, h := range headers {
+			def := r.Headers[h]
+			res.Headers = append(res.Headers, &parameter{Name: h, In: "header", Type: itemsType(&def.ItemsDef), Description: describe(def.Description, &def.ItemsDef)})
+		}
+		types := make([]string, 0, len(r.Examples))
+		for t := range r.Examples {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			res.Examples = append(res.Examples, &example{MediaType: t, Text: exampleText(r.Examples[t])})
+		}
+		o.Responses = append(o.Responses, res)
+	}
+	return o
+}
+
+// expander expands schemas into their properties, following references to definitions
+type expander struct {
+	defs swagger2.Definitions
+}
+
+// schema describes a schema with the properties of its objects expanded
+func (x *expander) schema(s *swagger2.Schema) *schema {
+	out := &schema{Type: schemaType(s)}
+	x.expand(&out.Properties, "", s, make(map[string]bool))
+	if s.Example != nil {
+		out.Example = exampleText(s.Example)
+	} else if name, ok := x.defs.RefName(s.Ref); ok && s.Ref != "" {
+		if def, ok := x.defs[name]; ok && def.Example != nil {
+			out.Example = exampleText(def.Example)
+		}
+	}
+	return out
+}
+
+// expand adds the properties of an object schema, or of the objects an array holds, to the list. seen holds the
+// definitions being expanded, so that a definition that refers to itself is not expanded again.
+func (x *expander) expand(list *[]*property, prefix string, s *swagger2.Schema, seen map[string]bool) {
+	if s.Ref != "" {
+		name, ok := x.defs.RefName(s.Ref)
+		def, found := x.defs[name]
+		if !ok || !found || seen[name] {
+			return
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		x.expand(list, prefix, &def, seen)
+		return
+	}
+	for i := range s.AllOf {
+		x.expand(list, prefix, &s.AllOf[i], seen)
+	}
+	if s.Type == "array" && s.Items != nil {
+		x.expand(list, prefix+"[]", &swagger2.Schema{ItemsDef: *s.Items}, seen)
+		return
+	}
+	if s.Type == "object" && s.AdditionalProperties != nil {
+		x.expand(list, prefix+"{}", &swagger2.Schema{ItemsDef: *s.AdditionalProperties}, seen)
+		return
+	}
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p := s.Properties[n]
+		path := n
+		if prefix != "" {
+			path = prefix + "." + n
+		}
+		desc := describe(p.Description, &p.ItemsDef)
+		if p.ReadOnly != nil && *p.ReadOnly {
+			desc = strings.TrimSpace("Read only. " + desc)
+		}
+		add(list, &property{Path: path, Type: schemaType(&p), Required: lists.Contains(s.Required, n), Description: desc})
+		x.expand(list, path, &p, seen)
+	}
+}
+
+// add appends a property to the list, or replaces the property with the same path that an earlier schema of an
+// allOf declared
+func add(list *[]*property, p *property) {
+	for i, q := range *list {
+		if q.Path == p.Path {
+			(*list)[i] = p
+			return
+		}
+	}
+	*list = append(*list, p)
+}
+
+// schemaType names the type of a schema, such as "array of Pet"
+func schemaType(s *swagger2.Schema) string {
+	if s.Ref == "" && len(s.AllOf) > 0 {
+		parts := make([]string, 0, len(s.AllOf))
+		for i := range s.AllOf {
+			parts = append(parts, schemaType(&s.AllOf[i]))
+		}
+		return strings.Join(parts, " and ")
+	}
+	if s.Ref == "" && s.Type == "" && len(s.Properties) > 0 {
+		return "object"
+	}
+	return itemsType(&s.ItemsDef)
+}
+
+// itemsType names the type of a simple schema
+func itemsType(d *swagger2.ItemsDef) string {
+	if d.Ref != "" {
+		if _, name := swagger2.ParseRef(d.Ref); name != "" {
+			return name
+		}
+		return d.Ref
+	}
+	switch d.Type {
+	case "array":
+		t := "array"
+		if d.Items != nil {
+			t += " of " + itemsType(d.Items)
+		}
+		if d.CollectionFormat != "" && d.CollectionFormat != "csv" {
+			t += " (" + d.CollectionFormat + ")"
+		}
+		return t
+	case "object":
+		if d.AdditionalProperties != nil {
+			return "map of " + itemsType(d.AdditionalProperties)
+		}
+		return "object"
+	case "":
+		return "any"
+	}
+	if d.Format != "" {
+		return d.Type + " (" + d.Format + ")"
+	}
+	return d.Type
+}
+
+// describe adds the constraints of a simple schema to a description
+func describe(description string, d *swagger2.ItemsDef) string {
+	parts := make([]string, 0)
+	if text := strings.TrimSpace(description); text != "" {
+		parts = append(parts, text)
+	}
+	if len(d.Enum) > 0 {
+		values := make([]string, 0, len(d.Enum))
+		for _, v := range d.Enum {
+			values = append(values, "`"+fmt.Sprint(v)+"`")
+		}
+		parts = append(parts, "One of "+strings.Join(values, ", ")+".")
+	}
+	if d.Default != nil {
+		parts = append(parts, "Default `"+fmt.Sprint(d.Default)+"`.")
+	}
+	if d.Minimum != nil {
+		parts = append(parts, fmt.Sprintf("Minimum %v.", *d.Minimum))
+	}
+	if d.Maximum != nil {
+		parts = append(parts, fmt.Sprintf("Maximum %v.", *d.Maximum))
+	}
+	if d.Pattern != nil {
+		parts = append(parts, "Pattern `"+*d.Pattern+"`.")
+	}
+	return strings.Join(parts, " ")
+}
+
+// exampleText formats an example as indented JSON, or as it is if it is a string
+func exampleText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.MarshalIndent(jsonValue(v), "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// jsonValue converts the maps YAML decoding produces into maps that encoding/json accepts
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = jsonValue(e)
+		}
+		return l
+	}
+	return v
+}
+
+// slug returns the anchor that GitHub gives a heading
+func slug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// uniqueSlug returns the anchor for a heading, numbered like GitHub does when another heading has the same one
+func uniqueSlug(seen map[string]int, heading string) string {
+	s := slug(heading)
+	n := seen[s]
+	seen[s] = n + 1
+	if n > 0 {
+		return fmt.Sprintf("%s-%d", s, n)
+	}
+	return s
+}
@@ -0,0 +1,187 @@
+// Package docserver serves Swagger 2 documents over HTTP, as JSON and YAML, together with interactive
+// documentation that lists the operations of the document and sends requests to the API from the browser.
+package docserver
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+//go:embed ui
+var ui embed.FS
+
+// Options configures the paths a Handler serves and how it rewrites the document for a request. The zero value
+// serves the document at /swagger.json and /swagger.yaml and the documentation at /docs/, without rewriting it.
+type Options struct {
+	JSONPath string // Path of the document as JSON; defaults to /swagger.json
+	YAMLPath string // Path of the document as YAML; defaults to /swagger.yaml
+	DocsPath string // Path of the interactive documentation, ending with a slash; defaults to /docs/
+
+	// Forwarded sets the host, scheme and base path of the document from the Forwarded header of a request, or from
+	// its X-Forwarded-Host, X-Forwarded-Proto and X-Forwarded-Prefix headers. The prefix is added before the base
+	// path. Only set it when the handler is behind proxies that set these headers.
+	Forwarded bool
+
+	// Rewrite, if set, returns the host and base path to serve the document with for a request, given those of the
+	// document after the Forwarded headers have been applied.
+	Rewrite func(r *http.Request, host, basePath string) (string, string)
+}
+
+// handler serves a document and its documentation
+type handler struct {
+	swag  *swagger2.Swagger
+	opts  Options
+	json  []byte // the document as JSON and YAML, when it is not rewritten for each request
+	yaml  []byte
+	index []byte
+	files http.Handler
+}
+
+// Handler returns an http.Handler that serves a document as JSON and YAML, and interactive documentation for it.
+// The paths in the options are matched against the path of the request, so a handler mounted under a prefix with
+// http.StripPrefix uses paths without the prefix. The document must not be changed while the handler serves it.
+func Handler(swag *swagger2.Swagger, opts Options) (http.Handler, error) {
+	if opts.JSONPath == "" {
+		opts.JSONPath = "/swagger.json"
+	}
+	if opts.YAMLPath == "" {
+		opts.YAMLPath = "/swagger.yaml"
+	}
+	if opts.DocsPath == "" {
+		opts.DocsPath = "/docs/"
+	}
+	if !strings.HasSuffix(opts.DocsPath, "/") {
+		opts.DocsPath += "/"
+	}
+	h := &handler{swag: swag, opts: opts}
+	if !opts.Forwarded && opts.Rewrite == nil {
+		var err error
+		if h.json, err = swag.Json(); err != nil {
+			return nil, err
+		}
+		if h.yaml, err = swag.Yaml(); err != nil {
+			return nil, err
+		}
+	}
+	files, err := fs.Sub(ui, "ui")
+	if err != nil {
+		return nil, err
+	}
+	h.files = http.StripPrefix(opts.DocsPath, http.FileServer(http.FS(files)))
+	page, err := template.ParseFS(files, "index.html")
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	err = page.Execute(&b, map[string]string{"Title": swag.Info.Title, "Spec": relative(opts.DocsPath, opts.JSONPath)})
+	if err != nil {
+		return nil, err
+	}
+	h.index = b.Bytes()
+	return h, nil
+}
+
+// relative returns the URL of a path relative to a directory
+func relative(dir, to string) string {
+	up := strings.Count(strings.Trim(dir, "/"), "/")
+	if strings.Trim(dir, "/") != "" {
+		up++
+	}
+	return strings.Repeat("../", up) + strings.TrimPrefix(to, "/")
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	switch p := r.URL.Path; {
+	case p == h.opts.JSONPath:
+		h.serveDocument(w, r, "application/json", h.json, (*swagger2.Swagger).Json)
+	case p == h.opts.YAMLPath:
+		h.serveDocument(w, r, "application/yaml", h.yaml, (*swagger2.Swagger).Yaml)
+	case p == strings.TrimSuffix(h.opts.DocsPath, "/"):
+		// the location is relative, so that it is right under any prefix the handler is mounted at
+		w.Header().Set("Location", path.Base(h.opts.DocsPath)+"/")
+		w.WriteHeader(http.StatusMovedPermanently)
+	case p == h.opts.DocsPath || p == h.opts.DocsPath+"index.html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(h.index)
+	case strings.HasPrefix(p, h.opts.DocsPath):
+		h.files.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveDocument writes the document, marshaling it for the request if it is rewritten
+func (h *handler) serveDocument(w http.ResponseWriter, r *http.Request, contentType string, b []byte, marshal func(*swagger2.Swagger) ([]byte, error)) {
+	if b == nil {
+		var err error
+		if b, err = marshal(h.rewrite(r)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(b)
+}
+
+// rewrite returns a copy of the document with the host, schemes and base path for a request
+func (h *handler) rewrite(r *http.Request) *swagger2.Swagger {
+	swag := *h.swag
+	if h.opts.Forwarded {
+		host, proto, prefix := forwarded(r)
+		if host != "" {
+			swag.Host = host
+		}
+		if proto != "" {
+			swag.Schemes = []string{proto}
+		}
+		if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+			swag.BasePath = prefix + swag.BasePath
+		}
+	}
+	if h.opts.Rewrite != nil {
+		swag.Host, swag.BasePath = h.opts.Rewrite(r, swag.Host, swag.BasePath)
+	}
+	return &swag
+}
+
+// forwarded returns the host, protocol and path prefix a proxy forwarded a request for
+func forwarded(r *http.Request) (host, proto, prefix string) {
+	if f := r.Header.Get("Forwarded"); f != "" {
+		// only the first proxy counts, the one the client connected to
+		first := strings.Split(f, ",")[0]
+		for _, pair := range strings.Split(first, ";") {
+			kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			value := strings.Trim(kv[1], `"`)
+			switch strings.ToLower(kv[0]) {
+			case "host":
+				host = value
+			case "proto":
+				proto = value
+			}
+		}
+	}
+	if host == "" {
+		host = strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Host"), ",")[0])
+	}
+	if proto == "" {
+		proto = strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0])
+	}
+	prefix = strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Prefix"), ",")[0])
+	return host, strings.ToLower(proto), prefix
+}
@@ -0,0 +1,133 @@
+package docserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+const petstore = `
+swagger: "2.0"
+info: {title: Petstore, version: "1.0"}
+host: pets.internal:8080
+basePath: /v1
+paths:
+  /pets:
+    get:
+      responses:
+        200: {description: The pets.}
+`
+
+func serve(t *testing.T, h http.Handler, method, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Handler(swag, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		method, path string
+		status       int
+		contentType  string
+		want         string
+	}{
+		{"GET", "/swagger.json", 200, "application/json", `"host": "pets.internal:8080"`},
+		{"GET", "/swagger.yaml", 200, "application/yaml", "basePath: /v1"},
+		{"GET", "/docs/", 200, "text/html; charset=utf-8", `<meta name="swagger-document" content="../swagger.json">`},
+		{"GET", "/docs/app.js", 200, "text/javascript; charset=utf-8", "sendRequest"},
+		{"GET", "/docs/style.css", 200, "text/css; charset=utf-8", ".operation"},
+		{"GET", "/docs", 301, "", ""},
+		{"GET", "/other", 404, "", ""},
+		{"POST", "/swagger.json", 405, "", ""},
+	} {
+		w := serve(t, h, c.method, c.path, nil)
+		if w.Code != c.status {
+			t.Errorf("%s %s: expected status %d, got %d", c.method, c.path, c.status, w.Code)
+			continue
+		}
+		if c.contentType != "" && w.Header().Get("Content-Type") != c.contentType {
+			t.Errorf("%s %s: expected content type %s, got %s", c.method, c.path, c.contentType, w.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(w.Body.String(), c.want) {
+			t.Errorf("%s %s: expected %q in:\n%s", c.method, c.path, c.want, w.Body.String())
+		}
+	}
+	if loc := serve(t, h, "GET", "/docs", nil).Header().Get("Location"); loc != "docs/" {
+		t.Errorf("unexpected redirect to %s", loc)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Handler(swag, Options{JSONPath: "/api/spec.json", YAMLPath: "/api/spec.yaml", DocsPath: "/api/docs/reference"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(t, h, "GET", "/api/spec.json", nil); w.Code != 200 {
+		t.Errorf("unexpected status %d", w.Code)
+	}
+	w := serve(t, h, "GET", "/api/docs/reference/", nil)
+	if !strings.Contains(w.Body.String(), `content="../../../api/spec.json"`) {
+		t.Errorf("unexpected page:\n%s", w.Body.String())
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := Handler(swag, Options{Forwarded: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := serve(t, h, "GET", "/swagger.json", http.Header{
+		"X-Forwarded-Host":   {"api.example.com, gateway.internal"},
+		"X-Forwarded-Proto":  {"https"},
+		"X-Forwarded-Prefix": {"/pets/"},
+	})
+	for _, want := range []string{`"host": "api.example.com"`, `"basePath": "/pets/v1"`, `"schemes": [` + "\n    \"https\"\n  ]"} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, w.Body.String())
+		}
+	}
+	w = serve(t, h, "GET", "/swagger.yaml", http.Header{"Forwarded": {`for=1.2.3.4;host="gw.example.com";proto=HTTPS, host=other`}})
+	for _, want := range []string{"host: gw.example.com", "- https", "basePath: /v1"} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, w.Body.String())
+		}
+	}
+	if swag.Host != "pets.internal:8080" || swag.BasePath != "/v1" {
+		t.Errorf("the document was changed: %s %s", swag.Host, swag.BasePath)
+	}
+
+	h, err = Handler(swag, Options{Rewrite: func(r *http.Request, host, basePath string) (string, string) {
+		return r.Host, "/" + r.URL.Query().Get("stage") + basePath
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = serve(t, h, "GET", "http://edge.example.com/swagger.json?stage=beta", nil)
+	if body := w.Body.String(); !strings.Contains(body, `"host": "edge.example.com"`) || !strings.Contains(body, `"basePath": "/beta/v1"`) {
+		t.Errorf("unexpected document:\n%s", body)
+	}
+}
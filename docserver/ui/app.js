@@ -0,0 +1,520 @@
+// Interactive documentation for a Swagger 2 document: lists the operations by tag and sends requests to the API.
+(function () {
+  "use strict";
+
+  var METHODS = ["get", "put", "post", "delete", "options", "head", "patch"];
+  var IN_ORDER = { path: 0, query: 1, header: 2, formData: 3, body: 4 };
+  var spec;
+  var credentials = {};
+
+  // el creates an element with attributes and children; strings become text, never markup
+  function el(tag, attrs) {
+    var e = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      var v = attrs[k];
+      if (v === undefined || v === null || v === false) {
+        return;
+      }
+      if (k.slice(0, 2) === "on") {
+        e.addEventListener(k.slice(2), v);
+      } else if (k === "value") {
+        e.value = v;
+      } else {
+        e.setAttribute(k, v === true ? "" : v);
+      }
+    });
+    for (var i = 2; i < arguments.length; i++) {
+      append(e, arguments[i]);
+    }
+    return e;
+  }
+
+  function append(e, child) {
+    if (child === undefined || child === null || child === false) {
+      return;
+    }
+    if (Array.isArray(child)) {
+      child.forEach(function (c) { append(e, c); });
+    } else if (typeof child === "string") {
+      e.appendChild(document.createTextNode(child));
+    } else {
+      e.appendChild(child);
+    }
+  }
+
+  function clear(e) {
+    while (e.firstChild) {
+      e.removeChild(e.firstChild);
+    }
+    return e;
+  }
+
+  // refName returns the name of the definition or parameter a reference points to, allowing the short "Pet" form
+  function refName(ref) {
+    var i = ref.lastIndexOf("/");
+    return i < 0 ? ref : decodeURIComponent(ref.slice(i + 1)).replace(/~1/g, "/").replace(/~0/g, "~");
+  }
+
+  function definition(ref) {
+    return (spec.definitions || {})[refName(ref)];
+  }
+
+  function parameter(p) {
+    return p.$ref ? (spec.parameters || {})[refName(p.$ref)] || { name: refName(p.$ref), in: "query" } : p;
+  }
+
+  // parameters merges the parameters of a path with those of an operation, which override them
+  function parameters(item, op) {
+    var all = [];
+    var seen = {};
+    (op.parameters || []).concat(item.parameters || []).forEach(function (p) {
+      p = parameter(p);
+      var key = p.in + ":" + p.name;
+      if (!seen[key]) {
+        seen[key] = true;
+        all.push(p);
+      }
+    });
+    return all.sort(function (a, b) { return IN_ORDER[a.in] - IN_ORDER[b.in]; });
+  }
+
+  // sample makes a value that matches a schema, to start a request body with
+  function sample(s, seen) {
+    seen = seen || {};
+    if (!s) {
+      return null;
+    }
+    if (s.$ref) {
+      var name = refName(s.$ref);
+      if (seen[name]) {
+        return null;
+      }
+      seen[name] = true;
+      var v = sample(definition(s.$ref), seen);
+      delete seen[name];
+      return v;
+    }
+    if (s.example !== undefined) {
+      return s.example;
+    }
+    if (s["default"] !== undefined) {
+      return s["default"];
+    }
+    if (s["enum"] && s["enum"].length) {
+      return s["enum"][0];
+    }
+    if (s.allOf) {
+      var merged = {};
+      s.allOf.forEach(function (part) {
+        var v = sample(part, seen);
+        if (v && typeof v === "object" && !Array.isArray(v)) {
+          Object.keys(v).forEach(function (k) { merged[k] = v[k]; });
+        }
+      });
+      return merged;
+    }
+    switch (s.type) {
+      case "array":
+        return s.items ? [sample(s.items, seen)] : [];
+      case "integer":
+      case "number":
+        return s.minimum !== undefined ? s.minimum : 0;
+      case "boolean":
+        return true;
+      case "string":
+        switch (s.format) {
+          case "date-time":
+            return new Date(0).toISOString();
+          case "date":
+            return "1970-01-01";
+          case "uuid":
+            return "00000000-0000-0000-0000-000000000000";
+        }
+        return "string";
+      case "file":
+        return null;
+    }
+    if (s.type === "object" || s.properties || s.additionalProperties) {
+      var o = {};
+      Object.keys(s.properties || {}).forEach(function (k) {
+        if (!s.properties[k].readOnly) {
+          o[k] = sample(s.properties[k], seen);
+        }
+      });
+      if (s.additionalProperties && !s.properties) {
+        o.key = sample(s.additionalProperties, seen);
+      }
+      return o;
+    }
+    return null;
+  }
+
+  // typeName names the type of a schema or parameter, such as "array of Pet"
+  function typeName(s) {
+    if (!s) {
+      return "";
+    }
+    if (s.$ref) {
+      return refName(s.$ref);
+    }
+    if (s.type === "array") {
+      return "array of " + (typeName(s.items) || "any");
+    }
+    if (s.type === "object" && s.additionalProperties) {
+      return "map of " + (typeName(s.additionalProperties) || "any");
+    }
+    if (s.allOf) {
+      return s.allOf.map(typeName).join(" and ");
+    }
+    return (s.type || "any") + (s.format ? " (" + s.format + ")" : "");
+  }
+
+  function baseURL() {
+    var scheme = document.getElementById("scheme");
+    var host = document.getElementById("host");
+    var base = document.getElementById("base-path");
+    return scheme.value + "://" + host.value + base.value.replace(/\/$/, "");
+  }
+
+  function renderInfo() {
+    var info = spec.info || {};
+    var header = clear(document.getElementById("info"));
+    append(header, el("h1", null, info.title || "API reference"));
+    if (info.version) {
+      append(header, el("p", { "class": "version" }, "Version " + info.version));
+    }
+    if (info.description) {
+      append(header, el("p", { "class": "description" }, info.description));
+    }
+    var links = el("p", { "class": "links" });
+    if (info.termsOfService) {
+      append(links, el("a", { href: info.termsOfService }, "Terms of service"));
+    }
+    if (info.license && info.license.url) {
+      append(links, el("a", { href: info.license.url }, info.license.name || "License"));
+    }
+    if (spec.externalDocs && spec.externalDocs.url) {
+      append(links, el("a", { href: spec.externalDocs.url }, spec.externalDocs.description || "More documentation"));
+    }
+    if (links.firstChild) {
+      append(header, links);
+    }
+    document.title = info.title || document.title;
+  }
+
+  function renderServer() {
+    var schemes = spec.schemes && spec.schemes.length ? spec.schemes : [location.protocol.replace(":", "")];
+    var scheme = el("select", { id: "scheme" }, schemes.map(function (s) { return el("option", { value: s }, s); }));
+    append(clear(document.getElementById("server")), [
+      el("h2", null, "Server"),
+      el("div", { "class": "server" },
+        el("label", null, "Scheme ", scheme),
+        el("label", null, "Host ", el("input", { id: "host", value: spec.host || location.host, size: 30 })),
+        el("label", null, "Base path ", el("input", { id: "base-path", value: spec.basePath || "", size: 20 })))
+    ]);
+  }
+
+  function renderAuthorize() {
+    var defs = spec.securityDefinitions || {};
+    var names = Object.keys(defs).sort();
+    var section = clear(document.getElementById("authorize"));
+    if (!names.length) {
+      return;
+    }
+    append(section, el("h2", null, "Authorize"));
+    names.forEach(function (name) {
+      var def = defs[name];
+      var set = function (key) {
+        return function (e) { credentials[name + key] = e.target.value; };
+      };
+      var fields;
+      switch (def.type) {
+        case "apiKey":
+          fields = el("label", null, "API key (" + def["in"] + " " + def.name + ") ",
+            el("input", { type: "password", oninput: set("") }));
+          break;
+        case "basic":
+          fields = [
+            el("label", null, "Username ", el("input", { oninput: set(":username") })),
+            el("label", null, "Password ", el("input", { type: "password", oninput: set(":password") }))
+          ];
+          break;
+        case "oauth2":
+          fields = el("label", null, "Access token (" + def.flow + ") ",
+            el("input", { type: "password", oninput: set("") }));
+          break;
+      }
+      append(section, el("div", { "class": "scheme" },
+        el("strong", null, name), def.description ? el("p", null, def.description) : null, fields));
+    });
+  }
+
+  // authorize adds the credentials the user entered for the security of an operation to a request
+  function authorize(op, headers, query) {
+    var reqs = op.security || spec.security || [];
+    var defs = spec.securityDefinitions || {};
+    reqs.forEach(function (req) {
+      Object.keys(req).forEach(function (name) {
+        var def = defs[name];
+        if (!def) {
+          return;
+        }
+        if (def.type === "apiKey" && credentials[name]) {
+          if (def["in"] === "query") {
+            query.push([def.name, credentials[name]]);
+          } else {
+            headers[def.name] = credentials[name];
+          }
+        } else if (def.type === "basic" && credentials[name + ":username"]) {
+          headers.Authorization = "Basic " + btoa(credentials[name + ":username"] + ":" + (credentials[name + ":password"] || ""));
+        } else if (def.type === "oauth2" && credentials[name]) {
+          headers.Authorization = "Bearer " + credentials[name];
+        }
+      });
+    });
+  }
+
+  // groups collects the operations by tag, in the order the tags are declared
+  function groups() {
+    var order = (spec.tags || []).map(function (t) { return t.name; });
+    var byTag = {};
+    Object.keys(spec.paths || {}).sort().forEach(function (path) {
+      var item = spec.paths[path];
+      METHODS.forEach(function (method) {
+        var op = item[method];
+        if (!op) {
+          return;
+        }
+        var tags = op.tags && op.tags.length ? op.tags : [""];
+        tags.forEach(function (tag) {
+          if (!byTag[tag]) {
+            byTag[tag] = [];
+            if (order.indexOf(tag) < 0 && tag !== "") {
+              order.push(tag);
+            }
+          }
+          byTag[tag].push({ path: path, method: method, item: item, op: op });
+        });
+      });
+    });
+    if (byTag[""]) {
+      order.push("");
+    }
+    return order.filter(function (t) { return byTag[t]; }).map(function (t) {
+      var tag = (spec.tags || []).filter(function (d) { return d.name === t; })[0] || {};
+      var name = t || (order.length > 1 ? "Other operations" : "Operations");
+      return { name: name, description: tag.description, operations: byTag[t] };
+    });
+  }
+
+  function renderOperations() {
+    var toc = clear(document.getElementById("toc"));
+    var list = clear(document.getElementById("operations"));
+    var count = 0;
+    groups().forEach(function (g) {
+      var links = el("ul");
+      append(toc, el("li", null, el("span", { "class": "tag" }, g.name), links));
+      append(list, el("h2", { "class": "tag" }, g.name));
+      if (g.description) {
+        append(list, el("p", { "class": "description" }, g.description));
+      }
+      g.operations.forEach(function (o) {
+        var id = "op-" + count++;
+        var text = o.method.toUpperCase() + " " + o.path + " " + (o.op.summary || "") + " " + (o.op.operationId || "");
+        append(links, el("li", { "data-filter": text.toLowerCase() },
+          el("a", { href: "#" + id, "class": o.op.deprecated ? "deprecated" : null },
+            el("span", { "class": "method " + o.method }, o.method.toUpperCase()), " " + o.path)));
+        append(list, renderOperation(id, o, text.toLowerCase()));
+      });
+    });
+  }
+
+  function renderOperation(id, o, filterText) {
+    var op = o.op;
+    var params = parameters(o.item, op);
+    var inputs = [];
+    var rows = params.filter(function (p) { return p["in"] !== "body"; }).map(function (p) {
+      var input;
+      if (p.type === "file") {
+        input = el("input", { type: "file" });
+      } else if (p["enum"]) {
+        input = el("select", null, [p.required ? null : el("option", { value: "" }, "")].concat(
+          p["enum"].map(function (v) { return el("option", { value: String(v) }, String(v)); })));
+      } else if (p.type === "boolean") {
+        input = el("select", null, el("option", { value: "" }, ""), el("option", { value: "true" }, "true"), el("option", { value: "false" }, "false"));
+      } else {
+        input = el("input", { placeholder: p.type === "array" ? "comma separated" : typeName(p), value: p["default"] !== undefined ? String(p["default"]) : "" });
+      }
+      inputs.push({ param: p, input: input });
+      return el("tr", null,
+        el("td", null, el("code", null, p.name), p.required ? el("span", { "class": "required" }, " *") : null),
+        el("td", null, p["in"]),
+        el("td", null, el("code", null, typeName(p))),
+        el("td", null, p.description || ""),
+        el("td", null, input));
+    });
+    var bodyParam = params.filter(function (p) { return p["in"] === "body"; })[0];
+    var consumes = op.consumes || spec.consumes || ["application/json"];
+    var body, contentType;
+    if (bodyParam) {
+      var value = sample(bodyParam.schema);
+      body = el("textarea", { rows: 8, spellcheck: "false", value: value === null ? "" : JSON.stringify(value, null, 2) });
+      contentType = el("select", null, consumes.map(function (c) { return el("option", { value: c }, c); }));
+    }
+    var result = el("div", { "class": "result" });
+    var send = el("button", {
+      type: "button",
+      onclick: function () { sendRequest(o, inputs, body, contentType, consumes, result); }
+    }, "Send");
+    var responses = Object.keys(op.responses || {}).sort().map(function (status) {
+      var r = op.responses[status];
+      var value = r.examples && r.examples["application/json"] !== undefined ? r.examples["application/json"] : r.schema ? sample(r.schema) : undefined;
+      return el("tr", null,
+        el("td", null, status),
+        el("td", null, r.description || ""),
+        el("td", null, r.schema ? el("code", null, typeName(r.schema)) : null,
+          value !== undefined && value !== null ? el("details", null, el("summary", null, "Example"), el("pre", null, JSON.stringify(value, null, 2))) : null));
+    });
+    return el("details", { id: id, "class": "operation " + o.method + (op.deprecated ? " deprecated" : ""), "data-filter": filterText },
+      el("summary", null,
+        el("span", { "class": "method " + o.method }, o.method.toUpperCase()), " ",
+        el("span", { "class": "path" }, o.path), " ",
+        el("span", { "class": "summary" }, op.summary || ""),
+        op.deprecated ? el("span", { "class": "badge" }, "Deprecated") : null),
+      op.description ? el("p", { "class": "description" }, op.description) : null,
+      rows.length ? el("table", null,
+        el("thead", null, el("tr", null, ["Name", "In", "Type", "Description", "Value"].map(function (h) { return el("th", null, h); }))),
+        el("tbody", null, rows)) : null,
+      body ? el("div", { "class": "body" },
+        el("h4", null, "Request body", bodyParam.required ? el("span", { "class": "required" }, " *") : null),
+        el("label", null, "Content type ", contentType),
+        el("p", null, el("code", null, typeName(bodyParam.schema)), bodyParam.description ? " " + bodyParam.description : ""),
+        body) : null,
+      el("h4", null, "Responses"),
+      el("table", null,
+        el("thead", null, el("tr", null, ["Status", "Description", "Type"].map(function (h) { return el("th", null, h); }))),
+        el("tbody", null, responses)),
+      el("p", null, send),
+      result);
+  }
+
+  // sendRequest builds the request from the values entered for an operation, sends it and shows the response
+  function sendRequest(o, inputs, body, contentType, consumes, result) {
+    var path = o.path;
+    var query = [];
+    var headers = {};
+    var form = null;
+    var missing = [];
+    var multipart = consumes.indexOf("multipart/form-data") >= 0;
+    inputs.forEach(function (i) {
+      var p = i.param;
+      var value = p.type === "file" ? i.input.files[0] : i.input.value;
+      if (value === undefined || value === "") {
+        if (p.required) {
+          missing.push(p.name);
+        }
+        return;
+      }
+      var values = p.type === "array" && typeof value === "string" ? value.split(",").map(function (v) { return v.trim(); }) : [value];
+      if (p.type === "array" && p.collectionFormat !== "multi") {
+        var sep = { ssv: " ", tsv: "\t", pipes: "|" }[p.collectionFormat] || ",";
+        values = [values.join(sep)];
+      }
+      switch (p["in"]) {
+        case "path":
+          path = path.split("{" + p.name + "}").join(encodeURIComponent(values[0]));
+          break;
+        case "query":
+          values.forEach(function (v) { query.push([p.name, v]); });
+          break;
+        case "header":
+          headers[p.name] = values[0];
+          break;
+        case "formData":
+          form = form || (multipart ? new FormData() : new URLSearchParams());
+          values.forEach(function (v) { form.append(p.name, v); });
+          break;
+      }
+    });
+    if (missing.length) {
+      append(clear(result), el("p", { "class": "error" }, "Required: " + missing.join(", ")));
+      return;
+    }
+    authorize(o.op, headers, query);
+    var init = { method: o.method.toUpperCase(), headers: headers };
+    if (body && body.value.trim() !== "") {
+      init.body = body.value;
+      headers["Content-Type"] = contentType.value;
+    } else if (form) {
+      init.body = form;
+    }
+    var url = baseURL() + path;
+    if (query.length) {
+      url += "?" + query.map(function (q) { return encodeURIComponent(q[0]) + "=" + encodeURIComponent(q[1]); }).join("&");
+    }
+    append(clear(result), el("p", null, "Sending ", el("code", null, init.method + " " + url), "…"));
+    var started = Date.now();
+    fetch(url, init).then(function (res) {
+      return res.text().then(function (text) {
+        var shown = text;
+        try {
+          shown = JSON.stringify(JSON.parse(text), null, 2);
+        } catch (e) {
+          // not JSON, shown as it is
+        }
+        var lines = [];
+        res.headers.forEach(function (v, k) { lines.push(k + ": " + v); });
+        append(clear(result), [
+          el("p", null, el("code", null, init.method + " " + url)),
+          el("p", { "class": res.ok ? "status ok" : "status failed" }, res.status + " " + res.statusText + " in " + (Date.now() - started) + " ms"),
+          el("pre", { "class": "headers" }, lines.join("\n")),
+          shown ? el("pre", null, shown) : null
+        ]);
+      });
+    }).catch(function (err) {
+      append(clear(result), el("p", { "class": "error" }, "The request failed: " + err.message + ". The API may not allow requests from this page (CORS)."));
+    });
+  }
+
+  function filter() {
+    var words = this.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var match = function (e) {
+      var text = e.getAttribute("data-filter");
+      return words.every(function (w) { return text.indexOf(w) >= 0; });
+    };
+    document.querySelectorAll("[data-filter]").forEach(function (e) {
+      e.hidden = !match(e);
+    });
+  }
+
+  var url = document.querySelector('meta[name="swagger-document"]').getAttribute("content");
+  fetch(url).then(function (res) {
+    if (!res.ok) {
+      throw new Error(res.status + " " + res.statusText);
+    }
+    return res.json();
+  }).then(function (doc) {
+    spec = doc;
+    renderInfo();
+    renderServer();
+    renderAuthorize();
+    renderOperations();
+    document.getElementById("filter").addEventListener("input", filter);
+    if (location.hash) {
+      var target = document.getElementById(location.hash.slice(1));
+      if (target) {
+        target.open = true;
+        target.scrollIntoView();
+      }
+    }
+  }).catch(function (err) {
+    append(clear(document.getElementById("operations")), el("p", { "class": "error" }, "Unable to load " + url + ": " + err.message));
+  });
+
+  window.addEventListener("hashchange", function () {
+    var target = document.getElementById(location.hash.slice(1));
+    if (target && target.tagName === "DETAILS") {
+      target.open = true;
+    }
+  });
+})();
@@ -0,0 +1,160 @@
+package swagger2
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Extensions holds vendor extensions. The field name MUST begin with x-, for example, x-internal-id. The value can be null, a primitive, an array or an object.
+type Extensions map[string]interface{}
+
+// Bool returns the value of a boolean extension, or false if it is missing or not a boolean.
+func (e Extensions) Bool(name string) bool {
+	b, _ := e[name].(bool)
+	return b
+}
+
+// String returns the value of a string extension, or "" if it is missing or not a string.
+func (e Extensions) String(name string) string {
+	s, _ := e[name].(string)
+	return s
+}
+
+// Int returns the value of an integer extension, or 0 if it is missing or not a whole number.
+func (e Extensions) Int(name string) int {
+	switch v := e[name].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
+	}
+	return 0
+}
+
+// vendorOnly removes any fields that are not vendor extensions, which YAML decoding collects alongside them.
+func (e Extensions) vendorOnly() Extensions {
+	for k := range e {
+		if !strings.HasPrefix(k, "x-") {
+			delete(e, k)
+		}
+	}
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// marshalWithExtensions serializes v to a JSON object and appends the extensions to it.
+func marshalWithExtensions(v interface{}, ext Extensions) ([]byte, error) {
+	buf, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return buf, err
+	}
+	keys := make([]string, 0, len(ext))
+	for k := range ext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var out bytes.Buffer
+	out.Write(buf[:len(buf)-1])
+	for i, k := range keys {
+		if i > 0 || len(buf) > 2 {
+			out.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		value, err := json.Marshal(ext[k])
+		if err != nil {
+			return nil, err
+		}
+		out.Write(name)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// unmarshalExtensions collects the vendor extensions from a JSON object.
+func unmarshalExtensions(in []byte) (Extensions, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(in, &fields); err != nil {
+		return nil, err
+	}
+	var ext Extensions
+	for k, raw := range fields {
+		if !strings.HasPrefix(k, "x-") {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		if ext == nil {
+			ext = make(Extensions)
+		}
+		ext[k] = v
+	}
+	return ext, nil
+}
+
+// MarshalJSON writes the schema along with its vendor extensions
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	return marshalWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalJSON reads the schema along with its vendor extensions
+func (s *Schema) UnmarshalJSON(in []byte) error {
+	type plain Schema
+	if err := json.Unmarshal(in, (*plain)(s)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(in)
+	s.Extensions = ext
+	return err
+}
+
+// UnmarshalYAML reads the schema along with its vendor extensions
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Schema
+	if err := unmarshal((*plain)(s)); err != nil {
+		return err
+	}
+	s.Extensions = s.Extensions.vendorOnly()
+	return nil
+}
+
+// MarshalJSON writes the operation along with its vendor extensions
+func (s Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	return marshalWithExtensions(plain(s), s.Extensions)
+}
+
+// UnmarshalJSON reads the operation along with its vendor extensions
+func (s *Operation) UnmarshalJSON(in []byte) error {
+	type plain Operation
+	if err := json.Unmarshal(in, (*plain)(s)); err != nil {
+		return err
+	}
+	ext, err := unmarshalExtensions(in)
+	s.Extensions = ext
+	return err
+}
+
+// UnmarshalYAML reads the operation along with its vendor extensions
+func (s *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Operation
+	if err := unmarshal((*plain)(s)); err != nil {
+		return err
+	}
+	s.Extensions = s.Extensions.vendorOnly()
+	return nil
+}
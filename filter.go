@@ -0,0 +1,219 @@
+package swagger2
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// FilterOptions selects the operations that Filter keeps. An operation is kept when it matches all the options
+// that are given, and it matches an option when it matches any of its values.
+type FilterOptions struct {
+	Tags       []string            // Tags of the operations, which need only one of them
+	Paths      []string            // Patterns of the paths, where * matches a segment and ** any number of them, such as "/pets/**"
+	Methods    []string            // HTTP methods of the operations, such as "get"
+	Extensions map[string][]string // Values of vendor extensions, such as "public" for x-audience, which may also hold a list of values
+}
+
+// Filter returns a copy of a document with only the operations that match the options, such as the public subset
+// of an internal API. Paths left without operations are removed, and so are the definitions, shared parameters
+// and responses, tags and security definitions that the remaining operations no longer use. Since operations
+// hold their responses rather than references to them, a shared response is kept when a remaining operation has
+// the same response. The security definitions of the security of the document are always kept.
+func Filter(s *Swagger, opts FilterOptions) (*Swagger, error) {
+	filtered, err := clone(s)
+	if err != nil {
+		return nil, err
+	}
+	for name, item := range filtered.Paths {
+		kept := false
+		for _, m := range Methods {
+			if op := item.Operation(m); op != nil {
+				if opts.match(name, m, op) {
+					kept = true
+				} else {
+					item.SetOperation(m, nil)
+				}
+			}
+		}
+		if kept {
+			filtered.Paths[name] = item
+		} else {
+			delete(filtered.Paths, name)
+		}
+	}
+	filtered.prune()
+	return filtered, nil
+}
+
+// match tells whether an operation matches the options
+func (opts FilterOptions) match(p, method string, op *Operation) bool {
+	if len(opts.Tags) > 0 && !matchAny(opts.Tags, func(tag string) bool { return lists.Contains(op.Tags, tag) }) {
+		return false
+	}
+	if len(opts.Paths) > 0 && !matchAny(opts.Paths, func(pattern string) bool { return matchPath(pattern, p) }) {
+		return false
+	}
+	if len(opts.Methods) > 0 && !matchAny(opts.Methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+		return false
+	}
+	for name, values := range opts.Extensions {
+		var have []string
+		switch v := op.Extensions[name].(type) {
+		case nil:
+		case []interface{}:
+			for _, e := range v {
+				have = append(have, fmt.Sprint(e))
+			}
+		default:
+			have = []string{fmt.Sprint(v)}
+		}
+		if !matchAny(values, func(value string) bool { return lists.Contains(have, value) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAny tells whether any of the values matches
+func matchAny(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath tells whether a path matches a pattern, where each segment is matched with path.Match and "**" matches
+// any number of segments
+func matchPath(pattern, p string) bool {
+	var match func(patterns, segments []string) bool
+	match = func(patterns, segments []string) bool {
+		if len(patterns) == 0 {
+			return len(segments) == 0
+		}
+		if patterns[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if match(patterns[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		ok, _ := path.Match(patterns[0], segments[0])
+		return ok && match(patterns[1:], segments[1:])
+	}
+	return match(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(strings.Trim(p, "/"), "/"))
+}
+
+// prune removes the definitions, shared parameters and responses, tags and security definitions that the
+// operations do not use
+func (s *Swagger) prune() {
+	definitions, parameters, responses := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	tags, security := make(map[string]bool), make(map[string]bool)
+	var walk func(schema *Schema)
+	walk = func(schema *Schema) {
+		eachRef(schema, func(ref *string) {
+			if name, ok := s.Definitions.RefName(*ref); ok && !definitions[name] {
+				definitions[name] = true
+				if def, found := s.Definitions[name]; found {
+					walk(&def)
+				}
+			}
+		})
+	}
+	param := func(p Parameter) {
+		if section, name := ParseRef(p.Ref); section == "parameters" {
+			if shared, ok := s.Parameters[name]; ok && !parameters[name] {
+				parameters[name] = true
+				walk(shared.Schema)
+			}
+			return
+		}
+		walk(p.Schema)
+		walk(&Schema{ItemsDef: p.ItemsDef})
+	}
+	secure := func(list []Security) {
+		for _, sec := range list {
+			for name := range sec {
+				security[name] = true
+			}
+		}
+	}
+	secure(s.Security)
+	for _, item := range s.Paths {
+		for _, p := range item.Parameters {
+			param(p)
+		}
+		for _, m := range Methods {
+			op := item.Operation(m)
+			if op == nil {
+				continue
+			}
+			for _, p := range op.Parameters {
+				param(p)
+			}
+			for _, r := range op.Responses {
+				walk(r.Schema)
+				for name, shared := range s.Responses {
+					if !responses[name] && reflect.DeepEqual(plain(r), plain(shared)) {
+						responses[name] = true
+					}
+				}
+			}
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+			secure(op.Security)
+		}
+	}
+	for name := range s.Definitions {
+		if !definitions[name] {
+			delete(s.Definitions, name)
+		}
+	}
+	for name := range s.Parameters {
+		if !parameters[name] {
+			delete(s.Parameters, name)
+		}
+	}
+	for name := range s.Responses {
+		if !responses[name] {
+			delete(s.Responses, name)
+		}
+	}
+	for name := range s.SecurityDefinitions {
+		if !security[name] {
+			delete(s.SecurityDefinitions, name)
+		}
+	}
+	kept := make([]Tag, 0, len(s.Tags))
+	for _, t := range s.Tags {
+		if tags[t.Name] {
+			kept = append(kept, t)
+		}
+	}
+	s.Tags = kept
+	if len(s.Definitions) == 0 {
+		s.Definitions = nil
+	}
+	if len(s.Parameters) == 0 {
+		s.Parameters = nil
+	}
+	if len(s.Responses) == 0 {
+		s.Responses = nil
+	}
+	if len(s.SecurityDefinitions) == 0 {
+		s.SecurityDefinitions = nil
+	}
+	if len(s.Tags) == 0 {
+		s.Tags = nil
+	}
+}
@@ -0,0 +1,144 @@
+package swagger2
+
+import (
+	"reflect"
+	"testing"
+)
+
+const filterShelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+  oauth: {type: oauth2, flow: implicit, authorizationUrl: "https://example.com/auth", scopes: {admin: Administration.}}
+tags:
+  - {name: pets}
+  - {name: admin}
+parameters:
+  limit: {name: limit, in: query, type: integer}
+  force: {name: force, in: query, type: boolean}
+responses:
+  NotFound: {description: Not found., schema: {$ref: "#/definitions/Error"}}
+  Forbidden: {description: Forbidden.}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      x-audience: [public, partner]
+      parameters:
+        - $ref: "#/parameters/limit"
+      responses:
+        200: {description: OK, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+    post:
+      tags: [pets]
+      x-audience: partner
+      security:
+        - key: []
+      parameters:
+        - {name: pet, in: body, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201: {description: Created.}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+    get:
+      tags: [pets]
+      x-audience: public
+      responses:
+        200: {description: OK, schema: {$ref: "#/definitions/Pet"}}
+        404: {description: Not found., schema: {$ref: "#/definitions/Error"}}
+    delete:
+      tags: [admin]
+      security:
+        - oauth: [admin]
+      parameters:
+        - $ref: "#/parameters/force"
+      responses:
+        204: {description: Deleted.}
+        403: {description: Forbidden.}
+  /admin/stats:
+    get:
+      tags: [admin]
+      responses:
+        200: {description: OK, schema: {$ref: "#/definitions/Stats"}}
+definitions:
+  Pet:
+    type: object
+    properties:
+      owner: {$ref: "#/definitions/Owner"}
+  Owner:
+    type: object
+    properties:
+      name: {type: string}
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+  Stats:
+    type: object
+    properties:
+      count: {type: integer}
+`
+
+func TestFilter(t *testing.T) {
+	swag, err := LoadYaml([]byte(filterShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	public, err := Filter(swag, FilterOptions{Extensions: map[string][]string{"x-audience": {"public"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := public.Validate(); len(errs) > 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if len(public.Paths) != 2 || public.Paths["/pets"].Post != nil || public.Paths["/pets/{id}"].Delete != nil || len(public.Paths["/pets/{id}"].Parameters) != 1 {
+		t.Errorf("unexpected paths %+v", public.Paths)
+	}
+	if names := keys(public.Definitions, public.Parameters, public.Responses, public.SecurityDefinitions); !reflect.DeepEqual(names, []string{"Error", "NotFound", "Owner", "Pet", "limit"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if len(public.Tags) != 1 || public.Tags[0].Name != "pets" {
+		t.Errorf("unexpected tags %+v", public.Tags)
+	}
+	if len(swag.Paths) != 3 || swag.Paths["/pets"].Post == nil {
+		t.Error("the document was modified")
+	}
+
+	admin, err := Filter(swag, FilterOptions{Paths: []string{"/pets/*", "/admin/**"}, Methods: []string{"DELETE", "get"}, Tags: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := admin.Validate(); len(errs) > 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if len(admin.Paths) != 2 || admin.Paths["/pets/{id}"].Get != nil || admin.Paths["/admin/stats"].Get == nil {
+		t.Errorf("unexpected paths %+v", admin.Paths)
+	}
+	if names := keys(admin.Definitions, admin.Parameters, admin.Responses, admin.SecurityDefinitions); !reflect.DeepEqual(names, []string{"Forbidden", "Stats", "force", "oauth"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"/pets", "/pets", true},
+		{"/pets/*", "/pets/{id}", true},
+		{"/pets/*", "/pets", false},
+		{"/pets/*", "/pets/{id}/photos", false},
+		{"/pets/**", "/pets", true},
+		{"/pets/**", "/pets/{id}/photos", true},
+		{"/**/photos", "/pets/{id}/photos", true},
+		{"/p*", "/pets", true},
+		{"/**", "/", true},
+	}
+	for _, test := range tests {
+		if matchPath(test.pattern, test.path) != test.match {
+			t.Errorf("%s %s: expected %v", test.pattern, test.path, test.match)
+		}
+	}
+}
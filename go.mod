@@ -1,5 +1,5 @@
 module github.com/babelrpc/swagger2
 
-go 1.15
+go 1.16
 
 require gopkg.in/yaml.v2 v2.4.0
@@ -0,0 +1,463 @@
+package gogen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// Client generates a Go source file with an HTTP client for the document's operations. It belongs in the same
+// package as the output of Types, and declares any types its parameters and responses need that are not
+// definitions. The output is gofmt'd.
+func Client(swag *swagger2.Swagger, opts Options) ([]byte, error) {
+	g := newGenerator(swag, opts)
+	// declare the types first so that the names they take are not reused, then start the file afresh
+	g.types()
+	g.decls.Reset()
+	g.imports = map[string]bool{
+		"bytes": true, "context": true, "encoding/base64": true, "encoding/json": true, "encoding/xml": true,
+		"fmt": true, "io": true, "io/ioutil": true, "mime/multipart": true, "net/http": true, "net/url": true,
+		"reflect": true, "strings": true, "time": true,
+	}
+	c := &clientGen{generator: g, creds: make(map[string][]string)}
+	c.client()
+	g.flush()
+	return g.source()
+}
+
+// clientGen writes the client for a document
+type clientGen struct {
+	*generator
+	errorType string              // name of the error returned for undeclared status codes
+	creds     map[string][]string // security definition to the client fields holding its credentials
+}
+
+// locals are the names used inside generated methods, which parameters must not take
+var locals = []string{"c", "ctx", "params", "path", "query", "header", "form", "files", "body", "contentType", "resp", "err", "out"}
+
+// client writes the client type, its operations and the helpers they use
+func (c *clientGen) client() {
+	title := c.swag.Info.Title
+	if title == "" {
+		title = "the API"
+	}
+	baseURL := c.ids.unique("DefaultBaseURL")
+	clientType := c.ids.unique("Client")
+	c.errorType = c.ids.unique("ResponseError")
+	fmt.Fprintf(&c.decls, "// %s is where the API is served according to its document.\n", baseURL)
+	fmt.Fprintf(&c.decls, "const %s = %q\n\n", baseURL, c.swag.BaseURL())
+
+	fmt.Fprintf(&c.decls, "// %s calls the operations of %s.\n", clientType, title)
+	fmt.Fprintf(&c.decls, "type %s struct {\n", clientType)
+	c.decls.WriteString("\tBaseURL    string       // Where the API is served, such as " + baseURL + "\n")
+	c.decls.WriteString("\tHTTPClient *http.Client // Sends the requests; http.DefaultClient is used if nil\n")
+	c.credentialFields()
+	c.decls.WriteString("}\n\n")
+
+	fmt.Fprintf(&c.decls, "// New%s returns a client for the API served at baseURL.\n", clientType)
+	fmt.Fprintf(&c.decls, "func New%s(baseURL string) *%s {\n\treturn &%s{BaseURL: baseURL}\n}\n\n", clientType, clientType, clientType)
+
+	fmt.Fprintf(&c.decls, "// %s is returned when the server responds with a status code the operation does not declare.\n", c.errorType)
+	fmt.Fprintf(&c.decls, "type %s struct {\n\tStatusCode int\n\tHeader     http.Header\n\tBody       []byte\n}\n\n", c.errorType)
+	fmt.Fprintf(&c.decls, "func (e *%s) Error() string {\n\treturn fmt.Sprintf(\"unexpected status %%d: %%s\", e.StatusCode, e.Body)\n}\n\n", c.errorType)
+
+	for _, o := range c.operations(locals...) {
+		c.operation(clientType, o)
+	}
+	c.authorize(clientType)
+	c.decls.WriteString(strings.Replace(strings.Replace(clientRuntime, "CLIENT", clientType, -1), "ERROR", c.errorType, -1))
+}
+
+// securityNames returns the names of the security definitions in sorted order
+func (c *clientGen) securityNames() []string {
+	names := make([]string, 0, len(c.swag.SecurityDefinitions))
+	for n := range c.swag.SecurityDefinitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// credentialFields writes a client field for each credential the security definitions need
+func (c *clientGen) credentialFields() {
+	fields := namer{"BaseURL": true, "HTTPClient": true}
+	for _, name := range c.securityNames() {
+		def := c.swag.SecurityDefinitions[name]
+		switch def.Type {
+		case "apiKey":
+			f := fields.unique(exported(name))
+			where := "header"
+			if def.In == "query" {
+				where = "query parameter"
+			}
+			fmt.Fprintf(&c.decls, "\t%s string // Sent in the %s %s for the %s security definition\n", f, def.Name, where, name)
+			c.creds[name] = []string{f}
+		case "basic":
+			user, pass := fields.unique(exported(name)+"Username"), fields.unique(exported(name)+"Password")
+			fmt.Fprintf(&c.decls, "\t%s string // Sent with basic authentication for the %s security definition\n", user, name)
+			fmt.Fprintf(&c.decls, "\t%s string\n", pass)
+			c.creds[name] = []string{user, pass}
+		case "oauth2":
+			f := fields.unique(exported(name) + "Token")
+			fmt.Fprintf(&c.decls, "\t%s string // An OAuth2 access token sent as a bearer token for the %s security definition\n", f, name)
+			c.creds[name] = []string{f}
+		}
+	}
+}
+
+// authorize writes the methods that apply credentials to a request
+func (c *clientGen) authorize(clientType string) {
+	c.decls.WriteString("// authorize applies the credentials for the first of the alternative security requirements the client has them for\n")
+	fmt.Fprintf(&c.decls, "func (c *%s) authorize(req *http.Request, security [][]string) {\n", clientType)
+	c.decls.WriteString("\tfor _, schemes := range security {\n\t\tok := true\n\t\tfor _, s := range schemes {\n\t\t\tok = ok && c.hasCredentials(s)\n\t\t}\n")
+	c.decls.WriteString("\t\tif ok {\n\t\t\tfor _, s := range schemes {\n\t\t\t\tc.applyCredentials(req, s)\n\t\t\t}\n\t\t\treturn\n\t\t}\n\t}\n}\n\n")
+
+	c.decls.WriteString("// hasCredentials returns true if the client has the credentials for a security definition\n")
+	fmt.Fprintf(&c.decls, "func (c *%s) hasCredentials(scheme string) bool {\n\tswitch scheme {\n", clientType)
+	for _, name := range c.securityNames() {
+		if fields := c.creds[name]; fields != nil {
+			fmt.Fprintf(&c.decls, "\tcase %q:\n\t\treturn c.%s != \"\"\n", name, fields[0])
+		}
+	}
+	c.decls.WriteString("\t}\n\treturn false\n}\n\n")
+
+	c.decls.WriteString("// applyCredentials adds the credentials for a security definition to a request\n")
+	fmt.Fprintf(&c.decls, "func (c *%s) applyCredentials(req *http.Request, scheme string) {\n\tswitch scheme {\n", clientType)
+	for _, name := range c.securityNames() {
+		def := c.swag.SecurityDefinitions[name]
+		fields := c.creds[name]
+		if fields == nil {
+			continue
+		}
+		fmt.Fprintf(&c.decls, "\tcase %q:\n", name)
+		switch {
+		case def.Type == "apiKey" && def.In == "query":
+			fmt.Fprintf(&c.decls, "\t\tq := req.URL.Query()\n\t\tq.Set(%q, c.%s)\n\t\treq.URL.RawQuery = q.Encode()\n", def.Name, fields[0])
+		case def.Type == "apiKey":
+			fmt.Fprintf(&c.decls, "\t\treq.Header.Set(%q, c.%s)\n", def.Name, fields[0])
+		case def.Type == "basic":
+			fmt.Fprintf(&c.decls, "\t\treq.SetBasicAuth(c.%s, c.%s)\n", fields[0], fields[1])
+		default:
+			fmt.Fprintf(&c.decls, "\t\treq.Header.Set(\"Authorization\", \"Bearer \"+c.%s)\n", fields[0])
+		}
+	}
+	c.decls.WriteString("\t}\n}\n\n")
+}
+
+// operation writes the optional parameters and response types of an operation, and the method that calls it
+func (c *clientGen) operation(clientType string, o *operation) {
+	optional := o.optional()
+	paramsType := ""
+	if len(optional) > 0 {
+		paramsType = c.ids.unique(o.id + "Params")
+		fmt.Fprintf(&c.decls, "// %s holds the optional parameters of %s.\n", paramsType, o.id)
+		fmt.Fprintf(&c.decls, "type %s struct {\n", paramsType)
+		for _, p := range optional {
+			c.decls.WriteString(comment("\t", p.field, p.Description, ""))
+			fmt.Fprintf(&c.decls, "\t%s %s\n", p.field, fieldType(p.typ))
+		}
+		c.decls.WriteString("}\n\n")
+	}
+
+	responseType := c.ids.unique(o.id + "Response")
+	fmt.Fprintf(&c.decls, "// %s holds the result of %s. The field for the status code the server returned is set.\n", responseType, o.id)
+	fmt.Fprintf(&c.decls, "type %s struct {\n\tStatusCode int\n\tHeader     http.Header\n", responseType)
+	for _, r := range o.results {
+		if r.typ != "" {
+			c.decls.WriteString(comment("\t", r.field, r.Description, ""))
+			fmt.Fprintf(&c.decls, "\t%s %s\n", r.field, fieldType(r.typ))
+		}
+	}
+	c.decls.WriteString("}\n\n")
+
+	// the method takes the required parameters as arguments, followed by the optional ones
+	args := []string{"ctx context.Context"}
+	for _, p := range o.params {
+		if p.required {
+			args = append(args, p.arg+" "+p.typ)
+		}
+	}
+	if o.body != nil && o.body.required {
+		args = append(args, o.body.arg+" "+o.body.typ)
+	}
+	if paramsType != "" {
+		args = append(args, "params *"+paramsType)
+	}
+	c.decls.WriteString(o.doc(""))
+	fmt.Fprintf(&c.decls, "func (c *%s) %s(%s) (*%s, error) {\n", clientType, o.id, strings.Join(args, ", "), responseType)
+	if paramsType != "" {
+		fmt.Fprintf(&c.decls, "\tif params == nil {\n\t\tparams = &%s{}\n\t}\n", paramsType)
+	}
+	fmt.Fprintf(&c.decls, "\tpath := %q\n\tquery := make(url.Values)\n\theader := make(http.Header)\n", o.path)
+
+	form, files := false, false
+	for _, p := range o.params {
+		form = form || p.In == "formData"
+		files = files || p.Type == "file"
+	}
+	if form {
+		c.decls.WriteString("\tform := make(url.Values)\n")
+	}
+	if files {
+		c.decls.WriteString("\tfiles := make(map[string]io.Reader)\n")
+	}
+	for _, p := range o.params {
+		c.setParam(p)
+	}
+
+	c.decls.WriteString("\tvar body io.Reader\n")
+	switch {
+	case o.body != nil:
+		ct := mediaType(o.consumes, "application/json")
+		value := c.value(o.body)
+		if o.body.required {
+			c.decls.WriteString("\t{\n")
+		} else {
+			fmt.Fprintf(&c.decls, "\tif %s != nil {\n", value)
+		}
+		fmt.Fprintf(&c.decls, "\t\tvar err error\n\t\tif body, err = encodeBody(%q, %s); err != nil {\n\t\t\treturn nil, err\n\t\t}\n", ct, value)
+		fmt.Fprintf(&c.decls, "\t\theader.Set(\"Content-Type\", %q)\n\t}\n", ct)
+	case form:
+		ct := "application/x-www-form-urlencoded"
+		for _, t := range o.consumes {
+			if strings.HasPrefix(t, "multipart/") {
+				ct = t
+			}
+		}
+		filesArg := "nil"
+		if files {
+			filesArg = "files"
+		}
+		c.decls.WriteString("\t{\n\t\tvar contentType string\n\t\tvar err error\n")
+		fmt.Fprintf(&c.decls, "\t\tif body, contentType, err = encodeForm(%q, form, %s); err != nil {\n\t\t\treturn nil, err\n\t\t}\n", ct, filesArg)
+		c.decls.WriteString("\t\theader.Set(\"Content-Type\", contentType)\n\t}\n")
+	}
+	if len(o.produces) > 0 {
+		fmt.Fprintf(&c.decls, "\theader.Set(\"Accept\", %q)\n", strings.Join(o.produces, ", "))
+	}
+
+	fmt.Fprintf(&c.decls, "\tresp, err := c.do(ctx, %q, path, query, header, body, %s)\n", strings.ToUpper(o.method), securityLiteral(o.security))
+	c.decls.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n\tdefer resp.Body.Close()\n")
+	fmt.Fprintf(&c.decls, "\tout := &%s{StatusCode: resp.StatusCode, Header: resp.Header}\n", responseType)
+	c.decls.WriteString("\tswitch resp.StatusCode {\n")
+	var fallback *result
+	for _, r := range o.results {
+		if r.status == 0 {
+			fallback = r
+			continue
+		}
+		fmt.Fprintf(&c.decls, "\tcase %d:\n", r.status)
+		if r.typ != "" {
+			fmt.Fprintf(&c.decls, "\t\terr = decodeBody(resp, &out.%s)\n", r.field)
+		}
+	}
+	c.decls.WriteString("\tdefault:\n")
+	if fallback != nil && fallback.typ != "" {
+		fmt.Fprintf(&c.decls, "\t\terr = decodeBody(resp, &out.%s)\n", fallback.field)
+	} else if fallback == nil {
+		fmt.Fprintf(&c.decls, "\t\treturn nil, newResponseError(resp)\n")
+	}
+	c.decls.WriteString("\t}\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n}\n\n")
+}
+
+// value is the expression that holds a parameter's value in the generated method
+func (c *clientGen) value(p *param) string {
+	if p.required {
+		return p.arg
+	}
+	return "params." + p.field
+}
+
+// setParam writes the code that places a parameter in the request
+func (c *clientGen) setParam(p *param) {
+	value := c.value(p)
+	indent := "\t"
+	if !p.required {
+		fmt.Fprintf(&c.decls, "\tif %s != nil {\n", value)
+		indent = "\t\t"
+		if nillable(p.typ) {
+			value = "*" + value
+		}
+	}
+	text := "formatValue(" + value + ")"
+	if sep := p.separator(); sep != "" {
+		text = fmt.Sprintf("strings.Join(formatValues(%s), %q)", value, sep)
+	}
+	target := map[string]string{"query": "query", "formData": "form"}[p.In]
+	switch {
+	case p.Type == "file":
+		fmt.Fprintf(&c.decls, "%sfiles[%q] = %s\n", indent, p.Name, value)
+	case p.In == "path":
+		fmt.Fprintf(&c.decls, "%spath = strings.Replace(path, %q, url.PathEscape(%s), 1)\n", indent, "{"+p.Name+"}", text)
+	case p.In == "header":
+		fmt.Fprintf(&c.decls, "%sheader.Set(%q, %s)\n", indent, p.Name, text)
+	case p.Type == "array" && p.separator() == "":
+		fmt.Fprintf(&c.decls, "%sfor _, v := range formatValues(%s) {\n%s\t%s.Add(%q, v)\n%s}\n", indent, value, indent, target, p.Name, indent)
+	default:
+		fmt.Fprintf(&c.decls, "%s%s.Set(%q, %s)\n", indent, target, p.Name, text)
+	}
+	if !p.required {
+		c.decls.WriteString("\t}\n")
+	}
+}
+
+// fieldType is the type of an optional value, which is a pointer unless the type can already be nil
+func fieldType(t string) string {
+	if nillable(t) {
+		return "*" + t
+	}
+	return t
+}
+
+// securityLiteral writes the alternative security requirements of an operation as a [][]string literal
+func securityLiteral(security []swagger2.Security) string {
+	if len(security) == 0 {
+		return "nil"
+	}
+	alternatives := make([]string, 0, len(security))
+	for _, s := range security {
+		names := make([]string, 0, len(s))
+		for n := range s {
+			names = append(names, fmt.Sprintf("%q", n))
+		}
+		sort.Strings(names)
+		alternatives = append(alternatives, "{"+strings.Join(names, ", ")+"}")
+	}
+	return "[][]string{" + strings.Join(alternatives, ", ") + "}"
+}
+
+// clientRuntime holds the helpers used by every generated client. CLIENT and ERROR are replaced with the names of
+// the client and error types.
+const clientRuntime = `// do sends a request to the API
+func (c *CLIENT) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, security [][]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.BaseURL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	c.authorize(req, security)
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// newResponseError reads the body of a response with an undeclared status code into an error
+func newResponseError(resp *http.Response) error {
+	b, _ := ioutil.ReadAll(resp.Body)
+	return &ERROR{StatusCode: resp.StatusCode, Header: resp.Header, Body: b}
+}
+
+// formatValue writes a parameter value as text
+func formatValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	switch t := rv.Interface().(type) {
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// formatValues writes each element of a slice as text
+func formatValues(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	out := make([]string, rv.Len())
+	for i := range out {
+		out[i] = formatValue(rv.Index(i).Interface())
+	}
+	return out
+}
+
+// encodeBody encodes a request body in the given media type
+func encodeBody(mediaType string, v interface{}) (io.Reader, error) {
+	switch {
+	case strings.Contains(mediaType, "json"):
+		b, err := json.Marshal(v)
+		return bytes.NewReader(b), err
+	case strings.Contains(mediaType, "xml"):
+		b, err := xml.Marshal(v)
+		return bytes.NewReader(b), err
+	}
+	switch t := v.(type) {
+	case io.Reader:
+		return t, nil
+	case []byte:
+		return bytes.NewReader(t), nil
+	}
+	return strings.NewReader(formatValue(v)), nil
+}
+
+// encodeForm encodes form parameters, using multipart/form-data if there are files. It returns the content type.
+func encodeForm(mediaType string, form url.Values, files map[string]io.Reader) (io.Reader, string, error) {
+	if !strings.HasPrefix(mediaType, "multipart/") && len(files) == 0 {
+		return strings.NewReader(form.Encode()), mediaType, nil
+	}
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name, values := range form {
+		for _, v := range values {
+			if err := w.WriteField(name, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for name, r := range files {
+		part, err := w.CreateFormFile(name, name)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(part, r); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, w.FormDataContentType(), nil
+}
+
+// decodeBody decodes a response body according to its content type
+func decodeBody(resp *http.Response, v interface{}) error {
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil || len(b) == 0 {
+		return err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "json"):
+		return json.Unmarshal(b, v)
+	case strings.Contains(contentType, "xml"):
+		return xml.Unmarshal(b, v)
+	}
+	switch t := v.(type) {
+	case *[]byte:
+		*t = b
+		return nil
+	case *string:
+		*t = string(b)
+		return nil
+	case **string:
+		s := string(b)
+		*t = &s
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+`
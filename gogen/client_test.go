@@ -0,0 +1,208 @@
+package gogen
+
+import (
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestClientBuild(t *testing.T) {
+	goTool(t)
+	for file, swag := range loadExamples(t) {
+		types, err := Types(swag, Options{})
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		client, err := Client(swag, Options{})
+		if err != nil {
+			t.Errorf("%s: %s\n%s", file, err, client)
+			continue
+		}
+		dir := writeModule(t, map[string][]byte{"api/types.go": types, "api/client.go": client})
+		goCommand(t, dir, "vet", "./...")
+	}
+}
+
+const store = `
+swagger: "2.0"
+info: {title: Store, version: "1.0"}
+basePath: /v1
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  api_key: {type: apiKey, name: X-API-Key, in: header}
+  basic: {type: basic}
+  oauth: {type: oauth2, flow: application, tokenUrl: "http://example.com/token", scopes: {}}
+security:
+  - api_key: []
+  - oauth: []
+paths:
+  /pets/{petId}/photos/{index}:
+    get:
+      operationId: getPhoto
+      parameters:
+        - {name: petId, in: path, required: true, type: integer, format: int64}
+        - {name: index, in: path, required: true, type: integer}
+        - {name: sizes, in: query, type: array, items: {type: string}, collectionFormat: pipes}
+        - {name: tag, in: query, required: true, type: array, items: {type: string}, collectionFormat: multi}
+        - {name: X-Trace, in: header, type: string}
+      responses:
+        200:
+          description: The photo.
+          schema: {$ref: "#/definitions/Photo"}
+        404:
+          description: No such photo.
+          schema:
+            type: object
+            properties:
+              message: {type: string}
+  /pets:
+    post:
+      security: [{basic: []}]
+      parameters:
+        - {name: pet, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201:
+          description: Created.
+          schema: {$ref: "#/definitions/Pet"}
+  /pets/{petId}/upload:
+    post:
+      operationId: upload
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+        - {name: caption, in: formData, type: string}
+        - {name: file, in: formData, required: true, type: file}
+      responses:
+        204: {description: Uploaded.}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string}
+      status: {type: string, enum: [available, sold]}
+  Photo:
+    type: object
+    properties:
+      url: {type: string}
+`
+
+// storeTest runs the generated client against an httptest server
+const storeTest = `package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/pets/7/photos/2":
+			if r.URL.Query()["tag"][1] != "b" || r.URL.Query().Get("sizes") != "s|m" || r.Header.Get("X-Trace") != "abc" {
+				t.Errorf("unexpected parameters %v %v", r.URL.Query(), r.Header)
+			}
+			if r.Header.Get("X-API-Key") != "secret" || r.Header.Get("Authorization") != "" {
+				t.Errorf("expected only the API key, got %v", r.Header)
+			}
+			w.Write([]byte("{\"url\":\"http://example.com/7.png\"}"))
+		case r.URL.Path == "/v1/pets/8/photos/0":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("expected the oauth token, got %v", r.Header)
+			}
+			w.WriteHeader(404)
+			w.Write([]byte("{\"message\":\"no photo\"}"))
+		case r.URL.Path == "/v1/pets" && r.Method == "POST":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "u" || pass != "p" {
+				t.Errorf("expected basic authentication, got %v", r.Header)
+			}
+			var pet Pet
+			if err := json.NewDecoder(r.Body).Decode(&pet); err != nil || pet.Name != "Rex" {
+				t.Errorf("unexpected body %v %v", pet, err)
+			}
+			w.WriteHeader(201)
+			json.NewEncoder(w).Encode(pet)
+		case r.URL.Path == "/v1/pets/3/upload":
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(f)
+			if string(b) != "PNG" || r.FormValue("caption") != "hi" {
+				t.Errorf("unexpected form %q %q", b, r.FormValue("caption"))
+			}
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(500)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := NewClient(srv.URL + "/v1")
+	c.APIKey = "secret"
+	trace := "abc"
+	res, err := c.GetPhoto(ctx, 7, 2, []string{"a", "b"}, &GetPhotoParams{Sizes: []string{"s", "m"}, XTrace: &trace})
+	if err != nil || res.StatusCode != 200 || res.OK == nil || *res.OK.URL != "http://example.com/7.png" {
+		t.Fatalf("unexpected result %+v %v", res, err)
+	}
+
+	c = &Client{BaseURL: srv.URL + "/v1", OauthToken: "token"}
+	res, err = c.GetPhoto(ctx, 8, 0, nil, nil)
+	if err != nil || res.NotFound == nil || *res.NotFound.Message != "no photo" || res.OK != nil {
+		t.Fatalf("expected a not found result, got %+v %v", res, err)
+	}
+
+	c = &Client{BaseURL: srv.URL + "/v1", BasicUsername: "u", BasicPassword: "p"}
+	status := PetStatusSold
+	created, err := c.PostPets(ctx, Pet{Name: "Rex", Status: &status})
+	if err != nil || created.Created == nil || *created.Created.Status != PetStatusSold {
+		t.Fatalf("unexpected result %+v %v", created, err)
+	}
+
+	caption := "hi"
+	if _, err := c.Upload(ctx, 3, strings.NewReader("PNG"), &UploadParams{Caption: &caption}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Upload(ctx, 4, strings.NewReader(""), nil)
+	if e, ok := err.(*ResponseError); !ok || e.StatusCode != 500 {
+		t.Fatalf("expected an error for an undeclared status, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.Upload(ctx, 3, strings.NewReader("PNG"), nil); err == nil {
+		t.Fatal("expected a cancelled context to stop the request")
+	}
+}
+`
+
+func TestClient(t *testing.T) {
+	goTool(t)
+	swag, err := swagger2.LoadYaml([]byte(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types, err := Types(swag, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := Client(swag, Options{})
+	if err != nil {
+		t.Fatalf("%s\n%s", err, client)
+	}
+	dir := writeModule(t, map[string][]byte{
+		"api/types.go":       types,
+		"api/client.go":      client,
+		"api/client_test.go": []byte(storeTest),
+	})
+	goCommand(t, dir, "test", "./...")
+}
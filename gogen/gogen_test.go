@@ -0,0 +1,90 @@
+package gogen
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/swagger12"
+)
+
+// loadExamples loads every example shipped with the parent package, importing the Swagger 1.2 one
+func loadExamples(t *testing.T) map[string]*swagger2.Swagger {
+	docs := make(map[string]*swagger2.Swagger)
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			docs[file] = swag
+		}
+	}
+	buf, err := ioutil.ReadFile("../examples/swagger12/api-docs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listing, err := swagger12.LoadListing(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swag, _, err := swagger12.Import(listing, swagger12.DirLoader("../examples/swagger12"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs["../examples/swagger12/api-docs.json"] = swag
+	return docs
+}
+
+// goTool returns the path of the go command, skipping the test if there is none
+func goTool(t *testing.T) string {
+	tool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	return tool
+}
+
+// writeModule writes the files into a new module in a temporary directory and returns the directory
+func writeModule(t *testing.T, files map[string][]byte) string {
+	dir := t.TempDir()
+	files["go.mod"] = []byte("module example.com/generated\n\ngo 1.15\n")
+	for name, buf := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, buf, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// goCommand runs the go command in a directory and fails the test with its output if it does not succeed
+func goCommand(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command(goTool(t), args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %v failed: %s\n%s", args, err, out)
+	}
+	return string(out)
+}
@@ -0,0 +1,123 @@
+package gogen
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case when they make up a whole word of an identifier
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "OK": true, "OS": true, "RPC": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// words splits a name into words on punctuation and on changes of case
+func words(name string) []string {
+	list := make([]string, 0)
+	word := make([]rune, 0)
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			list = append(list, string(word))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return list
+}
+
+// exported turns an arbitrary name into an exported Go identifier, such as "pet_id" into "PetID"
+func exported(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			r := []rune(w)
+			b.WriteString(string(unicode.ToUpper(r[0])) + strings.ToLower(string(r[1:])))
+		}
+	}
+	id := b.String()
+	if id == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// unexported turns an arbitrary name into an unexported Go identifier, such as "PetId" into "petID"
+func unexported(name string) string {
+	id := exported(name)
+	runes := []rune(id)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	switch {
+	case i == len(runes):
+		id = strings.ToLower(id)
+	case i > 1:
+		// lower the whole leading initialism except the start of the next word
+		id = strings.ToLower(string(runes[:i-1])) + string(runes[i-1:])
+	default:
+		id = strings.ToLower(string(runes[:1])) + string(runes[1:])
+	}
+	if token.Lookup(id).IsKeyword() {
+		id += "_"
+	}
+	return id
+}
+
+// namer hands out identifiers that are unique within a generated file
+type namer map[string]bool
+
+// unique returns the identifier, adding a number to it if it is already taken
+func (n namer) unique(id string) string {
+	candidate := id
+	for i := 2; n[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", id, i)
+	}
+	n[candidate] = true
+	return candidate
+}
+
+// comment formats a description as a Go comment starting with the identifier it documents
+func comment(indent, id, description, fallback string) string {
+	text := strings.TrimSpace(description)
+	if text == "" {
+		text = fallback
+	}
+	if text == "" {
+		return ""
+	}
+	if !strings.HasPrefix(text, id+" ") {
+		text = id + " " + text
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+"// "+strings.TrimRight(line, " \t\r"), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
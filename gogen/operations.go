@@ -0,0 +1,217 @@
+package gogen
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// operation is an operation with its parameters resolved and named for generated code
+type operation struct {
+	id       string // Go name of the operation
+	method   string
+	path     string
+	op       *swagger2.Operation
+	params   []*param // every parameter except the body, in path, query, header and formData order
+	body     *param
+	consumes []string
+	produces []string
+	results  []*result
+	security []swagger2.Security
+}
+
+// param is a parameter with the Go names and type used for it
+type param struct {
+	*swagger2.Parameter
+	arg      string // name of the method argument
+	field    string // name of the field in a struct of parameters
+	typ      string
+	required bool
+}
+
+// result is a response with the Go names and type used for it
+type result struct {
+	*swagger2.Response
+	code   string // status code, or "default"
+	status int    // 0 for the default response
+	field  string // name of the field holding the decoded body
+	typ    string // empty if the response has no body
+}
+
+// pathParam matches a templated path segment
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// operations collects the operations of the document sorted by path and method. Argument names are unique
+// within each operation and do not clash with the names in reserved.
+func (g *generator) operations(reserved ...string) []*operation {
+	paths := make([]string, 0, len(g.swag.Paths))
+	for p := range g.swag.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	ops := make([]*operation, 0)
+	for _, path := range paths {
+		item := g.swag.Paths[path]
+		for _, method := range swagger2.Methods {
+			if op := item.Operation(method); op != nil {
+				ops = append(ops, g.operation(path, method, &item, op, reserved))
+			}
+		}
+	}
+	return ops
+}
+
+// operation resolves a single operation
+func (g *generator) operation(path, method string, item *swagger2.PathItem, op *swagger2.Operation, reserved []string) *operation {
+	o := &operation{method: method, path: path, op: op, consumes: g.swag.Consumes, produces: g.swag.Produces, security: g.swag.Security}
+	if op.OperationId != "" {
+		o.id = g.ids.unique(exported(op.OperationId))
+	} else {
+		o.id = g.ids.unique(exported(method + " " + pathParam.ReplaceAllString(path, "by $1")))
+	}
+	if op.Consumes != nil {
+		o.consumes = op.Consumes
+	}
+	if op.Produces != nil {
+		o.produces = op.Produces
+	}
+	if op.Security != nil {
+		o.security = op.Security
+	}
+
+	params := g.swag.OperationParameters(path, item, op)
+	all := make([]*swagger2.Parameter, len(params))
+	for i := range params {
+		all[i] = &params[i]
+	}
+
+	args := make(namer)
+	for _, r := range reserved {
+		args[r] = true
+	}
+	fields := make(namer)
+	for _, p := range all {
+		pr := &param{
+			Parameter: p,
+			arg:       args.unique(unexported(p.Name)),
+			field:     fields.unique(exported(p.Name)),
+			required:  p.In == "path" || (p.Required != nil && *p.Required),
+		}
+		hint := o.id + exported(p.Name)
+		switch {
+		case p.In == "body" && p.Schema != nil:
+			pr.typ = g.schemaType(hint, p.Schema)
+		case p.Type == "file":
+			g.imports["io"] = true
+			pr.typ = "io.Reader"
+		default:
+			pr.typ = g.itemsType(hint, &p.ItemsDef)
+		}
+		if p.In == "body" {
+			o.body = pr
+		} else {
+			o.params = append(o.params, pr)
+		}
+	}
+	if o.body != nil {
+		// form parameters cannot be sent with a body
+		params := o.params[:0]
+		for _, p := range o.params {
+			if p.In != "formData" {
+				params = append(params, p)
+			}
+		}
+		o.params = params
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	results := make(namer)
+	for _, code := range codes {
+		r := op.Responses[code]
+		res := &result{Response: &r, code: code}
+		if code == "default" {
+			res.field = results.unique("Default")
+		} else {
+			res.status, _ = strconv.Atoi(code)
+			res.field = results.unique(statusName(res.status))
+		}
+		if r.Schema != nil {
+			res.typ = g.schemaType(o.id+res.field, r.Schema)
+		}
+		o.results = append(o.results, res)
+	}
+	return o
+}
+
+// statusName names a status code after its text, such as "NotFound" for 404
+func statusName(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return exported(text)
+	}
+	return "Status" + strconv.Itoa(status)
+}
+
+// separator returns the text that separates the values of an array parameter, or "" if the parameter is not an
+// array or each value is sent as a separate parameter
+func (p *param) separator() string {
+	if p.Type != "array" || (p.CollectionFormat == "multi" && (p.In == "query" || p.In == "formData")) {
+		return ""
+	}
+	if sep, ok := map[string]string{"ssv": " ", "tsv": "\t", "pipes": "|"}[p.CollectionFormat]; ok {
+		return sep
+	}
+	return ","
+}
+
+// optional returns the parameters that are not required
+func (o *operation) optional() []*param {
+	list := make([]*param, 0)
+	for _, p := range o.params {
+		if !p.required {
+			list = append(list, p)
+		}
+	}
+	if o.body != nil && !o.body.required {
+		list = append(list, o.body)
+	}
+	return list
+}
+
+// doc documents the method for an operation with its summary and description
+func (o *operation) doc(indent string) string {
+	text := strings.TrimSpace(o.op.Summary)
+	if desc := strings.TrimSpace(o.op.Description); desc != "" && desc != text {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += desc
+	}
+	if text == "" {
+		text = "is the operation at " + strings.ToUpper(o.method) + " " + o.path + "."
+	}
+	if o.op.Deprecated {
+		text += "\n\nDeprecated: the operation is deprecated by the API."
+	}
+	return comment(indent, o.id, text, "")
+}
+
+// mediaType picks the media type to use from a list, preferring JSON
+func mediaType(types []string, fallback string) string {
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			return t
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return fallback
+}
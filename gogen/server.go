@@ -0,0 +1,812 @@
+package gogen
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// Server generates a Go source file with a Server interface for the document's operations and an http.Handler
+// that serves an implementation of it. It belongs in the same package as the output of Types, and declares any
+// types its parameters and responses need that are not definitions. The handler checks parameters against the
+// constraints of the document. Bodies are checked for the types of their values when they are decoded and, in JSON,
+// for their required properties; other constraints on bodies, such as enums and ranges, are left to the
+// implementation. Patterns that Go's regexp package cannot compile, such as those with lookaheads, are not checked
+// and are listed in the error, which is returned with the source. The output is gofmt'd.
+func Server(swag *swagger2.Swagger, opts Options) ([]byte, error) {
+	g := newGenerator(swag, opts)
+	// declare the types first so that the names they take are not reused, then start the file afresh
+	g.types()
+	g.decls.Reset()
+	g.imports = map[string]bool{
+		"context": true, "encoding/base64": true, "encoding/json": true, "encoding/xml": true, "errors": true,
+		"fmt": true, "io": true, "io/ioutil": true, "net/http": true, "reflect": true, "regexp": true,
+		"strconv": true, "strings": true, "time": true,
+	}
+	s := &serverGen{generator: g, requests: make(map[*operation]string), responses: make(map[*operation]string), shapes: make(map[string]string)}
+	s.server()
+	g.flush()
+	src, err := g.source()
+	if err == nil && len(s.dropped) > 0 {
+		err = errors.New(strings.Join(s.dropped, "\n"))
+	}
+	return src, err
+}
+
+// serverGen writes the server for a document
+type serverGen struct {
+	*generator
+	serverType string
+	notImpl    string                // name of the error returned by unimplemented operations
+	requests   map[*operation]string // name of the request type of each operation
+	responses  map[*operation]string // name of the response type of each operation
+	shapes     map[string]string     // name of the variable holding the shape of each definition a body uses
+	shapeInit  strings.Builder       // statements that fill in the shapes of definitions, which may refer to each other
+	dropped    []string              // the checks that could not be generated
+}
+
+// server writes the interfaces, the handler and the helpers it uses
+func (s *serverGen) server() {
+	title := s.swag.Info.Title
+	if title == "" {
+		title = "the API"
+	}
+	s.serverType = s.ids.unique("Server")
+	s.notImpl = s.ids.unique("ErrNotImplemented")
+	unimplemented := s.ids.unique("Unimplemented" + s.serverType)
+	newHandler := s.ids.unique("NewHandler")
+	ops := s.operations()
+	for _, o := range ops {
+		s.requests[o] = s.ids.unique(o.id + "Request")
+		s.responses[o] = s.ids.unique(o.id + "Response")
+	}
+
+	// group the operations by their first tag if asked to
+	groups := make(map[string][]*operation)
+	tags := make([]string, 0)
+	for _, o := range ops {
+		tag := ""
+		if s.opts.ByTag && len(o.op.Tags) > 0 {
+			tag = o.op.Tags[0]
+		}
+		if _, ok := groups[tag]; !ok && tag != "" {
+			tags = append(tags, tag)
+		}
+		groups[tag] = append(groups[tag], o)
+	}
+	sort.Strings(tags)
+	embedded := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		id := s.ids.unique(exported(tag) + s.serverType)
+		embedded = append(embedded, id)
+		fmt.Fprintf(&s.decls, "// %s is implemented by a service that provides the operations tagged %s.\n", id, tag)
+		s.iface(id, nil, groups[tag])
+	}
+	fmt.Fprintf(&s.decls, "// %s is implemented by a service that provides the operations of %s.\n", s.serverType, title)
+	s.iface(s.serverType, embedded, groups[""])
+
+	fmt.Fprintf(&s.decls, "// %s is returned by the operations of %s, and answered with status 501.\n", s.notImpl, unimplemented)
+	fmt.Fprintf(&s.decls, "var %s = errors.New(\"not implemented\")\n\n", s.notImpl)
+	fmt.Fprintf(&s.decls, "// %s can be embedded in an implementation of %s to provide the operations it does not implement.\n", unimplemented, s.serverType)
+	fmt.Fprintf(&s.decls, "type %s struct{}\n\n", unimplemented)
+	for _, o := range ops {
+		fmt.Fprintf(&s.decls, "func (%s) %s {\n\treturn nil, %s\n}\n\n", unimplemented, s.signature(o), s.notImpl)
+	}
+
+	for _, o := range ops {
+		s.types(o)
+	}
+
+	// literal paths are matched before templated ones
+	routes := append([]*operation{}, ops...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		return strings.Count(routes[i].path, "{") < strings.Count(routes[j].path, "{")
+	})
+	basePath := strings.TrimRight(s.swag.BasePath, "/")
+	fmt.Fprintf(&s.decls, "// %s returns an http.Handler that serves the operations of srv under the base path %q. It routes\n", newHandler, basePath+"/")
+	s.decls.WriteString("// requests, decodes and validates their parameters, and writes the typed responses.\n")
+	fmt.Fprintf(&s.decls, "func %s(srv %s) http.Handler {\n\th := &handler{srv: srv}\n\th.routes = []route{\n", newHandler, s.serverType)
+	for _, o := range routes {
+		fmt.Fprintf(&s.decls, "\t\t{%q, regexp.MustCompile(%q), h.serve%s},\n", strings.ToUpper(o.method), routePattern(o.path), o.id)
+	}
+	s.decls.WriteString("\t}\n\treturn h\n}\n\n")
+
+	for _, o := range ops {
+		s.serve(o)
+	}
+	if s.shapeInit.Len() > 0 {
+		// the shapes of definitions are filled in once they are all declared, since they may refer to each other
+		fmt.Fprintf(&s.decls, "func init() {\n%s}\n\n", s.shapeInit.String())
+	}
+	runtime := strings.Replace(serverRuntime, "SERVER", s.serverType, -1)
+	runtime = strings.Replace(runtime, "NOTIMPLEMENTED", s.notImpl, -1)
+	runtime = strings.Replace(runtime, "BASEPATH", fmt.Sprintf("%q", basePath), -1)
+	s.decls.WriteString(runtime)
+}
+
+// iface writes an interface with a method for each operation
+func (s *serverGen) iface(id string, embedded []string, ops []*operation) {
+	fmt.Fprintf(&s.decls, "type %s interface {\n", id)
+	for _, e := range embedded {
+		fmt.Fprintf(&s.decls, "\t%s\n", e)
+	}
+	for _, o := range ops {
+		s.decls.WriteString(o.doc("\t"))
+		fmt.Fprintf(&s.decls, "\t%s\n", s.signature(o))
+	}
+	s.decls.WriteString("}\n\n")
+}
+
+// signature is the method signature of an operation
+func (s *serverGen) signature(o *operation) string {
+	return fmt.Sprintf("%s(ctx context.Context, req *%s) (*%s, error)", o.id, s.requests[o], s.responses[o])
+}
+
+// types writes the request and response types of an operation
+func (s *serverGen) types(o *operation) {
+	fmt.Fprintf(&s.decls, "// %s holds the parameters of %s.\n", s.requests[o], o.id)
+	fmt.Fprintf(&s.decls, "type %s struct {\n", s.requests[o])
+	s.decls.WriteString("\tHTTPRequest *http.Request // The request the parameters were decoded from\n")
+	params := append([]*param{}, o.params...)
+	if o.body != nil {
+		params = append(params, o.body)
+	}
+	for _, p := range params {
+		s.decls.WriteString(comment("\t", p.field, p.Description, ""))
+		if p.required {
+			fmt.Fprintf(&s.decls, "\t%s %s\n", p.field, p.typ)
+		} else {
+			fmt.Fprintf(&s.decls, "\t%s %s\n", p.field, fieldType(p.typ))
+		}
+	}
+	s.decls.WriteString("}\n\n")
+
+	fmt.Fprintf(&s.decls, "// %s holds the result of %s. The status code is taken from the first field that is set\n", s.responses[o], o.id)
+	s.decls.WriteString("// unless StatusCode is given.\n")
+	fmt.Fprintf(&s.decls, "type %s struct {\n\tStatusCode int\n\tHeader     http.Header\n", s.responses[o])
+	for _, r := range o.results {
+		if r.typ != "" {
+			s.decls.WriteString(comment("\t", r.field, r.Description, ""))
+			fmt.Fprintf(&s.decls, "\t%s %s\n", r.field, fieldType(r.typ))
+		}
+	}
+	s.decls.WriteString("}\n\n")
+}
+
+// routePattern turns a templated path into a regular expression that captures its parameters
+func routePattern(path string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, m := range pathParam.FindAllStringIndex(path, -1) {
+		b.WriteString(regexp.QuoteMeta(path[last:m[0]]))
+		b.WriteString("([^/]+)")
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(path[last:]))
+	b.WriteString("$")
+	return b.String()
+}
+
+// serve writes the handler method for an operation
+func (s *serverGen) serve(o *operation) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// serve%s decodes a request for %s and writes its response\n", o.id, o.id)
+	fmt.Fprintf(&b, "func (h *handler) serve%s(w http.ResponseWriter, r *http.Request, args []string) {\n", o.id)
+	fmt.Fprintf(&b, "\treq := &%s{HTTPRequest: r}\n", s.requests[o])
+	query, form := false, false
+	for _, p := range o.params {
+		query = query || p.In == "query"
+		form = form || p.In == "formData"
+	}
+	if query {
+		b.WriteString("\tquery := r.URL.Query()\n")
+	}
+	if form {
+		b.WriteString("\tif err := parseForm(r); err != nil {\n\t\tbadRequest(w, err)\n\t\treturn\n\t}\n")
+	}
+	arg := 0
+	for _, p := range o.params {
+		what := p.In + " parameter " + p.Name
+		if p.Type == "file" {
+			fmt.Fprintf(&b, "\tif f, _, err := r.FormFile(%q); err == nil {\n\t\tdefer f.Close()\n\t\treq.%s = f\n\t}", p.Name, p.field)
+			if p.required {
+				fmt.Fprintf(&b, " else {\n\t\tbadRequest(w, errors.New(%q))\n\t\treturn\n\t}", "missing required "+what)
+			}
+			b.WriteString("\n")
+			continue
+		}
+		var source string
+		switch p.In {
+		case "path":
+			source = fmt.Sprintf("[]string{args[%d]}", arg)
+			arg++
+		case "query":
+			source = fmt.Sprintf("query[%q]", p.Name)
+		case "header":
+			source = fmt.Sprintf("r.Header[%q]", http.CanonicalHeaderKey(p.Name))
+		default:
+			source = fmt.Sprintf("r.PostForm[%q]", p.Name)
+		}
+		if p.Type == "array" {
+			source = fmt.Sprintf("splitValues(%s, %q)", source, p.separator())
+		}
+		if p.Default != nil && p.Type != "array" {
+			source = fmt.Sprintf("withDefault(%s, %q)", source, text(p.Default))
+		}
+		args := []string{fmt.Sprintf("%q", what), source, fmt.Sprint(p.required), "&req." + p.field}
+		args = append(args, s.checks(o, p)...)
+		fmt.Fprintf(&b, "\tif err := decodeParam(%s); err != nil {\n\t\tbadRequest(w, err)\n\t\treturn\n\t}\n", strings.Join(args, ", "))
+	}
+	if o.body != nil {
+		shape := "nil"
+		if o.body.Schema != nil {
+			shape = s.shape(o.body.Schema, make(map[string]bool))
+		}
+		fmt.Fprintf(&b, "\tif err := decodeBody(r, &req.%s, %v, %s); err != nil {\n\t\tbadRequest(w, err)\n\t\treturn\n\t}\n", o.body.field, o.body.required, shape)
+	}
+
+	fmt.Fprintf(&b, "\tresp, err := h.srv.%s(r.Context(), req)\n", o.id)
+	b.WriteString("\tif err != nil {\n\t\tserverError(w, err)\n\t\treturn\n\t}\n")
+	fmt.Fprintf(&b, "\tif resp == nil {\n\t\tresp = &%s{}\n\t}\n", s.responses[o])
+	fmt.Fprintf(&b, "\tstatus := resp.StatusCode\n\tvar body interface{}\n\tmediaType := %q\n", mediaType(o.produces, "application/json"))
+	b.WriteString("\tswitch {\n")
+	fallback := http.StatusOK
+	for _, r := range o.results {
+		if r.status != 0 && fallback == http.StatusOK {
+			fallback = r.status
+		}
+		if r.typ == "" {
+			continue
+		}
+		status := r.status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		fmt.Fprintf(&b, "\tcase resp.%s != nil:\n\t\tbody = resp.%s\n", r.field, r.field)
+		if r.Schema.Type == "file" {
+			fmt.Fprintf(&b, "\t\tmediaType = %q\n", fileMediaType(o.produces))
+		}
+		fmt.Fprintf(&b, "\t\tif status == 0 {\n\t\t\tstatus = %d\n\t\t}\n", status)
+	}
+	b.WriteString("\t}\n")
+	fmt.Fprintf(&b, "\tif status == 0 {\n\t\tstatus = %d\n\t}\n", fallback)
+	b.WriteString("\twriteResponse(w, resp.Header, status, mediaType, body)\n}\n\n")
+	s.decls.WriteString(b.String())
+}
+
+// checks returns the expressions that validate the values of a parameter
+func (s *serverGen) checks(o *operation, p *param) []string {
+	checks := make([]string, 0)
+	values := &p.ItemsDef
+	if p.Type == "array" {
+		if p.MinItems != nil || p.MaxItems != nil {
+			checks = append(checks, fmt.Sprintf("itemsCheck(%s, %s)", intOr(p.MinItems, "0"), intOr(p.MaxItems, "-1")))
+		}
+		if p.Items == nil {
+			return checks
+		}
+		values = p.Items
+	}
+	if values.Enum != nil {
+		list := make([]string, 0, len(values.Enum))
+		for _, v := range values.Enum {
+			list = append(list, fmt.Sprintf("%q", text(v)))
+		}
+		checks = append(checks, "enumCheck("+strings.Join(list, ", ")+")")
+	}
+	if values.Minimum != nil || values.Maximum != nil {
+		min, max := "math.Inf(-1)", "math.Inf(1)"
+		if values.Minimum != nil {
+			min = fmt.Sprint(*values.Minimum)
+		}
+		if values.Maximum != nil {
+			max = fmt.Sprint(*values.Maximum)
+		}
+		if values.Minimum == nil || values.Maximum == nil {
+			s.imports["math"] = true
+		}
+		exclMin := values.ExclusiveMinimum != nil && *values.ExclusiveMinimum
+		exclMax := values.ExclusiveMaximum != nil && *values.ExclusiveMaximum
+		checks = append(checks, fmt.Sprintf("rangeCheck(%s, %s, %v, %v)", min, max, exclMin, exclMax))
+	}
+	if values.MinLength != nil || values.MaxLength != nil {
+		checks = append(checks, fmt.Sprintf("lengthCheck(%s, %s)", intOr(values.MinLength, "0"), intOr(values.MaxLength, "-1")))
+	}
+	if values.Pattern != nil {
+		if _, err := regexp.Compile(*values.Pattern); err != nil {
+			s.dropped = append(s.dropped, fmt.Sprintf("%s %s: %s parameter %s: pattern is not checked: %s", strings.ToUpper(o.method), o.path, p.In, p.Name, err))
+			return checks
+		}
+		id := s.ids.unique(unexported(o.id + p.field + "Pattern"))
+		fmt.Fprintf(&s.decls, "var %s = regexp.MustCompile(%q)\n\n", id, *values.Pattern)
+		checks = append(checks, "patternCheck("+id+")")
+	}
+	return checks
+}
+
+// shape returns the expression of the shape that checks the required properties of a body, or nil if the schema
+// requires none. seen holds the definitions being looked into, which are not looked into again.
+func (s *serverGen) shape(schema *swagger2.Schema, seen map[string]bool) string {
+	if schema.Ref != "" {
+		return s.refShape(&schema.ItemsDef, seen)
+	}
+	fields := make([]string, 0)
+	required := make([]string, 0)
+	for _, name := range schema.Required {
+		if p, ok := schema.Properties[name]; !ok || p.ReadOnly == nil || !*p.ReadOnly {
+			required = append(required, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(required) > 0 {
+		fields = append(fields, "required: []string{"+strings.Join(required, ", ")+"}")
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	props := make([]string, 0)
+	for _, name := range names {
+		p := schema.Properties[name]
+		if sub := s.shape(&p, seen); sub != "nil" {
+			props = append(props, fmt.Sprintf("%q: %s", name, sub))
+		}
+	}
+	if len(props) > 0 {
+		fields = append(fields, "properties: map[string]*shape{"+strings.Join(props, ", ")+"}")
+	}
+	if items := s.refShape(schema.Items, seen); items != "nil" {
+		fields = append(fields, "items: "+items)
+	}
+	if values := s.refShape(schema.AdditionalProperties, seen); values != "nil" {
+		fields = append(fields, "values: "+values)
+	}
+	all := make([]string, 0)
+	for i := range schema.AllOf {
+		if part := s.shape(&schema.AllOf[i], seen); part != "nil" {
+			all = append(all, part)
+		}
+	}
+	if len(all) > 0 {
+		fields = append(fields, "all: []*shape{"+strings.Join(all, ", ")+"}")
+	}
+	if len(fields) == 0 {
+		return "nil"
+	}
+	return "&shape{" + strings.Join(fields, ", ") + "}"
+}
+
+// refShape returns the shape of the definition that items refer to, declaring a variable for it the first time so
+// that definitions that refer to each other can share their shapes
+func (s *serverGen) refShape(items *swagger2.ItemsDef, seen map[string]bool) string {
+	if items == nil {
+		return "nil"
+	}
+	name, ok := s.swag.Definitions.RefName(items.Ref)
+	def := s.definition(name)
+	if !ok || def == nil {
+		return "nil"
+	}
+	if id, ok := s.shapes[name]; ok {
+		return id
+	}
+	if seen[name] {
+		// the definition is being looked into: refer to it if it turns out to need a shape
+		id := s.ids.unique(unexported(s.names[name] + "Shape"))
+		s.shapes[name] = id
+		return id
+	}
+	seen[name] = true
+	expr := s.shape(def, seen)
+	delete(seen, name)
+	id, recursive := s.shapes[name]
+	if expr == "nil" && !recursive {
+		s.shapes[name] = "nil"
+		return "nil"
+	}
+	if !recursive {
+		id = s.ids.unique(unexported(s.names[name] + "Shape"))
+		s.shapes[name] = id
+	}
+	fmt.Fprintf(&s.decls, "// %s checks the required properties of %s bodies\nvar %s = &shape{}\n\n", id, s.names[name], id)
+	switch {
+	case strings.HasPrefix(expr, "&"):
+		fmt.Fprintf(&s.shapeInit, "\t*%s = %s\n", id, expr[1:])
+	case expr != "nil":
+		fmt.Fprintf(&s.shapeInit, "\t*%s = *%s\n", id, expr)
+	}
+	return id
+}
+
+// text returns a value as the text of a parameter, writing numbers without exponents
+func text(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// intOr writes an optional int, or the fallback if it is not set
+func intOr(v *int, fallback string) string {
+	if v == nil {
+		return fallback
+	}
+	return fmt.Sprint(*v)
+}
+
+// fileMediaType picks the media type for a file response
+func fileMediaType(produces []string) string {
+	for _, t := range produces {
+		if !strings.Contains(t, "json") && !strings.Contains(t, "xml") {
+			return t
+		}
+	}
+	return "application/octet-stream"
+}
+
+// serverRuntime holds the helpers used by every generated server. SERVER, NOTIMPLEMENTED and BASEPATH are replaced
+// with the name of the server interface, the name of its error and the quoted base path.
+const serverRuntime = `// route matches the path of an operation
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	serve   func(w http.ResponseWriter, r *http.Request, args []string)
+}
+
+// handler routes requests to the operations of a SERVER
+type handler struct {
+	srv    SERVER
+	routes []route
+}
+
+// ServeHTTP finds the operation for a request and serves it
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, BASEPATH) {
+		http.NotFound(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, BASEPATH)
+	allowed := make([]string, 0)
+	for _, rt := range h.routes {
+		m := rt.pattern.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if rt.method != r.Method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		rt.serve(w, r, m[1:])
+		return
+	}
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// badRequest answers a request whose parameters are not valid
+func badRequest(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// serverError answers a request whose operation failed
+func serverError(w http.ResponseWriter, err error) {
+	if errors.Is(err, NOTIMPLEMENTED) {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// parseForm parses a URL encoded or multipart form
+func parseForm(r *http.Request) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		return r.ParseMultipartForm(32 << 20)
+	}
+	return r.ParseForm()
+}
+
+// splitValues splits the values of an array parameter. An empty separator means each value was sent separately.
+func splitValues(values []string, sep string) []string {
+	if sep == "" {
+		return values
+	}
+	if len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	return strings.Split(values[0], sep)
+}
+
+// withDefault supplies the default value of a parameter that was not sent
+func withDefault(values []string, def string) []string {
+	if len(values) == 0 {
+		return []string{def}
+	}
+	return values
+}
+
+// check validates the text values of a parameter
+type check func(texts []string) error
+
+// enumCheck allows only the given values
+func enumCheck(values ...string) check {
+	return func(texts []string) error {
+		for _, t := range texts {
+			ok := false
+			for _, v := range values {
+				ok = ok || t == v
+			}
+			if !ok {
+				return fmt.Errorf("%q is not one of %s", t, strings.Join(values, ", "))
+			}
+		}
+		return nil
+	}
+}
+
+// rangeCheck allows only numbers between min and max
+func rangeCheck(min, max float64, exclusiveMin, exclusiveMax bool) check {
+	return func(texts []string) error {
+		for _, t := range texts {
+			f, err := strconv.ParseFloat(t, 64)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", t)
+			}
+			if f < min || f > max || (exclusiveMin && f == min) || (exclusiveMax && f == max) {
+				return fmt.Errorf("%s is out of range", t)
+			}
+		}
+		return nil
+	}
+}
+
+// lengthCheck allows only text of between min and max characters; a negative max has no limit
+func lengthCheck(min, max int) check {
+	return func(texts []string) error {
+		for _, t := range texts {
+			if n := len([]rune(t)); n < min || (max >= 0 && n > max) {
+				return fmt.Errorf("%q has the wrong length", t)
+			}
+		}
+		return nil
+	}
+}
+
+// patternCheck allows only text matching a regular expression
+func patternCheck(re *regexp.Regexp) check {
+	return func(texts []string) error {
+		for _, t := range texts {
+			if !re.MatchString(t) {
+				return fmt.Errorf("%q does not match %s", t, re)
+			}
+		}
+		return nil
+	}
+}
+
+// itemsCheck allows only arrays of between min and max items; a negative max has no limit
+func itemsCheck(min, max int) check {
+	return func(texts []string) error {
+		if len(texts) < min || (max >= 0 && len(texts) > max) {
+			return fmt.Errorf("%d items is the wrong number", len(texts))
+		}
+		return nil
+	}
+}
+
+// decodeParam validates the text values of a parameter and parses them into target
+func decodeParam(what string, texts []string, required bool, target interface{}, checks ...check) error {
+	if len(texts) == 0 {
+		if required {
+			return fmt.Errorf("missing required %s", what)
+		}
+		return nil
+	}
+	for _, c := range checks {
+		if err := c(texts); err != nil {
+			return fmt.Errorf("invalid %s: %s", what, err)
+		}
+	}
+	if err := parseTexts(texts, reflect.ValueOf(target).Elem()); err != nil {
+		return fmt.Errorf("invalid %s: %s", what, err)
+	}
+	return nil
+}
+
+// parseTexts parses text values into a value, which may be a pointer or a slice
+func parseTexts(texts []string, v reflect.Value) error {
+	switch {
+	case v.Kind() == reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		return parseTexts(texts, v.Elem())
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		s := reflect.MakeSlice(v.Type(), len(texts), len(texts))
+		for i, t := range texts {
+			if err := parseText(t, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return parseText(texts[0], v)
+}
+
+// parseText parses a single text value
+func parseText(t string, v reflect.Value) error {
+	switch v.Interface().(type) {
+	case time.Time:
+		tm, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return nil
+	case []byte:
+		b, err := base64.StdEncoding.DecodeString(t)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(t)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(t, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(t, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(t)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(t))
+	default:
+		return fmt.Errorf("cannot parse %q", t)
+	}
+	return nil
+}
+
+// shape describes the properties that the objects of a JSON body must have
+type shape struct {
+	required   []string
+	properties map[string]*shape
+	items      *shape   // the shape of the items of an array
+	values     *shape   // the shape of the values of a map
+	all        []*shape // the shapes that the value must also have
+}
+
+// check returns an error for the first required property that a value decoded from JSON, found at where, misses
+func (s *shape) check(where string, v interface{}) error {
+	if s == nil {
+		return nil
+	}
+	for _, part := range s.all {
+		if err := part.check(where, v); err != nil {
+			return err
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("invalid body: missing required property %s", strings.TrimPrefix(where+"."+name, "."))
+			}
+		}
+		for name, value := range v {
+			p, ok := s.properties[name]
+			if !ok {
+				p = s.values
+			}
+			if err := p.check(where+"."+name, value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := s.items.check(fmt.Sprintf("%s[%d]", where, i), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeBody decodes a request body according to its content type, checking the required properties of JSON
+// bodies against a shape
+func decodeBody(r *http.Request, v interface{}, required bool, s *shape) error {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		if required {
+			return errors.New("missing required body")
+		}
+		return nil
+	}
+	contentType := r.Header.Get("Content-Type")
+	switch t := v.(type) {
+	case *[]byte:
+		if !strings.Contains(contentType, "json") && !strings.Contains(contentType, "xml") {
+			*t = b
+			return nil
+		}
+	}
+	if strings.Contains(contentType, "xml") {
+		err = xml.Unmarshal(b, v)
+	} else if err = json.Unmarshal(b, v); err == nil && s != nil {
+		var decoded interface{}
+		if err = json.Unmarshal(b, &decoded); err == nil {
+			return s.check("", decoded)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid body: %s", err)
+	}
+	return nil
+}
+
+// writeResponse encodes a response body in the given media type
+func writeResponse(w http.ResponseWriter, header http.Header, status int, mediaType string, body interface{}) {
+	for k, v := range header {
+		w.Header()[k] = v
+	}
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	var b []byte
+	var err error
+	switch {
+	case strings.Contains(mediaType, "json"):
+		b, err = json.Marshal(body)
+	case strings.Contains(mediaType, "xml"):
+		b, err = xml.Marshal(body)
+	default:
+		switch t := body.(type) {
+		case io.Reader:
+			b, err = ioutil.ReadAll(t)
+		case []byte:
+			b = t
+		default:
+			v := reflect.ValueOf(body)
+			for v.Kind() == reflect.Ptr {
+				v = v.Elem()
+			}
+			b = []byte(fmt.Sprint(v.Interface()))
+		}
+	}
+	if err != nil {
+		serverError(w, err)
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.WriteHeader(status)
+	w.Write(b)
+}
+`
@@ -0,0 +1,203 @@
+package gogen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestServerBuild(t *testing.T) {
+	goTool(t)
+	for file, swag := range loadExamples(t) {
+		types, err := Types(swag, Options{})
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		for _, byTag := range []bool{false, true} {
+			server, err := Server(swag, Options{ByTag: byTag})
+			if err != nil {
+				t.Errorf("%s: %s\n%s", file, err, server)
+				continue
+			}
+			dir := writeModule(t, map[string][]byte{"api/types.go": types, "api/server.go": server})
+			goCommand(t, dir, "vet", "./...")
+		}
+	}
+}
+
+// storeServerTest serves an implementation of the generated server and calls it with the generated client
+const storeServerTest = `package api
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/generated/client"
+)
+
+type store struct {
+	UnimplementedServer
+}
+
+func (store) GetPhoto(ctx context.Context, req *GetPhotoRequest) (*GetPhotoResponse, error) {
+	if req.PetID == 8 {
+		msg := "no photo"
+		return &GetPhotoResponse{NotFound: &GetPhotoNotFound{Message: &msg}}, nil
+	}
+	if len(req.Tag) == 2 && (req.Tag[1] != "b" || len(req.Sizes) != 2 || req.Sizes[1] != GetPhotoSizesItemM || *req.XTrace != "abc") {
+		return nil, errors.New("unexpected parameters")
+	}
+	url := "http://example.com/7.png"
+	return &GetPhotoResponse{OK: &Photo{URL: &url}}, nil
+}
+
+func (store) PostPets(ctx context.Context, req *PostPetsRequest) (*PostPetsResponse, error) {
+	pet := req.Pet
+	return &PostPetsResponse{Created: &pet}, nil
+}
+
+func TestGeneratedServer(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(store{}))
+	defer srv.Close()
+	ctx := context.Background()
+	c := client.NewClient(srv.URL + "/v1")
+
+	trace := "abc"
+	sizes := []client.GetPhotoSizesItem{client.GetPhotoSizesItemS, client.GetPhotoSizesItemM}
+	res, err := c.GetPhoto(ctx, 7, 2, []string{"a", "b"}, &client.GetPhotoParams{Sizes: sizes, XTrace: &trace})
+	if err != nil || res.OK == nil || *res.OK.URL != "http://example.com/7.png" {
+		t.Fatalf("unexpected result %+v %v", res, err)
+	}
+	res, err = c.GetPhoto(ctx, 8, 0, []string{"a"}, nil)
+	if err != nil || res.StatusCode != 404 || *res.NotFound.Message != "no photo" {
+		t.Fatalf("expected a not found result, got %+v %v", res, err)
+	}
+
+	status := client.PetStatusSold
+	created, err := c.PostPets(ctx, client.Pet{Name: "Rex", Status: &status})
+	if err != nil || created.StatusCode != 201 || created.Created.Name != "Rex" || *created.Created.Status != client.PetStatusSold {
+		t.Fatalf("unexpected result %+v %v", created, err)
+	}
+
+	_, err = c.Upload(ctx, 3, strings.NewReader("PNG"), nil)
+	if e, ok := err.(*client.ResponseError); !ok || e.StatusCode != 501 {
+		t.Fatalf("expected an unimplemented operation to answer 501, got %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/v1/pets/7/photos/2?tag=a":           200,
+		"/v1/pets/7/photos/2":                 400, // missing tag
+		"/v1/pets/7/photos/11?tag=a":          400, // index above maximum
+		"/v1/pets/7/photos/2?tag=a&sizes=s|x": 400, // size not in the enum
+		"/v1/pets/x/photos/2?tag=a":           400, // petId is not an integer
+		"/v1/pets/7/photos":                   404,
+		"/pets/7/photos/2?tag=a":              404, // outside the base path
+		"/v1/pets/7/upload":                   405,
+	} {
+		req, _ := http.NewRequest("GET", srv.URL+path, nil)
+		req.Header.Set("X-Trace", "abc")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected status %d, got %d: %s", path, want, resp.StatusCode, body)
+		}
+	}
+
+	for body, want := range map[string]string{
+		` + "`" + `{"name": "Rex", "parent": {"name": "Max"}}` + "`" + `: "",
+		` + "`" + `{"status": "sold"}` + "`" + `:                         "invalid body: missing required property name",
+		` + "`" + `{"name": "Rex", "parent": {}}` + "`" + `:              "invalid body: missing required property parent.name",
+		` + "`" + `{"name": 3}` + "`" + `:                               "invalid body: json: cannot unmarshal number",
+	} {
+		resp, err := http.Post(srv.URL+"/v1/pets", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		text, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if got := strings.TrimSpace(string(text)); want == "" && resp.StatusCode != 201 || want != "" && (resp.StatusCode != 400 || !strings.HasPrefix(got, want)) {
+			t.Errorf("%s: unexpected response %d %s", body, resp.StatusCode, got)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/v1/pets/7/photos/2?tag=a", nil)
+	req.Header.Set("X-Trace", "ABC")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != 400 {
+		t.Errorf("expected a header that does not match its pattern to be rejected, got %v %v", resp, err)
+	}
+}
+`
+
+func TestServer(t *testing.T) {
+	goTool(t)
+	swag, err := swagger2.LoadYaml([]byte(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// constrain the parameters of getPhoto so that the handler has something to validate
+	params := swag.Paths["/pets/{petId}/photos/{index}"].Get.Parameters
+	max, pattern := 10.0, "^[a-z]+$"
+	params[1].Maximum = &max
+	params[2].Items.Enum = []interface{}{"s", "m", "l"}
+	params[4].Pattern = &pattern
+	// and let pets have a parent, whose name is required as well
+	swag.Definitions["Pet"].Properties["parent"] = swagger2.Schema{ItemsDef: swagger2.ItemsDef{Ref: "#/definitions/Pet"}}
+	files := make(map[string][]byte)
+	for name, gen := range map[string]func(*swagger2.Swagger, Options) ([]byte, error){
+		"api/types.go":     Types,
+		"api/server.go":    Server,
+		"client/types.go":  Types,
+		"client/client.go": Client,
+	} {
+		src, err := gen(swag, Options{Package: strings.Split(name, "/")[0]})
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", name, err, src)
+		}
+		files[name] = src
+	}
+	files["api/server_test.go"] = []byte(storeServerTest)
+	dir := writeModule(t, files)
+	goCommand(t, dir, "test", "./...")
+}
+
+func TestServerValues(t *testing.T) {
+	swag, err := swagger2.LoadJson([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "List", "version": "1.0"},
+		"paths": {"/items": {"get": {
+			"operationId": "list",
+			"parameters": [
+				{"name": "limit", "in": "query", "type": "integer", "default": 1000000},
+				{"name": "size", "in": "query", "type": "integer", "enum": [1000000, 2000000]},
+				{"name": "q", "in": "query", "type": "string", "pattern": "^(?!x)"}
+			],
+			"responses": {"204": {"description": "Listed."}}
+		}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Server(swag, Options{})
+	if err == nil || !strings.Contains(err.Error(), "GET /items: query parameter q: pattern is not checked") {
+		t.Errorf("expected the pattern to be reported, got %v", err)
+	}
+	out := string(src)
+	for _, want := range []string{`withDefault(query["limit"], "1000000")`, `enumCheck("1000000", "2000000")`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "(?!x)") {
+		t.Errorf("expected no check of the pattern in:\n%s", out)
+	}
+}
@@ -0,0 +1,356 @@
+// Package gogen generates Go source code from Swagger 2 documents.
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Options controls code generation.
+type Options struct {
+	Package string // The name of the generated package; defaults to "api"
+	ByTag   bool   // Server declares an interface for each operation tag, which the Server interface embeds
+}
+
+// Types generates a Go source file declaring a type for each of the document's definitions. The output is gofmt'd.
+func Types(swag *swagger2.Swagger, opts Options) ([]byte, error) {
+	g := newGenerator(swag, opts)
+	g.types()
+	return g.source()
+}
+
+// generator holds the state of code generation for a document
+type generator struct {
+	swag    *swagger2.Swagger
+	opts    Options
+	ids     namer             // identifiers in use at package level
+	names   map[string]string // definition name to Go type name
+	imports map[string]bool
+	decls   bytes.Buffer // declarations written so far
+	pending []pendingType
+}
+
+// pendingType is an inline object or enum that needs a named type
+type pendingType struct {
+	name   string
+	schema *swagger2.Schema
+}
+
+// newGenerator names every definition up front so references can be written in any order
+func newGenerator(swag *swagger2.Swagger, opts Options) *generator {
+	if opts.Package == "" {
+		opts.Package = "api"
+	}
+	g := &generator{
+		swag:    swag,
+		opts:    opts,
+		ids:     make(namer),
+		names:   make(map[string]string),
+		imports: make(map[string]bool),
+	}
+	for _, name := range g.definitionNames() {
+		g.names[name] = g.ids.unique(exported(name))
+	}
+	return g
+}
+
+// definitionNames returns the definition names in sorted order
+func (g *generator) definitionNames() []string {
+	names := make([]string, 0, len(g.swag.Definitions))
+	for n := range g.swag.Definitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// source assembles the package clause, imports and declarations and formats the result
+func (g *generator) source() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by swaggen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.opts.Package)
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for p := range g.imports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		b.WriteString("import (\n")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "\t%q\n", p)
+		}
+		b.WriteString(")\n\n")
+	}
+	b.Write(g.decls.Bytes())
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return b.Bytes(), fmt.Errorf("generated code does not parse: %s", err)
+	}
+	return out, nil
+}
+
+// types declares a type for every definition, followed by the inline types they need
+func (g *generator) types() {
+	for _, name := range g.definitionNames() {
+		def := g.swag.Definitions[name]
+		g.declare(g.names[name], name, &def)
+	}
+	g.flush()
+}
+
+// flush declares the inline types collected so far, which may in turn collect more
+func (g *generator) flush() {
+	for len(g.pending) > 0 {
+		p := g.pending[0]
+		g.pending = g.pending[1:]
+		g.declare(p.name, "", p.schema)
+	}
+}
+
+// declare writes a named type for a schema
+func (g *generator) declare(id, defName string, s *swagger2.Schema) {
+	fallback := ""
+	if defName != "" {
+		fallback = "is generated from the " + defName + " definition."
+	}
+	doc := comment("", id, s.Description, fallback)
+	g.decls.WriteString(doc)
+	switch {
+	case s.Enum != nil && s.Type != "" && s.Type != "array" && s.Type != "object":
+		t := g.primitive(&s.ItemsDef)
+		if !constant(t) {
+			// types such as time.Time have no constants, so the values are only listed
+			if doc != "" {
+				g.decls.WriteString("//\n")
+			}
+			g.decls.WriteString(enumComment(s.Enum))
+		}
+		fmt.Fprintf(&g.decls, "type %s %s\n\n", id, t)
+		if constant(t) {
+			g.enum(id, s.Enum)
+		}
+	case g.isStruct(s):
+		fmt.Fprintf(&g.decls, "type %s struct {\n", id)
+		g.fields(id, s, make(namer))
+		g.decls.WriteString("}\n\n")
+	default:
+		fmt.Fprintf(&g.decls, "type %s %s\n\n", id, g.schemaType(id, s))
+	}
+}
+
+// enum declares a typed constant for each enum value
+func (g *generator) enum(id string, values []interface{}) {
+	g.decls.WriteString("const (\n")
+	for _, v := range values {
+		var suffix, literal string
+		switch t := v.(type) {
+		case string:
+			suffix, literal = exported(t), strconv.Quote(t)
+		case bool:
+			suffix, literal = exported(strconv.FormatBool(t)), strconv.FormatBool(t)
+		case float64:
+			literal = strconv.FormatFloat(t, 'f', -1, 64)
+			suffix = exported(strings.Replace(strings.Replace(literal, "-", "Minus", 1), ".", "_", 1))
+		case int:
+			literal = strconv.Itoa(t)
+			suffix = exported(strings.Replace(literal, "-", "Minus", 1))
+		default:
+			continue
+		}
+		if suffix == "X" {
+			suffix = "Empty"
+		}
+		fmt.Fprintf(&g.decls, "\t%s %s = %s\n", g.ids.unique(id+suffix), id, literal)
+	}
+	g.decls.WriteString(")\n\n")
+}
+
+// constant returns true if values of a Go type can be declared as constants
+func constant(t string) bool {
+	switch t {
+	case "string", "bool", "int", "int32", "int64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// enumComment lists the values of an enum whose type cannot have constants
+func enumComment(values []interface{}) string {
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		list = append(list, fmt.Sprint(v))
+	}
+	return "// Allowed values: " + strings.Join(list, ", ") + "\n"
+}
+
+// isStruct returns true if the schema is an object with properties or is composed with allOf
+func (g *generator) isStruct(s *swagger2.Schema) bool {
+	return s.Ref == "" && (len(s.Properties) > 0 || len(s.AllOf) > 0)
+}
+
+// fields writes the struct fields for a schema, embedding the definitions its allOf refers to and merging in
+// the properties of inline parts. ids holds the field names already used in the struct.
+func (g *generator) fields(owner string, s *swagger2.Schema, ids namer) {
+	for i := range s.AllOf {
+		part := &s.AllOf[i]
+		if name, ok := g.swag.Definitions.RefName(part.Ref); ok {
+			ids[g.names[name]] = true
+			fmt.Fprintf(&g.decls, "\t%s\n", g.names[name])
+		} else if part.Ref == "" {
+			g.fields(owner, part, ids)
+		}
+	}
+	props := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		props = append(props, n)
+	}
+	sort.Strings(props)
+	for _, n := range props {
+		p := s.Properties[n]
+		id := ids.unique(exported(n))
+		t := g.schemaType(owner+id, &p)
+		tag := n
+		required := lists.Contains(s.Required, n)
+		if !required {
+			tag += ",omitempty"
+		}
+		if nillable(t) && (!required || p.Extensions.Bool("x-nullable") || g.recursive(owner, &p)) {
+			t = "*" + t
+		}
+		g.decls.WriteString(comment("\t", id, p.Description, ""))
+		fmt.Fprintf(&g.decls, "\t%s %s `json:\"%s\"`\n", id, t, tag)
+	}
+}
+
+// nillable returns true if a field of the type needs a pointer to tell a missing value from the zero value
+func nillable(t string) bool {
+	return !strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[") && t != "interface{}" && t != "io.Reader"
+}
+
+// recursive returns true if a required property refers back to the struct that holds it, which needs a pointer
+func (g *generator) recursive(owner string, p *swagger2.Schema) bool {
+	name, ok := g.swag.Definitions.RefName(p.Ref)
+	if !ok {
+		return false
+	}
+	seen := make(map[string]bool)
+	var reaches func(name string) bool
+	reaches = func(name string) bool {
+		if g.names[name] == owner {
+			return true
+		}
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		def := g.definition(name)
+		if def == nil {
+			return false
+		}
+		for _, part := range def.AllOf {
+			if n, ok := g.swag.Definitions.RefName(part.Ref); ok && reaches(n) {
+				return true
+			}
+		}
+		for pn, prop := range def.Properties {
+			if n, ok := g.swag.Definitions.RefName(prop.Ref); ok && lists.Contains(def.Required, pn) && reaches(n) {
+				return true
+			}
+		}
+		return false
+	}
+	return reaches(name)
+}
+
+// definition looks up a definition by name
+func (g *generator) definition(name string) *swagger2.Schema {
+	def, ok := g.swag.Definitions[name]
+	if !ok {
+		return nil
+	}
+	return &def
+}
+
+// schemaType returns the Go type for a schema, collecting inline objects and enums as named types called hint
+func (g *generator) schemaType(hint string, s *swagger2.Schema) string {
+	switch {
+	case s.Ref != "":
+		return g.itemsType(hint, &s.ItemsDef)
+	case len(s.Properties) > 0 || len(s.AllOf) > 0:
+		id := g.ids.unique(hint)
+		g.pending = append(g.pending, pendingType{id, s})
+		return id
+	}
+	return g.itemsType(hint, &s.ItemsDef)
+}
+
+// itemsType returns the Go type for the simple types used by array items and map values
+func (g *generator) itemsType(hint string, d *swagger2.ItemsDef) string {
+	if d.Ref != "" {
+		if name, ok := g.swag.Definitions.RefName(d.Ref); ok {
+			return g.names[name]
+		}
+		return "interface{}"
+	}
+	switch d.Type {
+	case "array":
+		if d.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.itemsType(hint+"Item", d.Items)
+	case "object", "":
+		if d.AdditionalProperties != nil {
+			return "map[string]" + g.itemsType(hint+"Value", d.AdditionalProperties)
+		}
+		if d.Type == "object" {
+			return "map[string]interface{}"
+		}
+		return "interface{}"
+	}
+	if d.Enum != nil {
+		id := g.ids.unique(hint)
+		g.pending = append(g.pending, pendingType{id, &swagger2.Schema{ItemsDef: *d}})
+		return id
+	}
+	return g.primitive(d)
+}
+
+// primitive returns the Go type for a string, number, integer, boolean or file
+func (g *generator) primitive(d *swagger2.ItemsDef) string {
+	switch d.Type {
+	case "string":
+		switch d.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		switch d.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case "number":
+		if d.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "file":
+		return "[]byte"
+	}
+	return "interface{}"
+}
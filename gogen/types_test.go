@@ -0,0 +1,156 @@
+package gogen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestTypesBuild(t *testing.T) {
+	goTool(t)
+	for file, swag := range loadExamples(t) {
+		src, err := Types(swag, Options{})
+		if err != nil {
+			t.Errorf("%s: %s\n%s", file, err, src)
+			continue
+		}
+		dir := writeModule(t, map[string][]byte{"api/types.go": src})
+		goCommand(t, dir, "build", "./...")
+	}
+}
+
+const models = `
+swagger: "2.0"
+info: {title: Models, version: "1.0"}
+paths: {}
+definitions:
+  pet_status:
+    type: string
+    description: The state of a pet in the store.
+    enum: [available, sold]
+  Pet:
+    type: object
+    required: [name, parent]
+    properties:
+      name: {type: string, description: The pet's name.}
+      born: {type: string, format: date-time}
+      status: {$ref: "#/definitions/pet_status"}
+      parent: {$ref: "#/definitions/Pet"}
+      tags: {type: array, items: {type: string}}
+      scores: {type: object, additionalProperties: {type: integer, format: int32}}
+      size: {type: string, enum: [small, large]}
+      owner:
+        type: object
+        properties:
+          name: {type: string}
+  Cat:
+    allOf:
+      - $ref: "#/definitions/Pet"
+      - properties:
+          lives: {type: integer, x-nullable: true}
+        required: [lives]
+`
+
+func TestTypes(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(models))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Types(swag, Options{Package: "models"})
+	if err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+	code := strings.Join(strings.Fields(string(src)), " ")
+	for _, want := range []string{
+		"package models",
+		"\"time\"",
+		"// PetStatus The state of a pet in the store.\ntype PetStatus string",
+		"PetStatusAvailable PetStatus = \"available\"",
+		"// Name The pet's name.\n\tName string `json:\"name\"`",
+		"Born *time.Time `json:\"born,omitempty\"`",
+		"Parent *Pet `json:\"parent\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"Scores map[string]int32 `json:\"scores,omitempty\"`",
+		"Size *PetSize `json:\"size,omitempty\"`",
+		"PetSizeSmall PetSize = \"small\"",
+		"Owner *PetOwner `json:\"owner,omitempty\"`",
+		"type PetOwner struct",
+		"type Cat struct {\n\tPet\n\tLives *int `json:\"lives\"`",
+	} {
+		if !strings.Contains(code, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("expected generated code to contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTypesNonASCII(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(`
+swagger: "2.0"
+info: {title: Bakery, version: "1.0"}
+paths: {}
+definitions:
+  éclair:
+    type: object
+    properties:
+      crème: {type: string}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Types(swag, Options{})
+	if err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+	code := strings.Join(strings.Fields(string(src)), " ")
+	for _, want := range []string{"type Éclair struct", "Crème *string `json:\"crème,omitempty\"`"} {
+		if !strings.Contains(code, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("expected generated code to contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTypesEnumsWithoutConstants(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(`
+swagger: "2.0"
+info: {title: Events, version: "1.0"}
+paths: {}
+definitions:
+  Stamp:
+    type: string
+    format: date-time
+    enum: ["2020-01-01T00:00:00Z", "2021-01-01T00:00:00Z"]
+  Key:
+    type: string
+    format: byte
+    description: A signing key.
+    enum: [AAAA, BBBB]
+  Event:
+    type: object
+    properties:
+      level: {type: integer, enum: [1, 2]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Types(swag, Options{})
+	if err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+	code := strings.Join(strings.Fields(string(src)), " ")
+	for _, want := range []string{
+		"// Stamp is generated from the Stamp definition.\n//\n// Allowed values: 2020-01-01T00:00:00Z, 2021-01-01T00:00:00Z\ntype Stamp time.Time",
+		"// Key A signing key.\n//\n// Allowed values: AAAA, BBBB\ntype Key []byte",
+		"EventLevelX1 EventLevel = 1",
+	} {
+		if !strings.Contains(code, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("expected generated code to contain %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(code, "Stamp = ") || strings.Contains(code, "Key = ") {
+		t.Errorf("unexpected constants:\n%s", src)
+	}
+	goTool(t)
+	dir := writeModule(t, map[string][]byte{"api/types.go": src})
+	goCommand(t, dir, "build", "./...")
+}
@@ -0,0 +1,13 @@
+// Package lists has helpers for the lists of names that documents are full of, such as required properties and
+// media types.
+package lists
+
+// Contains returns true if the list holds the string.
+func Contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
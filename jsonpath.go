@@ -0,0 +1,604 @@
+package swagger2
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// jsonPath is a compiled JSONPath expression, such as "$.paths['/pets'].*[?(@.deprecated == true)]". It supports
+// names, wildcards, indexes, slices, unions, recursive descent and filters that compare the values of paths
+// relative to the current node (@) or to the root ($) with literals, combined with &&, || and !.
+type jsonPath struct {
+	segments []pathSegment
+}
+
+// pathSegment selects children of the nodes matched so far, or descendants for ".."
+type pathSegment struct {
+	descendants bool
+	selectors   []pathSelector
+}
+
+// pathSelector selects children by name, index, slice, filter or all of them
+type pathSelector struct {
+	kind             selectorKind
+	name             string
+	index            int
+	start, end, step *int
+	filter           pathFilter
+}
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectIndex
+	selectSlice
+	selectAll
+	selectFilter
+)
+
+// pathNode is a node matched by a path, with the reference tokens that lead to it from the root
+type pathNode struct {
+	tokens []string
+	value  interface{}
+}
+
+// pointer returns the JSON Pointer of a node
+func (n pathNode) pointer() string {
+	return pointer("", n.tokens...)
+}
+
+// compileJsonPath parses a JSONPath expression
+func compileJsonPath(expr string) (*jsonPath, error) {
+	p := &pathParser{expr: expr}
+	p.skipSpace()
+	if !p.consume("$") {
+		return nil, p.errorf("must start with $")
+	}
+	path, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.expr) {
+		return nil, p.errorf("unexpected %q", p.expr[p.pos:])
+	}
+	return path, nil
+}
+
+// find returns the nodes of a value, as decoded from JSON, that a path matches
+func (path *jsonPath) find(root interface{}) []pathNode {
+	return path.from(root, []pathNode{{tokens: []string{}, value: root}})
+}
+
+// from returns the nodes that a path matches starting from some nodes
+func (path *jsonPath) from(root interface{}, nodes []pathNode) []pathNode {
+	for _, seg := range path.segments {
+		if seg.descendants {
+			all := make([]pathNode, 0)
+			for _, n := range nodes {
+				all = descendants(n, all)
+			}
+			nodes = all
+		}
+		next := make([]pathNode, 0)
+		for _, n := range nodes {
+			for _, sel := range seg.selectors {
+				next = sel.apply(root, n, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants appends a node and all the nodes below it, in document order with object keys sorted
+func descendants(n pathNode, list []pathNode) []pathNode {
+	list = append(list, n)
+	for _, c := range children(n) {
+		list = descendants(c, list)
+	}
+	return list
+}
+
+// children returns the members of an object, sorted by key, or the elements of an array
+func children(n pathNode) []pathNode {
+	var list []pathNode
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		for _, k := range keys(v) {
+			list = append(list, n.child(k, v[k]))
+		}
+	case []interface{}:
+		for i, e := range v {
+			list = append(list, n.child(strconv.Itoa(i), e))
+		}
+	}
+	return list
+}
+
+// child returns a node below another one
+func (n pathNode) child(token string, value interface{}) pathNode {
+	tokens := make([]string, len(n.tokens)+1)
+	copy(tokens, n.tokens)
+	tokens[len(n.tokens)] = token
+	return pathNode{tokens: tokens, value: value}
+}
+
+// apply appends the children of a node that a selector matches
+func (sel pathSelector) apply(root interface{}, n pathNode, list []pathNode) []pathNode {
+	switch sel.kind {
+	case selectName:
+		if m, ok := n.value.(map[string]interface{}); ok {
+			if v, found := m[sel.name]; found {
+				list = append(list, n.child(sel.name, v))
+			}
+		}
+	case selectIndex:
+		if a, ok := n.value.([]interface{}); ok {
+			i := sel.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				list = append(list, n.child(strconv.Itoa(i), a[i]))
+			}
+		}
+	case selectSlice:
+		if a, ok := n.value.([]interface{}); ok {
+			for _, i := range sel.indexes(len(a)) {
+				list = append(list, n.child(strconv.Itoa(i), a[i]))
+			}
+		}
+	case selectAll:
+		list = append(list, children(n)...)
+	case selectFilter:
+		for _, c := range children(n) {
+			if truthy(sel.filter.eval(root, c)) {
+				list = append(list, c)
+			}
+		}
+	}
+	return list
+}
+
+// indexes returns the indexes of an array of a length that a slice selects
+func (sel pathSelector) indexes(length int) []int {
+	step := 1
+	if sel.step != nil {
+		step = *sel.step
+	}
+	if step == 0 {
+		return nil
+	}
+	bound := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += length
+		}
+		if i < -1 {
+			i = -1
+		}
+		if i > length {
+			i = length
+		}
+		return i
+	}
+	list := make([]int, 0)
+	if step > 0 {
+		start, end := bound(sel.start, 0), bound(sel.end, length)
+		if start < 0 {
+			start = 0
+		}
+		for i := start; i < end; i += step {
+			list = append(list, i)
+		}
+	} else {
+		start, end := bound(sel.start, length-1), bound(sel.end, -1)
+		if start >= length {
+			start = length - 1
+		}
+		for i := start; i > end; i += step {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// pathFilter is an expression of a filter selector
+type pathFilter interface {
+	eval(root interface{}, current pathNode) interface{}
+}
+
+// filterPath is a path relative to the current node or to the root. It evaluates to the first value it matches,
+// or to noValue.
+type filterPath struct {
+	relative bool
+	path     *jsonPath
+}
+
+// noValue is the value of a path that matches nothing
+type noValue struct{}
+
+func (f filterPath) eval(root interface{}, current pathNode) interface{} {
+	start := pathNode{tokens: []string{}, value: root}
+	if f.relative {
+		start = current
+	}
+	if nodes := f.path.from(root, []pathNode{start}); len(nodes) > 0 {
+		return nodes[0].value
+	}
+	return noValue{}
+}
+
+// filterLiteral is a string, number, boolean or null
+type filterLiteral struct {
+	value interface{}
+}
+
+func (f filterLiteral) eval(interface{}, pathNode) interface{} {
+	return f.value
+}
+
+// filterNot negates an expression
+type filterNot struct {
+	expr pathFilter
+}
+
+func (f filterNot) eval(root interface{}, current pathNode) interface{} {
+	return !truthy(f.expr.eval(root, current))
+}
+
+// filterBinary combines or compares two expressions
+type filterBinary struct {
+	op          string
+	left, right pathFilter
+}
+
+func (f filterBinary) eval(root interface{}, current pathNode) interface{} {
+	l := f.left.eval(root, current)
+	switch f.op {
+	case "&&":
+		return truthy(l) && truthy(f.right.eval(root, current))
+	case "||":
+		return truthy(l) || truthy(f.right.eval(root, current))
+	}
+	r := f.right.eval(root, current)
+	_, lmissing := l.(noValue)
+	_, rmissing := r.(noValue)
+	switch f.op {
+	case "==":
+		return lmissing == rmissing && reflect.DeepEqual(l, r)
+	case "!=":
+		return lmissing != rmissing || !reflect.DeepEqual(l, r)
+	}
+	if lf, ok := l.(float64); ok {
+		if rf, ok := r.(float64); ok {
+			return compare(f.op, lf < rf, lf == rf)
+		}
+	}
+	if ls, ok := l.(string); ok {
+		if rs, ok := r.(string); ok {
+			return compare(f.op, ls < rs, ls == rs)
+		}
+	}
+	return false
+}
+
+// compare returns the result of an ordering operator
+func compare(op string, less, equal bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+// truthy tells whether the value of an expression selects a node: paths that match something, except false
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case noValue:
+		return false
+	case bool:
+		return v
+	}
+	return true
+}
+
+// pathParser parses JSONPath expressions
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("jsonpath %s: at %d: %s", p.expr, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.expr) && unicode.IsSpace(rune(p.expr[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips a string if it comes next
+func (p *pathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// segments parses the segments that follow $ or @
+func (p *pathParser) segments() (*jsonPath, error) {
+	path := &jsonPath{segments: make([]pathSegment, 0)}
+	for {
+		switch {
+		case p.consume(".."):
+			seg := pathSegment{descendants: true}
+			if strings.HasPrefix(p.expr[p.pos:], "[") {
+				sels, err := p.bracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = sels
+			} else {
+				sel, err := p.dotted()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = []pathSelector{sel}
+			}
+			path.segments = append(path.segments, seg)
+		case p.consume("."):
+			sel, err := p.dotted()
+			if err != nil {
+				return nil, err
+			}
+			path.segments = append(path.segments, pathSegment{selectors: []pathSelector{sel}})
+		case strings.HasPrefix(p.expr[p.pos:], "["):
+			sels, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			path.segments = append(path.segments, pathSegment{selectors: sels})
+		default:
+			return path, nil
+		}
+	}
+}
+
+// dotted parses the name or wildcard after a dot
+func (p *pathParser) dotted() (pathSelector, error) {
+	if p.consume("*") {
+		return pathSelector{kind: selectAll}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && !strings.ContainsRune(".[]()!=<>&|,' \t\n\"", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return pathSelector{}, p.errorf("name expected")
+	}
+	return pathSelector{kind: selectName, name: p.expr[start:p.pos]}, nil
+}
+
+// bracket parses the selectors between brackets
+func (p *pathParser) bracket() ([]pathSelector, error) {
+	p.consume("[")
+	sels := make([]pathSelector, 0)
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+		p.skipSpace()
+		if p.consume("]") {
+			return sels, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("] expected")
+		}
+	}
+}
+
+// selector parses a selector between brackets
+func (p *pathParser) selector() (pathSelector, error) {
+	switch {
+	case p.consume("*"):
+		return pathSelector{kind: selectAll}, nil
+	case p.consume("?"):
+		p.skipSpace()
+		filter, err := p.or()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{kind: selectFilter, filter: filter}, nil
+	case p.pos < len(p.expr) && (p.expr[p.pos] == '\'' || p.expr[p.pos] == '"'):
+		s, err := p.quoted()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{kind: selectName, name: s}, nil
+	}
+	var bounds [3]*int
+	colons := 0
+	for i := 0; i < 3; i++ {
+		p.skipSpace()
+		if n, ok := p.integer(); ok {
+			bounds[i] = &n
+		}
+		p.skipSpace()
+		if i == 2 || !p.consume(":") {
+			break
+		}
+		colons++
+	}
+	if colons == 0 {
+		if bounds[0] == nil {
+			return pathSelector{}, p.errorf("selector expected")
+		}
+		return pathSelector{kind: selectIndex, index: *bounds[0]}, nil
+	}
+	return pathSelector{kind: selectSlice, start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+}
+
+// integer parses an optionally negative integer
+func (p *pathParser) integer() (int, bool) {
+	start := p.pos
+	if p.pos < len(p.expr) && p.expr[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+// quoted parses a string between single or double quotes, with backslash escapes
+func (p *pathParser) quoted() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.expr):
+			e := p.expr[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// or parses a filter expression, with || binding less than &&
+func (p *pathParser) or() (pathFilter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpace(); p.consume("||"); p.skipSpace() {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = filterBinary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *pathParser) and() (pathFilter, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpace(); p.consume("&&"); p.skipSpace() {
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		left = filterBinary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *pathParser) comparison() (pathFilter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			p.skipSpace()
+			right, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			return filterBinary{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *pathParser) unary() (pathFilter, error) {
+	p.skipSpace()
+	switch {
+	case p.consume("!"):
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{expr}, nil
+	case p.consume("("):
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace(); !p.consume(")") {
+			return nil, p.errorf(") expected")
+		}
+		return expr, nil
+	case p.consume("@"), p.consume("$"):
+		relative := p.expr[p.pos-1] == '@'
+		path, err := p.segments()
+		if err != nil {
+			return nil, err
+		}
+		return filterPath{relative: relative, path: path}, nil
+	case p.pos < len(p.expr) && (p.expr[p.pos] == '\'' || p.expr[p.pos] == '"'):
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return filterLiteral{s}, nil
+	case p.consume("true"):
+		return filterLiteral{true}, nil
+	case p.consume("false"):
+		return filterLiteral{false}, nil
+	case p.consume("null"):
+		return filterLiteral{nil}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && strings.ContainsRune("+-.0123456789eE", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("filter expression expected")
+	}
+	return filterLiteral{f}, nil
+}
@@ -0,0 +1,68 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathDocument = `{
+  "paths": {
+    "/pets": {
+      "get": {"tags": ["pets"], "x-audience": "public", "responses": {"200": {"description": "OK"}}},
+      "post": {"tags": ["pets"], "deprecated": true, "responses": {"201": {"description": "Created"}}}
+    },
+    "/stats": {
+      "get": {"tags": ["admin"], "responses": {"200": {"description": "OK"}}}
+    }
+  },
+  "tags": [{"name": "pets"}, {"name": "admin"}, {"name": "billing"}],
+  "definitions": {"Pet": {"properties": {"age": {"type": "integer", "minimum": 0}, "name": {"type": "string"}}}}
+}`
+
+func TestJsonPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(jsonPathDocument), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr     string
+		pointers string
+	}{
+		{"$", ""},
+		{"$.paths['/pets'].get", "/paths/~1pets/get"},
+		{`$.paths["/pets"]['get','post'].tags[0]`, "/paths/~1pets/get/tags/0 /paths/~1pets/post/tags/0"},
+		{"$.paths.*.*", "/paths/~1pets/get /paths/~1pets/post /paths/~1stats/get"},
+		{"$.paths.*[?(@.deprecated == true)]", "/paths/~1pets/post"},
+		{"$.paths.*[?@.x-audience]", "/paths/~1pets/get"},
+		{"$.paths.*[?(!@.deprecated && @.tags[0] != 'admin')]", "/paths/~1pets/get"},
+		{"$.paths.*.*.responses[?(@.description == 'OK' || @.description == 'Created')]", "/paths/~1pets/get/responses/200 /paths/~1pets/post/responses/201 /paths/~1stats/get/responses/200"},
+		{"$..minimum", "/definitions/Pet/properties/age/minimum"},
+		{"$..properties[?(@.minimum >= 0)]", "/definitions/Pet/properties/age"},
+		{"$..[?(@.type == 'string')]", "/definitions/Pet/properties/name"},
+		{"$.tags[-1].name", "/tags/2/name"},
+		{"$.tags[1:]", "/tags/1 /tags/2"},
+		{"$.tags[::-2]", "/tags/2 /tags/0"},
+		{"$.tags[?(@.name == $.paths['/stats'].get.tags[0])]", "/tags/1"},
+		{"$.nothing.here", ""},
+	}
+	for _, test := range tests {
+		path, err := compileJsonPath(test.expr)
+		if err != nil {
+			t.Errorf("%s: %s", test.expr, err)
+			continue
+		}
+		pointers := make([]string, 0)
+		for _, n := range path.find(doc) {
+			pointers = append(pointers, n.pointer())
+		}
+		if got := strings.Join(pointers, " "); got != test.pointers {
+			t.Errorf("%s: expected %q, got %q", test.expr, test.pointers, got)
+		}
+	}
+	for _, expr := range []string{"paths", "$.", "$[", "$['a'", "$[?(@.a ==)]", "$.a b"} {
+		if _, err := compileJsonPath(expr); err == nil {
+			t.Errorf("%s: expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package swagger2
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Draft identifies the JSON Schema dialect written by the exporter. Its value is used as the $schema of the document.
+type Draft string
+
+const (
+	Draft04     Draft = "http://json-schema.org/draft-04/schema#"      // JSON Schema draft-04, the dialect Swagger 2 schemas are based on
+	Draft202012 Draft = "https://json-schema.org/draft/2020-12/schema" // JSON Schema 2020-12
+)
+
+// JsonSchema exports all of the definitions as a single JSON Schema document, with each definition placed under
+// "definitions" (draft-04) or "$defs" (2020-12). The returned errors list anything that could not be expressed.
+func (d Definitions) JsonSchema(draft Draft) (map[string]interface{}, []error) {
+	x := newSchemaExporter(d, draft)
+	defs := make(map[string]interface{})
+	for _, name := range x.names() {
+		def := d[name]
+		defs[name] = x.schema("definitions."+name, name, &def)
+	}
+	doc := map[string]interface{}{"$schema": string(draft)}
+	if len(defs) > 0 {
+		doc[x.defsKey()] = defs
+	}
+	return doc, x.errs
+}
+
+// JsonSchema exports the schema as a standalone JSON Schema document. Definitions it refers to, directly or
+// indirectly, are looked up in defs and included in the document. The returned errors list anything that could
+// not be expressed.
+func (s *Schema) JsonSchema(draft Draft, defs Definitions) (map[string]interface{}, []error) {
+	x := newSchemaExporter(defs, draft)
+	doc := x.schema("schema", "", s)
+	doc["$schema"] = string(draft)
+
+	// keep pulling in referenced definitions until there are no new ones
+	included := make(map[string]interface{})
+	for len(x.pending) > 0 {
+		name := x.pending[0]
+		x.pending = x.pending[1:]
+		if _, ok := included[name]; ok {
+			continue
+		}
+		def := defs[name]
+		included[name] = x.schema("definitions."+name, name, &def)
+	}
+	if len(included) > 0 {
+		doc[x.defsKey()] = included
+	}
+	return doc, x.errs
+}
+
+// schemaExporter holds the state of a JSON Schema export
+type schemaExporter struct {
+	defs     Definitions
+	draft    Draft
+	subtypes map[string][]string // definitions that extend a definition with a discriminator
+	pending  []string            // referenced definitions
+	errs     []error
+}
+
+// newSchemaExporter prepares an export, finding the definitions that inherit from each discriminated definition
+func newSchemaExporter(defs Definitions, draft Draft) *schemaExporter {
+	x := &schemaExporter{defs: defs, draft: draft, subtypes: make(map[string][]string), errs: make([]error, 0)}
+	for _, name := range x.names() {
+		for _, parent := range defs[name].AllOf {
+			if base := x.definition(parent.Ref); base != "" && defs[base].Discriminator != "" {
+				x.subtypes[base] = append(x.subtypes[base], name)
+			}
+		}
+	}
+	return x
+}
+
+// names returns the definition names in sorted order
+func (x *schemaExporter) names() []string {
+	names := make([]string, 0, len(x.defs))
+	for n := range x.defs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unsupported records something that could not be expressed
+func (x *schemaExporter) unsupported(where, format string, args ...interface{}) {
+	x.errs = append(x.errs, fmt.Errorf(where+": "+format, args...))
+}
+
+// defsKey is the keyword that holds the definitions in the chosen draft
+func (x *schemaExporter) defsKey() string {
+	if x.draft == Draft04 {
+		return "definitions"
+	}
+	return "$defs"
+}
+
+// definition returns the name of the definition a reference points at
+func (x *schemaExporter) definition(ref string) string {
+	name, _ := x.defs.RefName(ref)
+	return name
+}
+
+// ref rewrites a reference to a definition into the location used by the exported document
+func (x *schemaExporter) ref(where, ref string) string {
+	name := x.definition(ref)
+	if name == "" {
+		x.unsupported(where, "reference %s is not to a definition and was left as is", ref)
+		return ref
+	}
+	if _, ok := x.defs[name]; !ok {
+		x.unsupported(where, "reference %s is to a missing definition", ref)
+	} else {
+		x.pending = append(x.pending, name)
+	}
+	return MakeRef(x.defsKey(), name)
+}
+
+// items exports the fields common to every kind of schema
+func (x *schemaExporter) items(where string, d *ItemsDef) map[string]interface{} {
+	out := make(map[string]interface{})
+	if d.Ref != "" {
+		out["$ref"] = x.ref(where, d.Ref)
+	}
+	switch d.Type {
+	case "":
+	case "file":
+		x.unsupported(where, "type file has no JSON Schema equivalent; exported as a binary string")
+		out["type"] = "string"
+		if x.draft == Draft04 {
+			out["format"] = "binary"
+		} else {
+			out["contentMediaType"] = "application/octet-stream"
+		}
+	default:
+		out["type"] = d.Type
+	}
+	if d.Format != "" {
+		out["format"] = d.Format
+	}
+	if d.Items != nil {
+		out["items"] = x.items(where+".items", d.Items)
+	}
+	if d.CollectionFormat != "" {
+		x.unsupported(where, "collectionFormat has no JSON Schema equivalent; dropped")
+	}
+	if d.Default != nil {
+		out["default"] = d.Default
+	}
+	x.limit(out, "maximum", "exclusiveMaximum", d.Maximum, d.ExclusiveMaximum)
+	x.limit(out, "minimum", "exclusiveMinimum", d.Minimum, d.ExclusiveMinimum)
+	if d.MaxLength != nil {
+		out["maxLength"] = *d.MaxLength
+	}
+	if d.MinLength != nil {
+		out["minLength"] = *d.MinLength
+	}
+	if d.Pattern != nil {
+		out["pattern"] = *d.Pattern
+	}
+	if d.MaxItems != nil {
+		out["maxItems"] = *d.MaxItems
+	}
+	if d.MinItems != nil {
+		out["minItems"] = *d.MinItems
+	}
+	if d.UniqueItems != nil {
+		out["uniqueItems"] = *d.UniqueItems
+	}
+	if d.Enum != nil {
+		out["enum"] = d.Enum
+	}
+	if d.MultipleOf != nil {
+		out["multipleOf"] = *d.MultipleOf
+	}
+	if d.AdditionalProperties != nil {
+		out["additionalProperties"] = x.items(where+".additionalProperties", d.AdditionalProperties)
+	}
+	return out
+}
+
+// limit exports a maximum or minimum. Draft-04 uses a boolean modifier for exclusive limits, later drafts hold the limit itself.
+func (x *schemaExporter) limit(out map[string]interface{}, name, exclusive string, value *float64, excl *bool) {
+	if value == nil {
+		return
+	}
+	switch {
+	case excl == nil || x.draft == Draft04:
+		out[name] = *value
+		if excl != nil {
+			out[exclusive] = *excl
+		}
+	case *excl:
+		out[exclusive] = *value
+	default:
+		out[name] = *value
+	}
+}
+
+// schema exports a schema. name is set when exporting a definition, and is used to translate its discriminator.
+func (x *schemaExporter) schema(where, name string, s *Schema) map[string]interface{} {
+	out := x.items(where, &s.ItemsDef)
+	if s.Title != "" {
+		out["title"] = s.Title
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.MaxProperties != nil {
+		out["maxProperties"] = *s.MaxProperties
+	}
+	if s.MinProperties != nil {
+		out["minProperties"] = *s.MinProperties
+	}
+	if s.Required != nil {
+		out["required"] = s.Required
+	}
+	if s.AllOf != nil {
+		all := make([]interface{}, 0, len(s.AllOf))
+		for i := range s.AllOf {
+			all = append(all, x.schema(fmt.Sprintf("%s.allOf[%d]", where, i), "", &s.AllOf[i]))
+		}
+		out["allOf"] = all
+	}
+	if s.Properties != nil {
+		props := make(map[string]interface{})
+		for n, p := range s.Properties {
+			props[n] = x.schema(where+"."+n, "", &p)
+		}
+		out["properties"] = props
+	}
+	if s.ReadOnly != nil && *s.ReadOnly {
+		if x.draft == Draft04 {
+			x.unsupported(where, "readOnly is not part of draft-04; dropped")
+		} else {
+			out["readOnly"] = true
+		}
+	}
+	if s.Example != nil {
+		if x.draft == Draft04 {
+			x.unsupported(where, "example is not part of draft-04; dropped")
+		} else {
+			out["examples"] = []interface{}{s.Example}
+		}
+	}
+	if s.Xml != nil {
+		x.unsupported(where, "xml has no JSON Schema equivalent; dropped")
+	}
+	if s.ExternalDocs != nil {
+		x.unsupported(where, "externalDocs has no JSON Schema equivalent; dropped")
+	}
+	if s.Discriminator != "" {
+		x.discriminator(where, name, s, out)
+	}
+	x.inherit(name, s, out)
+	if s.Extensions.Bool("x-nullable") {
+		out = nullable(out)
+	}
+	for k, v := range s.Extensions {
+		if k != "x-nullable" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// discriminator limits the discriminator property of a base definition to the names of the base and its sub types
+func (x *schemaExporter) discriminator(where, name string, s *Schema, out map[string]interface{}) {
+	props, _ := out["properties"].(map[string]interface{})
+	prop, _ := props[s.Discriminator].(map[string]interface{})
+	if prop == nil || name == "" {
+		x.unsupported(where, "discriminator %s has no JSON Schema equivalent; dropped", s.Discriminator)
+		return
+	}
+	values := []interface{}{name}
+	for _, sub := range x.subtypes[name] {
+		values = append(values, sub)
+	}
+	prop["enum"] = values
+}
+
+// inherit pins the discriminator property of a definition that extends a discriminated definition to its own name
+func (x *schemaExporter) inherit(name string, s *Schema, out map[string]interface{}) {
+	if name == "" {
+		return
+	}
+	for _, parent := range s.AllOf {
+		base := x.definition(parent.Ref)
+		if base == "" || x.defs[base].Discriminator == "" {
+			continue
+		}
+		var value interface{} = map[string]interface{}{"enum": []interface{}{name}}
+		if x.draft != Draft04 {
+			value = map[string]interface{}{"const": name}
+		}
+		pin := map[string]interface{}{
+			"properties": map[string]interface{}{x.defs[base].Discriminator: value},
+		}
+		out["allOf"] = append(out["allOf"].([]interface{}), pin)
+	}
+}
+
+// nullable allows null in addition to the values the schema describes
+func nullable(out map[string]interface{}) map[string]interface{} {
+	if t, ok := out["type"].(string); ok && out["$ref"] == nil {
+		out["type"] = []interface{}{t, "null"}
+		if enum, ok := out["enum"].([]interface{}); ok {
+			out["enum"] = append(enum, nil)
+		}
+		return out
+	}
+	return map[string]interface{}{
+		"anyOf": []interface{}{out, map[string]interface{}{"type": "null"}},
+	}
+}
@@ -0,0 +1,132 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const polymorphic = `{
+  "Pet": {
+    "type": "object",
+    "discriminator": "petType",
+    "required": ["name", "petType"],
+    "properties": {
+      "name": {"type": "string", "readOnly": true},
+      "petType": {"type": "string"},
+      "owner": {"$ref": "#/definitions/Owner", "x-nullable": true}
+    }
+  },
+  "Cat": {
+    "allOf": [
+      {"$ref": "#/definitions/Pet"},
+      {"type": "object", "properties": {"lives": {"type": "integer", "maximum": 9, "exclusiveMaximum": true, "x-nullable": true}}}
+    ]
+  },
+  "Owner": {"type": "object", "properties": {"photo": {"type": "file"}}},
+  "Unused": {"type": "string"}
+}`
+
+func loadPolymorphic(t *testing.T) Definitions {
+	var defs Definitions
+	if err := json.Unmarshal([]byte(polymorphic), &defs); err != nil {
+		t.Fatal(err)
+	}
+	return defs
+}
+
+// dig follows a path of object keys and array indexes through an exported document
+func dig(v interface{}, path ...interface{}) interface{} {
+	for _, p := range path {
+		switch k := p.(type) {
+		case string:
+			m, _ := v.(map[string]interface{})
+			v = m[k]
+		case int:
+			a, _ := v.([]interface{})
+			if k >= len(a) {
+				return nil
+			}
+			v = a[k]
+		}
+	}
+	return v
+}
+
+func TestDefinitionsJsonSchema(t *testing.T) {
+	defs := loadPolymorphic(t)
+
+	doc, errs := defs.JsonSchema(Draft04)
+	if doc["$schema"] != string(Draft04) || dig(doc, "definitions", "Cat", "allOf", 0, "$ref") != "#/definitions/Pet" {
+		t.Errorf("unexpected draft-04 document: %v", doc)
+	}
+	if enum := dig(doc, "definitions", "Pet", "properties", "petType", "enum"); len(enum.([]interface{})) != 2 {
+		t.Errorf("discriminator should list the base and its sub types, got %v", enum)
+	}
+	if pin := dig(doc, "definitions", "Cat", "allOf", 2, "properties", "petType", "enum", 0); pin != "Cat" {
+		t.Errorf("sub type should pin the discriminator, got %v", pin)
+	}
+	if limit := dig(doc, "definitions", "Cat", "allOf", 1, "properties", "lives", "exclusiveMaximum"); limit != true {
+		t.Errorf("draft-04 exclusive limits are booleans, got %v", limit)
+	}
+	if typ := dig(doc, "definitions", "Cat", "allOf", 1, "properties", "lives", "type"); len(typ.([]interface{})) != 2 {
+		t.Errorf("x-nullable should allow null, got %v", typ)
+	}
+	text := ErrorList(errs).String()
+	if !strings.Contains(text, "readOnly") || !strings.Contains(text, "file") {
+		t.Errorf("expected readOnly and file to be reported, got:\n%s", text)
+	}
+
+	doc, errs = defs.JsonSchema(Draft202012)
+	if dig(doc, "$defs", "Cat", "allOf", 0, "$ref") != "#/$defs/Pet" {
+		t.Errorf("references should point at $defs: %v", dig(doc, "$defs", "Cat"))
+	}
+	if limit := dig(doc, "$defs", "Cat", "allOf", 1, "properties", "lives", "exclusiveMaximum"); limit != 9.0 {
+		t.Errorf("2020-12 exclusive limits are numbers, got %v", limit)
+	}
+	if dig(doc, "$defs", "Pet", "properties", "name", "readOnly") != true {
+		t.Errorf("readOnly should be kept in 2020-12")
+	}
+	if ref := dig(doc, "$defs", "Pet", "properties", "owner", "anyOf", 0, "$ref"); ref != "#/$defs/Owner" {
+		t.Errorf("nullable references should become anyOf, got %v", dig(doc, "$defs", "Pet", "properties", "owner"))
+	}
+	if strings.Contains(ErrorList(errs).String(), "readOnly") {
+		t.Errorf("readOnly should not be reported for 2020-12")
+	}
+}
+
+func TestSchemaJsonSchema(t *testing.T) {
+	defs := loadPolymorphic(t)
+	root := &Schema{ItemsDef: ItemsDef{Type: "array", Items: &ItemsDef{Ref: "#/definitions/Cat"}}}
+	doc, _ := root.JsonSchema(Draft202012, defs)
+	included := doc["$defs"].(map[string]interface{})
+	if len(included) != 3 || included["Unused"] != nil {
+		t.Errorf("expected only the referenced definitions to be included, got %d", len(included))
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSchemaExtensions(t *testing.T) {
+	in := `{"type":"string","x-nullable":true,"x-order":{"n":1}}`
+	var s Schema
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Extensions.Bool("x-nullable") || len(s.Extensions) != 2 {
+		t.Errorf("extensions were not read: %v", s.Extensions)
+	}
+	out, err := json.Marshal(s)
+	if err != nil || string(out) != in {
+		t.Errorf("extensions were not written back: %s %v", out, err)
+	}
+
+	swag, err := LoadYaml([]byte("definitions:\n  Name:\n    type: string\n    x-nullable: true\n    unknown: 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext := swag.Definitions["Name"].Extensions; !ext.Bool("x-nullable") || len(ext) != 1 {
+		t.Errorf("only vendor extensions should be kept from YAML, got %v", ext)
+	}
+}
@@ -0,0 +1,426 @@
+package swagger2
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ConflictStrategy tells Merge what to do when documents define the same name differently.
+type ConflictStrategy int
+
+// The strategies for conflicts.
+const (
+	ConflictError     ConflictStrategy = iota // Merging fails
+	ConflictPrefix                            // The definition of a later document is renamed with its name as a prefix
+	ConflictKeepFirst                         // The definition of the first document wins
+)
+
+// MergeOptions configures Merge.
+type MergeOptions struct {
+	// Info describes the merged document. The information of the first document is used if the title is empty.
+	Info Info
+
+	// Strategy tells what to do with definitions, shared parameters and responses, and security definitions of the
+	// same name that differ. Operations on the same path and method always conflict, and the first one is kept
+	// unless the strategy is ConflictError.
+	Strategy ConflictStrategy
+
+	// Names holds the names of the documents, such as the services they describe, which prefix the names of
+	// renamed definitions and appear in conflicts. They default to the titles of the documents without spaces, followed
+	// by the position of the document when two titles are the same.
+	Names []string
+
+	// PrefixBasePath prefixes the paths of each document with its base path, so that the merged document, which has
+	// no base path, serves them all. Otherwise the base path of the first document is used, and the others conflict
+	// with it if they differ.
+	PrefixBasePath bool
+}
+
+// Conflict is something that documents being merged define differently.
+type Conflict struct {
+	Section    string   // "paths", "definitions", "parameters", "responses", "securityDefinitions" or "basePath"
+	Name       string   // What conflicts, such as "Pet" or "/pets.get"
+	Sources    []string // The names of the documents, starting with the one whose definition was kept
+	Resolution string   // What was done, such as "renamed to billingPet"
+}
+
+// String describes the conflict, such as "definitions.Pet: defined differently by shelter and billing; renamed to
+// billingPet".
+func (c Conflict) String() string {
+	where := c.Section
+	if c.Name != "" {
+		where += "." + c.Name
+	}
+	text := fmt.Sprintf("%s: defined differently by %s", where, strings.Join(c.Sources, " and "))
+	if c.Resolution != "" {
+		text += "; " + c.Resolution
+	}
+	return text
+}
+
+// Merge combines documents, such as those of the services behind a gateway, into one. It takes the paths, the
+// definitions, shared parameters and responses, security definitions and tags of each of them. Operations keep the
+// security, schemes and media types they had in their document when these differ from those of the merged
+// document, which are those of the first one, and path parameters stay with the operations of their document when
+// two documents describe the same path. Definitions of the same name are only a conflict when they differ. Merge
+// returns the conflicts it resolved, or an error that lists them all with ConflictError. The documents are not
+// modified.
+func Merge(opts MergeOptions, docs ...*Swagger) (*Swagger, []Conflict, error) {
+	if len(docs) == 0 {
+		return nil, nil, errors.New("no documents to merge")
+	}
+	sources := make([]*Swagger, len(docs))
+	for i, doc := range docs {
+		src, err := clone(doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("document %d: %s", i+1, err)
+		}
+		sources[i] = src
+	}
+	first := sources[0]
+	merged := &Swagger{
+		Swagger:             "2.0",
+		Info:                first.Info,
+		Host:                first.Host,
+		BasePath:            first.BasePath,
+		Schemes:             first.Schemes,
+		Consumes:            first.Consumes,
+		Produces:            first.Produces,
+		Security:            first.Security,
+		Paths:               make(Paths),
+		Definitions:         make(Definitions),
+		Parameters:          make(Parameters),
+		Responses:           make(Responses),
+		SecurityDefinitions: make(SecurityDefinitions),
+		ExternalDocs:        first.ExternalDocs,
+	}
+	if opts.Info.Title != "" {
+		merged.Info = opts.Info
+	}
+	if opts.PrefixBasePath {
+		merged.BasePath = ""
+	}
+	m := &merger{opts: opts, merged: merged, names: make([]string, len(docs)), owners: make(map[string]int)}
+	for i, src := range sources {
+		m.names[i] = fmt.Sprintf("document %d", i+1)
+		if i < len(opts.Names) && opts.Names[i] != "" {
+			m.names[i] = opts.Names[i]
+		} else if title := strings.Map(identifier, src.Info.Title); title != "" {
+			m.names[i] = title
+			for j := 0; j < i; j++ {
+				if m.names[j] == title {
+					m.names[i] = fmt.Sprintf("%s%d", title, i+1)
+				}
+			}
+		}
+	}
+	for i, src := range sources {
+		m.add(i, src)
+	}
+	if opts.Strategy == ConflictError && len(m.conflicts) > 0 {
+		list := make([]string, len(m.conflicts))
+		for i, c := range m.conflicts {
+			list[i] = c.String()
+		}
+		return nil, m.conflicts, errors.New(strings.Join(list, "\n"))
+	}
+	if len(merged.Definitions) == 0 {
+		merged.Definitions = nil
+	}
+	if len(merged.Parameters) == 0 {
+		merged.Parameters = nil
+	}
+	if len(merged.Responses) == 0 {
+		merged.Responses = nil
+	}
+	if len(merged.SecurityDefinitions) == 0 {
+		merged.SecurityDefinitions = nil
+	}
+	return merged, m.conflicts, nil
+}
+
+// identifier keeps the letters and digits of a title for the name of a document
+func identifier(r rune) rune {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return r
+	}
+	return -1
+}
+
+// clone returns a deep copy of a document
+func clone(s *Swagger) (*Swagger, error) {
+	b, err := s.Json()
+	if err != nil {
+		return nil, err
+	}
+	return LoadJson(b)
+}
+
+// merger holds the state of a merge
+type merger struct {
+	opts      MergeOptions
+	merged    *Swagger
+	names     []string
+	owners    map[string]int // the document each name in the merged document comes from, by section and name
+	conflicts []Conflict
+}
+
+// conflict records a conflict between the document that defined a name first and another one
+func (m *merger) conflict(section, name string, i int, resolution string) {
+	owner := m.owners[section+"."+name] // the first document owns the base path
+	if m.opts.Strategy == ConflictError {
+		resolution = ""
+	}
+	m.conflicts = append(m.conflicts, Conflict{Section: section, Name: name, Sources: []string{m.names[owner], m.names[i]}, Resolution: resolution})
+}
+
+// add merges a copy of a document, which it changes
+func (m *merger) add(i int, src *Swagger) {
+	merged := m.merged
+	if !m.opts.PrefixBasePath && i > 0 && strings.TrimRight(src.BasePath, "/") != strings.TrimRight(merged.BasePath, "/") {
+		base := merged.BasePath
+		if base == "" {
+			base = "/"
+		}
+		m.conflict("basePath", "", i, "the paths are served under "+base)
+	}
+
+	// Rename what conflicts first, so that the references of the document can follow. Renaming a definition
+	// changes those that refer to it, which may then conflict in turn, so this goes on until nothing changes.
+	definitions, parameters := make(map[string]string), make(map[string]string)
+	responses, schemes := make(map[string]string), make(map[string]string)
+	for {
+		changed := m.section(i, "definitions", src.Definitions, merged.Definitions, definitions)
+		changed = m.section(i, "parameters", src.Parameters, merged.Parameters, parameters) || changed
+		changed = m.section(i, "responses", src.Responses, merged.Responses, responses) || changed
+		changed = m.section(i, "securityDefinitions", src.SecurityDefinitions, merged.SecurityDefinitions, schemes) || changed
+		if !changed {
+			break
+		}
+		src.rename(definitions, parameters, schemes)
+	}
+
+	m.copy(i, "definitions", src.Definitions, merged.Definitions, definitions)
+	m.copy(i, "parameters", src.Parameters, merged.Parameters, parameters)
+	m.copy(i, "responses", src.Responses, merged.Responses, responses)
+	m.copy(i, "securityDefinitions", src.SecurityDefinitions, merged.SecurityDefinitions, schemes)
+
+	prefix := ""
+	if m.opts.PrefixBasePath {
+		prefix = strings.TrimRight(src.BasePath, "/")
+	}
+	for _, path := range keys(src.Paths) {
+		item := src.Paths[path]
+		target, exists := merged.Paths[prefix+path]
+		if exists && !reflect.DeepEqual(item.Parameters, target.Parameters) {
+			// The path parameters of each document only apply to its own operations
+			for _, list := range []*PathItem{&target, &item} {
+				for _, method := range Methods {
+					if op := list.Operation(method); op != nil {
+						op.Parameters = inherit(op.Parameters, list.Parameters)
+					}
+				}
+				list.Parameters = nil
+			}
+		}
+		if !exists {
+			target = PathItem{Parameters: item.Parameters}
+		}
+		for _, method := range Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			name := prefix + path + "." + method
+			if existing := target.Operation(method); existing != nil {
+				if !reflect.DeepEqual(existing, op) {
+					m.conflict("paths", name, i, "kept the first operation")
+				}
+				continue
+			}
+			m.owners["paths."+name] = i
+			if op.Security == nil && !reflect.DeepEqual(src.Security, merged.Security) {
+				op.Security = src.Security
+				if op.Security == nil {
+					op.Security = []Security{}
+				}
+			}
+			if op.Schemes == nil && !reflect.DeepEqual(src.Schemes, merged.Schemes) {
+				op.Schemes = src.Schemes
+			}
+			if op.Consumes == nil && !reflect.DeepEqual(src.Consumes, merged.Consumes) {
+				op.Consumes = src.Consumes
+			}
+			if op.Produces == nil && !reflect.DeepEqual(src.Produces, merged.Produces) {
+				op.Produces = src.Produces
+			}
+			target.SetOperation(method, op)
+		}
+		merged.Paths[prefix+path] = target
+	}
+
+	for _, tag := range src.Tags {
+		found := false
+		for _, t := range merged.Tags {
+			found = found || t.Name == tag.Name
+		}
+		if !found {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+}
+
+// inherit adds the path parameters that an operation does not override to its own parameters
+func inherit(params, pathParams []Parameter) []Parameter {
+	for _, p := range pathParams {
+		overridden := false
+		for _, o := range params {
+			overridden = overridden || o.Name == p.Name && o.In == p.In
+		}
+		if !overridden {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// section finds the names of a section of a document that are already in the merged document with a different
+// value, besides those it found before. It adds the new names of those that are renamed to renames, and the empty
+// name for those that are dropped, and returns true if it found any.
+func (m *merger) section(i int, section string, src, merged interface{}, renames map[string]string) bool {
+	found := false
+	s, t := reflect.ValueOf(src), reflect.ValueOf(merged)
+	taken := make(map[string]bool)
+	for _, renamed := range renames {
+		taken[renamed] = true
+	}
+	for _, name := range keys(src) {
+		if _, ok := renames[name]; ok {
+			continue
+		}
+		existing := t.MapIndex(reflect.ValueOf(name))
+		if !existing.IsValid() || reflect.DeepEqual(existing.Interface(), s.MapIndex(reflect.ValueOf(name)).Interface()) {
+			continue
+		}
+		found = true
+		if m.opts.Strategy != ConflictPrefix {
+			renames[name] = ""
+			m.conflict(section, name, i, "kept the first definition")
+			continue
+		}
+		renamed := m.names[i] + name
+		for n := 2; taken[renamed] || t.MapIndex(reflect.ValueOf(renamed)).IsValid() || s.MapIndex(reflect.ValueOf(renamed)).IsValid(); n++ {
+			renamed = fmt.Sprintf("%s%s%d", m.names[i], name, n)
+		}
+		renames[name], taken[renamed] = renamed, true
+		m.conflict(section, name, i, "renamed to "+renamed)
+	}
+	return found
+}
+
+// copy adds the entries of a section of a document to the merged document, under their new names
+func (m *merger) copy(i int, section string, src, merged interface{}, renames map[string]string) {
+	s, t := reflect.ValueOf(src), reflect.ValueOf(merged)
+	for _, name := range keys(src) {
+		target := name
+		if renamed, ok := renames[name]; ok {
+			if renamed == "" {
+				continue
+			}
+			target = renamed
+		}
+		if !t.MapIndex(reflect.ValueOf(target)).IsValid() {
+			m.owners[section+"."+target] = i
+			t.SetMapIndex(reflect.ValueOf(target), s.MapIndex(reflect.ValueOf(name)))
+		}
+	}
+}
+
+// rename changes the references of a document to renamed definitions and shared parameters, and the security
+// requirements of renamed security schemes. Dropped names, which map to "", are left alone. New names are never
+// names of the document, so renaming again with more names only changes what was not renamed yet.
+func (s *Swagger) rename(definitions, parameters, schemes map[string]string) {
+	ref := func(r *string) {
+		if name, ok := s.Definitions.RefName(*r); ok && definitions[name] != "" {
+			*r = MakeRef("definitions", definitions[name])
+		}
+	}
+	param := func(p *Parameter) {
+		if section, name := ParseRef(p.Ref); section == "parameters" && parameters[name] != "" {
+			p.Ref = MakeRef("parameters", parameters[name])
+		}
+		eachRef(p.Schema, ref)
+	}
+	security := func(list []Security) {
+		for _, req := range list {
+			for name, scopes := range req {
+				if schemes[name] != "" {
+					delete(req, name)
+					req[schemes[name]] = scopes
+				}
+			}
+		}
+	}
+	for name, def := range s.Definitions {
+		eachRef(&def, ref)
+		s.Definitions[name] = def
+	}
+	for name, p := range s.Parameters {
+		param(&p)
+		s.Parameters[name] = p
+	}
+	for name, r := range s.Responses {
+		eachRef(r.Schema, ref)
+		s.Responses[name] = r
+	}
+	security(s.Security)
+	for path, item := range s.Paths {
+		for i := range item.Parameters {
+			param(&item.Parameters[i])
+		}
+		for _, method := range Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			for i := range op.Parameters {
+				param(&op.Parameters[i])
+			}
+			for code, r := range op.Responses {
+				eachRef(r.Schema, ref)
+				op.Responses[code] = r
+			}
+			security(op.Security)
+		}
+		s.Paths[path] = item
+	}
+}
+
+// eachRef calls a function with each reference of a schema and of the schemas within it, so that it can change them
+func eachRef(s *Schema, fn func(ref *string)) {
+	if s == nil {
+		return
+	}
+	var items func(i *ItemsDef)
+	items = func(i *ItemsDef) {
+		if i == nil {
+			return
+		}
+		if i.Ref != "" {
+			fn(&i.Ref)
+		}
+		items(i.Items)
+		items(i.AdditionalProperties)
+	}
+	items(&s.ItemsDef)
+	for i := range s.AllOf {
+		eachRef(&s.AllOf[i], fn)
+	}
+	for name, p := range s.Properties {
+		eachRef(&p, fn)
+		s.Properties[name] = p
+	}
+}
@@ -0,0 +1,226 @@
+package swagger2
+
+import (
+	"strings"
+	"testing"
+)
+
+const mergeShelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+basePath: /shelter
+produces: [application/json]
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+security:
+  - key: []
+tags:
+  - {name: pets}
+parameters:
+  limit: {name: limit, in: query, type: integer}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      parameters:
+        - $ref: "#/parameters/limit"
+      responses:
+        200: {description: OK, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+  /health:
+    get:
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+`
+
+const mergeBilling = `
+swagger: "2.0"
+info: {title: Billing, version: "2.0"}
+basePath: /billing
+produces: [application/xml]
+securityDefinitions:
+  key: {type: apiKey, name: Authorization, in: header}
+security:
+  - key: []
+tags:
+  - {name: invoices}
+  - {name: pets}
+parameters:
+  limit: {name: limit, in: query, type: integer, maximum: 50}
+paths:
+  /invoices:
+    get:
+      tags: [invoices]
+      parameters:
+        - $ref: "#/parameters/limit"
+      responses:
+        200: {description: OK, schema: {type: array, items: {$ref: "#/definitions/Invoice"}}}
+  /pets:
+    get:
+      responses:
+        200: {description: Billable pets.}
+  /health:
+    get:
+      responses:
+        200: {description: OK}
+definitions:
+  Invoice:
+    type: object
+    properties:
+      pet: {$ref: "#/definitions/Pet"}
+      error: {$ref: "#/definitions/Error"}
+  Pet:
+    type: object
+    properties:
+      id: {type: string}
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+`
+
+func TestMerge(t *testing.T) {
+	shelter, err := LoadYaml([]byte(mergeShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	billing, err := LoadYaml([]byte(mergeBilling))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, conflicts, err := Merge(MergeOptions{}, shelter, billing)
+	want := []string{
+		"basePath: defined differently by PetShelter and Billing",
+		"definitions.Pet: defined differently by PetShelter and Billing",
+		"parameters.limit: defined differently by PetShelter and Billing",
+		"securityDefinitions.key: defined differently by PetShelter and Billing",
+		"paths./pets.get: defined differently by PetShelter and Billing",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") || len(conflicts) != len(want) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	merged, conflicts, err := Merge(MergeOptions{Strategy: ConflictPrefix, Names: []string{"shelter", "billing"}, PrefixBasePath: true, Info: Info{Title: "Gateway", Version: "1"}}, shelter, billing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		text[i] = c.String()
+	}
+	want = []string{
+		"definitions.Pet: defined differently by shelter and billing; renamed to billingPet",
+		"parameters.limit: defined differently by shelter and billing; renamed to billinglimit",
+		"securityDefinitions.key: defined differently by shelter and billing; renamed to billingkey",
+	}
+	if strings.Join(text, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected conflicts:\n%s", strings.Join(text, "\n"))
+	}
+	if merged.Info.Title != "Gateway" || merged.BasePath != "" || len(merged.Paths) != 5 {
+		t.Errorf("unexpected document %+v", merged)
+	}
+	invoice := merged.Definitions["Invoice"]
+	if invoice.Properties["pet"].Ref != "#/definitions/billingPet" || invoice.Properties["error"].Ref != "#/definitions/Error" {
+		t.Errorf("unexpected references %+v", invoice.Properties)
+	}
+	invoices := merged.Paths["/billing/invoices"].Get
+	if invoices.Parameters[0].Ref != "#/parameters/billinglimit" || invoices.Security[0]["billingkey"] == nil ||
+		len(invoices.Produces) != 1 || invoices.Produces[0] != "application/xml" {
+		t.Errorf("unexpected operation %+v", invoices)
+	}
+	if pets := merged.Paths["/shelter/pets"].Get; pets.Security != nil || pets.Produces != nil {
+		t.Errorf("unexpected operation %+v", pets)
+	}
+	if len(merged.Tags) != 2 || merged.Tags[0].Name != "pets" || merged.Tags[1].Name != "invoices" {
+		t.Errorf("unexpected tags %+v", merged.Tags)
+	}
+	if shelter.Paths["/pets"].Get.Security != nil || billing.Definitions["Invoice"].Properties["pet"].Ref != "#/definitions/Pet" {
+		t.Error("the documents were modified")
+	}
+
+	merged, conflicts, err = Merge(MergeOptions{Strategy: ConflictKeepFirst}, shelter, billing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 5 || conflicts[4].Resolution != "kept the first operation" || merged.Paths["/pets"].Get.Responses["200"].Description != "OK" ||
+		merged.Paths["/invoices"].Get.Parameters[0].Ref != "#/parameters/limit" || merged.Definitions["Pet"].Properties["name"].Type != "string" {
+		t.Errorf("unexpected merge %v", conflicts)
+	}
+}
+
+func TestMergeDependentRenames(t *testing.T) {
+	a, err := LoadYaml([]byte(`
+swagger: "2.0"
+info: {title: A, version: "1.0"}
+paths:
+  /a:
+    get:
+      responses:
+        200: {description: OK, schema: {$ref: "#/definitions/Owner"}}
+definitions:
+  Owner:
+    type: object
+    properties:
+      pet: {$ref: "#/definitions/Pet"}
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadYaml([]byte(`
+swagger: "2.0"
+info: {title: B, version: "1.0"}
+paths:
+  /b:
+    get:
+      responses:
+        200: {description: OK, schema: {$ref: "#/definitions/Shop"}}
+definitions:
+  Shop:
+    type: object
+    properties:
+      owner: {$ref: "#/definitions/Owner"}
+  Owner:
+    type: object
+    properties:
+      pet: {$ref: "#/definitions/Pet"}
+  Pet:
+    type: object
+    properties:
+      id: {type: integer}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, conflicts, err := Merge(MergeOptions{Strategy: ConflictPrefix}, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		text[i] = c.String()
+	}
+	want := []string{
+		"definitions.Pet: defined differently by A and B; renamed to BPet",
+		"definitions.Owner: defined differently by A and B; renamed to BOwner",
+	}
+	if strings.Join(text, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected conflicts:\n%s", strings.Join(text, "\n"))
+	}
+	if merged.Definitions["Owner"].Properties["pet"].Ref != "#/definitions/Pet" || merged.Definitions["BOwner"].Properties["pet"].Ref != "#/definitions/BPet" ||
+		merged.Definitions["Shop"].Properties["owner"].Ref != "#/definitions/BOwner" || merged.Paths["/a"].Get.Responses["200"].Schema.Ref != "#/definitions/Owner" {
+		t.Errorf("unexpected definitions %+v", merged.Definitions)
+	}
+}
@@ -0,0 +1,464 @@
+package openapi3
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// Version is the OpenAPI version written by FromSwagger2.
+const Version = "3.0.3"
+
+// FromSwagger2 converts a Swagger 2 document into an OpenAPI 3.0 document. The returned errors describe
+// anything that could not be represented exactly; the document is still usable when they are present.
+func FromSwagger2(s *swagger2.Swagger) (*OpenAPI, []error) {
+	c := &up{src: s, errs: make([]error, 0)}
+	o := &OpenAPI{
+		OpenAPI:      Version,
+		Info:         s.Info,
+		Servers:      c.servers(s.Schemes),
+		Paths:        make(Paths),
+		Security:     s.Security,
+		Tags:         s.Tags,
+		ExternalDocs: s.ExternalDocs,
+	}
+	comp := &Components{}
+	if len(s.Definitions) > 0 {
+		comp.Schemas = make(map[string]Schema)
+		for n, d := range s.Definitions {
+			comp.Schemas[n] = *c.schema(&d)
+		}
+	}
+	for n, p := range s.Parameters {
+		if p.In == "body" || p.In == "formData" {
+			// these are folded into the request body of each operation that uses them
+			continue
+		}
+		if comp.Parameters == nil {
+			comp.Parameters = make(map[string]Parameter)
+		}
+		comp.Parameters[n] = c.parameter("parameters."+n, &p)
+	}
+	for n, r := range s.Responses {
+		if comp.Responses == nil {
+			comp.Responses = make(map[string]Response)
+		}
+		comp.Responses[n] = c.response("responses."+n, &r, s.Produces)
+	}
+	if len(s.SecurityDefinitions) > 0 {
+		comp.SecuritySchemes = make(map[string]SecurityScheme)
+		for n, d := range s.SecurityDefinitions {
+			comp.SecuritySchemes[n] = c.securityScheme(n, &d)
+		}
+	}
+	if comp.Schemas != nil || comp.Parameters != nil || comp.Responses != nil || comp.SecuritySchemes != nil {
+		o.Components = comp
+	}
+	for path, item := range s.Paths {
+		o.Paths[path] = c.pathItem(path, &item)
+	}
+	return o, c.errs
+}
+
+// up holds the state of a Swagger 2 to OpenAPI 3 conversion
+type up struct {
+	src  *swagger2.Swagger
+	errs []error
+}
+
+// lossy records something that could not be converted exactly
+func (c *up) lossy(where, format string, args ...interface{}) {
+	c.errs = append(c.errs, fmt.Errorf(where+": "+format, args...))
+}
+
+// servers builds the server list from the host, base path and schemes
+func (c *up) servers(schemes []string) []Server {
+	if c.src.Host == "" && c.src.BasePath == "" && len(schemes) == 0 {
+		return nil
+	}
+	base := c.src.BasePath
+	if base == "" {
+		base = "/"
+	}
+	if c.src.Host == "" {
+		// relative to wherever the document was served from
+		return []Server{{Url: base}}
+	}
+	if len(schemes) == 0 {
+		return []Server{{Url: "//" + c.src.Host + strings.TrimSuffix(base, "/")}}
+	}
+	servers := make([]Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, Server{Url: scheme + "://" + c.src.Host + strings.TrimSuffix(base, "/")})
+	}
+	return servers
+}
+
+// ref rewrites a Swagger 2 local reference into its OpenAPI 3 location
+func (c *up) ref(ref string) string {
+	section, name := swagger2.ParseRef(ref)
+	switch section {
+	case "definitions":
+		return swagger2.MakeRef("components", "schemas", name)
+	case "parameters", "responses":
+		return swagger2.MakeRef("components", section, name)
+	}
+	return ref
+}
+
+// pathItem converts a path item and its operations
+func (c *up) pathItem(path string, item *swagger2.PathItem) PathItem {
+	p := PathItem{Ref: item.Ref}
+	for i := range item.Parameters {
+		if in := c.src.ResolveParameter(item.Parameters[i]).In; in == "body" || in == "formData" {
+			continue
+		}
+		p.Parameters = append(p.Parameters, c.parameterOrRef(path, &item.Parameters[i]))
+	}
+	for _, method := range swagger2.Methods {
+		op := item.Operation(method)
+		if op != nil {
+			p.SetOperation(method, c.operation(strings.ToUpper(method)+" "+path, path, item, op))
+		}
+	}
+	return p
+}
+
+// operation converts a single operation, folding body and form parameters into a request body
+func (c *up) operation(where, path string, item *swagger2.PathItem, op *swagger2.Operation) *Operation {
+	o := &Operation{
+		Tags:         op.Tags,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		ExternalDocs: op.ExternalDocs,
+		OperationId:  op.OperationId,
+		Responses:    make(Responses),
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+	}
+	if len(op.Schemes) > 0 && !sameStrings(op.Schemes, c.src.Schemes) {
+		o.Servers = c.servers(op.Schemes)
+	}
+	consumes := c.src.Consumes
+	if op.Consumes != nil {
+		consumes = op.Consumes
+	}
+	produces := c.src.Produces
+	if op.Produces != nil {
+		produces = op.Produces
+	}
+
+	// body and form parameters make up the request body, and the others are those of the operation
+	var body *swagger2.Parameter
+	form := make([]*swagger2.Parameter, 0)
+	params := c.src.OperationParameters(path, item, op)
+	for i := range params {
+		switch params[i].In {
+		case "body":
+			body = &params[i]
+		case "formData":
+			form = append(form, &params[i])
+		}
+	}
+	for i := range op.Parameters {
+		if in := c.src.ResolveParameter(op.Parameters[i]).In; in != "body" && in != "formData" {
+			o.Parameters = append(o.Parameters, c.parameterOrRef(where, &op.Parameters[i]))
+		}
+	}
+	if body != nil && len(form) > 0 {
+		c.lossy(where, "body and formData parameters cannot be used together; formData parameters were dropped")
+		form = form[:0]
+	}
+	if body != nil {
+		o.RequestBody = c.bodyRequest(where, body, consumes)
+	} else if len(form) > 0 {
+		o.RequestBody = c.formRequest(where, form, consumes)
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		r := op.Responses[code]
+		o.Responses[code] = c.response(where+" "+code, &r, produces)
+	}
+	return o
+}
+
+// parameterOrRef converts a non-body parameter, keeping references to shared parameters
+func (c *up) parameterOrRef(where string, p *swagger2.Parameter) Parameter {
+	if p.Ref != "" {
+		return Parameter{Ref: c.ref(p.Ref)}
+	}
+	return c.parameter(where, p)
+}
+
+// parameter converts a query, header or path parameter
+func (c *up) parameter(where string, p *swagger2.Parameter) Parameter {
+	param := Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required != nil && *p.Required,
+		Schema:      c.items(&p.ItemsDef),
+	}
+	if p.Type == "array" {
+		param.Style, param.Explode = c.style(where+" parameter "+p.Name, p.In, p.CollectionFormat)
+	}
+	return param
+}
+
+// style maps a collection format onto the equivalent style and explode values
+func (c *up) style(where, in, format string) (string, *bool) {
+	if format == "" {
+		format = "csv"
+	}
+	switch in {
+	case "query", "formData":
+		switch format {
+		case "csv":
+			return "form", boolPtr(false)
+		case "multi":
+			return "form", boolPtr(true)
+		case "ssv":
+			return "spaceDelimited", boolPtr(false)
+		case "pipes":
+			return "pipeDelimited", boolPtr(false)
+		}
+	case "path", "header":
+		if format == "csv" {
+			return "simple", nil
+		}
+	}
+	c.lossy(where, "collectionFormat %s has no equivalent style in %s; using the default", format, in)
+	return "", nil
+}
+
+// bodyRequest converts a body parameter into a request body with one entry per consumed media type
+func (c *up) bodyRequest(where string, p *swagger2.Parameter, consumes []string) *RequestBody {
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+	r := &RequestBody{
+		Description: p.Description,
+		Content:     make(map[string]MediaType),
+		Required:    p.Required != nil && *p.Required,
+	}
+	var schema *Schema
+	if p.Schema != nil {
+		schema = c.schema(p.Schema)
+	} else {
+		c.lossy(where, "body parameter %s has no schema", p.Name)
+	}
+	for _, mt := range consumes {
+		r.Content[mt] = MediaType{Schema: schema}
+	}
+	return r
+}
+
+// formRequest converts formData parameters into an object schema for each form media type
+func (c *up) formRequest(where string, params []*swagger2.Parameter, consumes []string) *RequestBody {
+	obj := &Schema{Type: "object", Properties: make(map[string]Schema)}
+	encoding := make(map[string]Encoding)
+	required := false
+	hasFile := false
+	for _, p := range params {
+		prop := c.items(&p.ItemsDef)
+		prop.Description = p.Description
+		obj.Properties[p.Name] = *prop
+		if p.Required != nil && *p.Required {
+			obj.Required = append(obj.Required, p.Name)
+			required = true
+		}
+		if p.Type == "file" {
+			hasFile = true
+		}
+		if p.Type == "array" {
+			style, explode := c.style(where+" parameter "+p.Name, p.In, p.CollectionFormat)
+			if style != "" {
+				encoding[p.Name] = Encoding{Style: style, Explode: explode}
+			}
+		}
+	}
+	types := make([]string, 0)
+	for _, mt := range consumes {
+		if mt == "multipart/form-data" || mt == "application/x-www-form-urlencoded" {
+			types = append(types, mt)
+		}
+	}
+	if len(types) == 0 {
+		if hasFile {
+			types = append(types, "multipart/form-data")
+		} else {
+			types = append(types, "application/x-www-form-urlencoded")
+		}
+		if len(consumes) > 0 {
+			c.lossy(where, "formData parameters used without a form media type; assuming %s", types[0])
+		}
+	}
+	r := &RequestBody{Content: make(map[string]MediaType), Required: required}
+	for _, mt := range types {
+		m := MediaType{Schema: obj}
+		if mt == "application/x-www-form-urlencoded" && len(encoding) > 0 {
+			m.Encoding = encoding
+		}
+		r.Content[mt] = m
+	}
+	return r
+}
+
+// response converts a response, using the produced media types for its content
+func (c *up) response(where string, r *swagger2.Response, produces []string) Response {
+	resp := Response{Description: r.Description}
+	if len(r.Headers) > 0 {
+		resp.Headers = make(map[string]Header)
+		for n, h := range r.Headers {
+			hdr := Header{Description: h.Description, Schema: c.items(&h.ItemsDef)}
+			if h.Type == "array" {
+				hdr.Style, hdr.Explode = c.style(where+" header "+n, "header", h.CollectionFormat)
+			}
+			resp.Headers[n] = hdr
+		}
+	}
+	if r.Schema == nil && len(r.Examples) == 0 {
+		return resp
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	var schema *Schema
+	if r.Schema != nil {
+		schema = c.schema(r.Schema)
+	}
+	resp.Content = make(map[string]MediaType)
+	for _, mt := range produces {
+		resp.Content[mt] = MediaType{Schema: schema}
+	}
+	for mt, ex := range r.Examples {
+		m, ok := resp.Content[mt]
+		if !ok {
+			c.lossy(where, "example for %s does not match a produced media type; adding it as content", mt)
+		}
+		m.Schema = schema
+		m.Example = ex
+		resp.Content[mt] = m
+	}
+	return resp
+}
+
+// securityScheme converts a security definition
+func (c *up) securityScheme(name string, d *swagger2.SecurityDefinition) SecurityScheme {
+	s := SecurityScheme{Type: d.Type, Description: d.Description}
+	switch d.Type {
+	case "basic":
+		s.Type = "http"
+		s.Scheme = "basic"
+	case "apiKey":
+		s.Name = d.Name
+		s.In = d.In
+	case "oauth2":
+		scopes := d.Scopes
+		if scopes == nil {
+			scopes = swagger2.Scopes{}
+		}
+		s.Flows = &OAuthFlows{}
+		switch d.Flow {
+		case "implicit":
+			s.Flows.Implicit = &OAuthFlow{AuthorizationUrl: d.AuthorizationUrl, Scopes: scopes}
+		case "password":
+			s.Flows.Password = &OAuthFlow{TokenUrl: d.TokenUrl, Scopes: scopes}
+		case "application":
+			s.Flows.ClientCredentials = &OAuthFlow{TokenUrl: d.TokenUrl, Scopes: scopes}
+		case "accessCode":
+			s.Flows.AuthorizationCode = &OAuthFlow{AuthorizationUrl: d.AuthorizationUrl, TokenUrl: d.TokenUrl, Scopes: scopes}
+		default:
+			c.lossy("securityDefinitions."+name, "unknown oauth2 flow %q", d.Flow)
+		}
+	default:
+		c.lossy("securityDefinitions."+name, "unknown security type %q", d.Type)
+	}
+	return s
+}
+
+// items converts the simple type used by non-body parameters, headers and array items into a schema
+func (c *up) items(d *swagger2.ItemsDef) *Schema {
+	s := &Schema{
+		Ref:              c.ref(d.Ref),
+		Type:             d.Type,
+		Format:           d.Format,
+		Default:          d.Default,
+		Maximum:          d.Maximum,
+		ExclusiveMaximum: d.ExclusiveMaximum,
+		Minimum:          d.Minimum,
+		ExclusiveMinimum: d.ExclusiveMinimum,
+		MaxLength:        d.MaxLength,
+		MinLength:        d.MinLength,
+		Pattern:          d.Pattern,
+		MaxItems:         d.MaxItems,
+		MinItems:         d.MinItems,
+		UniqueItems:      d.UniqueItems,
+		Enum:             d.Enum,
+		MultipleOf:       d.MultipleOf,
+	}
+	if s.Type == "file" {
+		s.Type = "string"
+		s.Format = "binary"
+	}
+	if d.Items != nil {
+		s.Items = c.items(d.Items)
+	}
+	if d.AdditionalProperties != nil {
+		s.AdditionalProperties = &AdditionalProperties{Allowed: true, Schema: c.items(d.AdditionalProperties)}
+	}
+	return s
+}
+
+// schema converts a JSON schema, rewriting references to definitions
+func (c *up) schema(d *swagger2.Schema) *Schema {
+	s := c.items(&d.ItemsDef)
+	s.Title = d.Title
+	s.Description = d.Description
+	s.MaxProperties = d.MaxProperties
+	s.MinProperties = d.MinProperties
+	s.Required = d.Required
+	s.ReadOnly = d.ReadOnly != nil && *d.ReadOnly
+	s.Nullable = d.Extensions.Bool("x-nullable")
+	s.Xml = d.Xml
+	s.ExternalDocs = d.ExternalDocs
+	s.Example = d.Example
+	if d.Discriminator != "" {
+		s.Discriminator = &Discriminator{PropertyName: d.Discriminator}
+	}
+	for i := range d.AllOf {
+		s.AllOf = append(s.AllOf, *c.schema(&d.AllOf[i]))
+	}
+	if len(d.Properties) > 0 {
+		s.Properties = make(map[string]Schema)
+		for n, p := range d.Properties {
+			s.Properties[n] = *c.schema(&p)
+		}
+	}
+	return s
+}
+
+// sameStrings returns true if both slices hold the same values in the same order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// boolPtr returns a pointer to the given value
+func boolPtr(b bool) *bool {
+	return &b
+}
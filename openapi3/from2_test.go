@@ -0,0 +1,175 @@
+package openapi3
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+// loadExamples loads every Swagger 2 example shipped with the parent package
+func loadExamples(t *testing.T) map[string]*swagger2.Swagger {
+	docs := make(map[string]*swagger2.Swagger)
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			docs[file] = swag
+		}
+	}
+	return docs
+}
+
+var refPattern = regexp.MustCompile(`"\$ref": "#/components/(\w+)/([^"]+)"`)
+
+func TestFromSwagger2Examples(t *testing.T) {
+	for file, swag := range loadExamples(t) {
+		doc, _ := FromSwagger2(swag)
+		buf, err := doc.Json()
+		if err != nil {
+			t.Errorf("%s: unable to serialize: %s", file, err)
+			continue
+		}
+		if _, err := LoadJson(buf); err != nil {
+			t.Errorf("%s: unable to reload: %s", file, err)
+		}
+		if strings.Contains(string(buf), "#/definitions/") {
+			t.Errorf("%s: references to definitions were not rewritten", file)
+		}
+		for _, m := range refPattern.FindAllStringSubmatch(string(buf), -1) {
+			found := false
+			if doc.Components != nil {
+				switch m[1] {
+				case "schemas":
+					_, found = doc.Components.Schemas[m[2]]
+				case "parameters":
+					_, found = doc.Components.Parameters[m[2]]
+				}
+			}
+			if !found {
+				t.Errorf("%s: dangling reference to %s/%s", file, m[1], m[2])
+			}
+		}
+	}
+}
+
+const convertSource = `{
+  "swagger": "2.0",
+  "info": {"title": "Test", "version": "1"},
+  "host": "api.example.com",
+  "basePath": "/v1",
+  "schemes": ["https", "http"],
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "post": {
+        "consumes": ["multipart/form-data"],
+        "parameters": [
+          {"name": "photo", "in": "formData", "type": "file", "required": true},
+          {"name": "tags", "in": "formData", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"name": "ids", "in": "query", "type": "array", "items": {"type": "integer"}, "collectionFormat": "tsv"}
+        ],
+        "responses": {"204": {"description": "created"}}
+      },
+      "put": {
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "string"},
+        {"name": "raw", "in": "body", "schema": {"type": "string"}}
+      ],
+      "put": {
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "discriminator": "kind", "required": ["kind"], "properties": {"kind": {"type": "string"}}}
+  },
+  "securityDefinitions": {
+    "basic": {"type": "basic"},
+    "key": {"type": "apiKey", "name": "X-Key", "in": "header"},
+    "code": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://auth/a", "tokenUrl": "https://auth/t", "scopes": {"read": "Read"}}
+  }
+}`
+
+func TestFromSwagger2(t *testing.T) {
+	swag, err := swagger2.LoadJson([]byte(convertSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, lossy := FromSwagger2(swag)
+
+	if len(doc.Servers) != 2 || doc.Servers[0].Url != "https://api.example.com/v1" {
+		t.Errorf("unexpected servers: %+v", doc.Servers)
+	}
+
+	post := doc.Paths["/pets"].Post
+	form, ok := post.RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatalf("form parameters were not converted to a multipart body: %+v", post.RequestBody)
+	}
+	if photo := form.Schema.Properties["photo"]; photo.Type != "string" || photo.Format != "binary" {
+		t.Errorf("file parameter should be a binary string, got %+v", photo)
+	}
+	if len(form.Schema.Required) != 1 || form.Schema.Required[0] != "photo" || !post.RequestBody.Required {
+		t.Errorf("required form fields not carried over: %+v", form.Schema.Required)
+	}
+	if len(post.Parameters) != 1 || post.Parameters[0].Name != "ids" {
+		t.Errorf("unexpected parameters: %+v", post.Parameters)
+	}
+	if len(lossy) != 1 || !strings.Contains(lossy[0].Error(), "tsv") {
+		t.Errorf("expected the tsv collection format to be reported, got %v", lossy)
+	}
+
+	put := doc.Paths["/pets"].Put
+	body, ok := put.RequestBody.Content["application/json"]
+	if !ok || body.Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("body parameter was not converted: %+v", put.RequestBody)
+	}
+	update := doc.Paths["/pets/{id}"].Put
+	if body, ok := update.RequestBody.Content["application/json"]; !ok || body.Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("the body parameter of the path replaced that of the operation: %+v", update.RequestBody)
+	}
+	if put.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("response schema was not converted: %+v", put.Responses["200"])
+	}
+	if d := doc.Components.Schemas["Pet"].Discriminator; d == nil || d.PropertyName != "kind" {
+		t.Errorf("discriminator was not converted: %+v", d)
+	}
+
+	schemes := doc.Components.SecuritySchemes
+	if schemes["basic"].Type != "http" || schemes["basic"].Scheme != "basic" {
+		t.Errorf("basic auth was not converted: %+v", schemes["basic"])
+	}
+	if schemes["key"].Type != "apiKey" || schemes["key"].Name != "X-Key" || schemes["key"].In != "header" {
+		t.Errorf("api key was not converted: %+v", schemes["key"])
+	}
+	code := schemes["code"].Flows.AuthorizationCode
+	if code == nil || code.TokenUrl != "https://auth/t" || code.Scopes["read"] != "Read" {
+		t.Errorf("oauth2 flow was not converted: %+v", schemes["code"].Flows)
+	}
+}
@@ -0,0 +1,45 @@
+package openapi3
+
+import (
+	"encoding/json"
+)
+
+// LoadJson parses the incoming byte array as OpenAPI 3 JSON data
+func LoadJson(in []byte) (*OpenAPI, error) {
+	var o OpenAPI
+	err := json.Unmarshal(in, &o)
+	if err != nil {
+		return nil, err
+	}
+	return &o, err
+}
+
+// Json serializes the OpenAPI 3 document into JSON format
+func (o *OpenAPI) Json() ([]byte, error) {
+	return json.MarshalIndent(o, "", "  ")
+}
+
+// MarshalJSON writes additionalProperties as either a boolean or a schema
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+// UnmarshalJSON reads additionalProperties from either a boolean or a schema
+func (a *AdditionalProperties) UnmarshalJSON(in []byte) error {
+	var b bool
+	if err := json.Unmarshal(in, &b); err == nil {
+		a.Allowed = b
+		a.Schema = nil
+		return nil
+	}
+	var s Schema
+	if err := json.Unmarshal(in, &s); err != nil {
+		return err
+	}
+	a.Allowed = true
+	a.Schema = &s
+	return nil
+}
@@ -0,0 +1,49 @@
+package openapi3
+
+// Methods lists the HTTP methods that a PathItem can describe, in the order they are declared.
+var Methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operation returns the operation for the given lower-case HTTP method, or nil if there isn't one.
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "post":
+		return p.Post
+	case "delete":
+		return p.Delete
+	case "options":
+		return p.Options
+	case "head":
+		return p.Head
+	case "patch":
+		return p.Patch
+	case "trace":
+		return p.Trace
+	}
+	return nil
+}
+
+// SetOperation stores the operation for the given lower-case HTTP method. Unknown methods are ignored.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch method {
+	case "get":
+		p.Get = op
+	case "put":
+		p.Put = op
+	case "post":
+		p.Post = op
+	case "delete":
+		p.Delete = op
+	case "options":
+		p.Options = op
+	case "head":
+		p.Head = op
+	case "patch":
+		p.Patch = op
+	case "trace":
+		p.Trace = op
+	}
+}
@@ -0,0 +1,252 @@
+// Package openapi3 models OpenAPI 3.0 documents and converts them to and from Swagger 2.
+package openapi3
+
+import (
+	"github.com/babelrpc/swagger2"
+)
+
+// OpenAPI is the root document object of an OpenAPI 3.0 description.
+type OpenAPI struct {
+	OpenAPI      string                  `yaml:"openapi" json:"openapi"`                               // Required. The semantic version number of the OpenAPI Specification version that the document uses.
+	Info         swagger2.Info           `yaml:"info" json:"info"`                                     // Required. Provides metadata about the API.
+	Servers      []Server                `yaml:"servers,omitempty" json:"servers,omitempty"`           // An array of Server Objects, which provide connectivity information to a target server.
+	Paths        Paths                   `yaml:"paths" json:"paths"`                                   // Required. The available paths and operations for the API.
+	Components   *Components             `yaml:"components,omitempty" json:"components,omitempty"`     // An element to hold various schemas for the specification.
+	Security     []swagger2.Security     `yaml:"security,omitempty" json:"security,omitempty"`         // A declaration of which security mechanisms can be used across the API.
+	Tags         []swagger2.Tag          `yaml:"tags,omitempty" json:"tags,omitempty"`                 // A list of tags used by the specification with additional metadata.
+	ExternalDocs *swagger2.Documentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"` // Additional external documentation.
+}
+
+// Server represents a server hosting the API.
+type Server struct {
+	Url         string                    `yaml:"url" json:"url"`                                     // Required. A URL to the target host. This URL supports Server Variables and MAY be relative.
+	Description string                    `yaml:"description,omitempty" json:"description,omitempty"` // An optional string describing the host designated by the URL.
+	Variables   map[string]ServerVariable `yaml:"variables,omitempty" json:"variables,omitempty"`     // A map between a variable name and its value. The value is used for substitution in the server's URL template.
+}
+
+// ServerVariable represents a variable for server URL template substitution.
+type ServerVariable struct {
+	Enum        []string `yaml:"enum,omitempty" json:"enum,omitempty"`               // An enumeration of string values to be used if the substitution options are from a limited set.
+	Default     string   `yaml:"default" json:"default"`                             // Required. The default value to use for substitution.
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"` // An optional description for the server variable.
+}
+
+// Components holds a set of reusable objects for different aspects of the specification.
+type Components struct {
+	Schemas         map[string]Schema         `yaml:"schemas,omitempty" json:"schemas,omitempty"`                 // An object to hold reusable Schema Objects.
+	Responses       map[string]Response       `yaml:"responses,omitempty" json:"responses,omitempty"`             // An object to hold reusable Response Objects.
+	Parameters      map[string]Parameter      `yaml:"parameters,omitempty" json:"parameters,omitempty"`           // An object to hold reusable Parameter Objects.
+	Examples        map[string]Example        `yaml:"examples,omitempty" json:"examples,omitempty"`               // An object to hold reusable Example Objects.
+	RequestBodies   map[string]RequestBody    `yaml:"requestBodies,omitempty" json:"requestBodies,omitempty"`     // An object to hold reusable Request Body Objects.
+	Headers         map[string]Header         `yaml:"headers,omitempty" json:"headers,omitempty"`                 // An object to hold reusable Header Objects.
+	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes,omitempty" json:"securitySchemes,omitempty"` // An object to hold reusable Security Scheme Objects.
+	Links           map[string]Link           `yaml:"links,omitempty" json:"links,omitempty"`                     // An object to hold reusable Link Objects.
+	Callbacks       map[string]Callback       `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`             // An object to hold reusable Callback Objects.
+}
+
+// Paths holds the relative paths to the individual endpoints and their operations. The path is appended to the URL from the Server Object in order to construct the full URL.
+type Paths map[string]PathItem
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Ref         string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`               // Allows for an external definition of this path item.
+	Summary     string      `yaml:"summary,omitempty" json:"summary,omitempty"`         // An optional, string summary, intended to apply to all operations in this path.
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"` // An optional, string description, intended to apply to all operations in this path.
+	Get         *Operation  `yaml:"get,omitempty" json:"get,omitempty"`                 // A definition of a GET operation on this path.
+	Put         *Operation  `yaml:"put,omitempty" json:"put,omitempty"`                 // A definition of a PUT operation on this path.
+	Post        *Operation  `yaml:"post,omitempty" json:"post,omitempty"`               // A definition of a POST operation on this path.
+	Delete      *Operation  `yaml:"delete,omitempty" json:"delete,omitempty"`           // A definition of a DELETE operation on this path.
+	Options     *Operation  `yaml:"options,omitempty" json:"options,omitempty"`         // A definition of a OPTIONS operation on this path.
+	Head        *Operation  `yaml:"head,omitempty" json:"head,omitempty"`               // A definition of a HEAD operation on this path.
+	Patch       *Operation  `yaml:"patch,omitempty" json:"patch,omitempty"`             // A definition of a PATCH operation on this path.
+	Trace       *Operation  `yaml:"trace,omitempty" json:"trace,omitempty"`             // A definition of a TRACE operation on this path.
+	Servers     []Server    `yaml:"servers,omitempty" json:"servers,omitempty"`         // An alternative server array to service all operations in this path.
+	Parameters  []Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`   // A list of parameters that are applicable for all the operations described under this path.
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Tags         []string                `yaml:"tags,omitempty" json:"tags,omitempty"`                 // A list of tags for API documentation control.
+	Summary      string                  `yaml:"summary,omitempty" json:"summary,omitempty"`           // A short summary of what the operation does.
+	Description  string                  `yaml:"description,omitempty" json:"description,omitempty"`   // A verbose explanation of the operation behavior. CommonMark syntax MAY be used for rich text representation.
+	ExternalDocs *swagger2.Documentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"` // Additional external documentation for this operation.
+	OperationId  string                  `yaml:"operationId,omitempty" json:"operationId,omitempty"`   // Unique string used to identify the operation.
+	Parameters   []Parameter             `yaml:"parameters,omitempty" json:"parameters,omitempty"`     // A list of parameters that are applicable for this operation.
+	RequestBody  *RequestBody            `yaml:"requestBody,omitempty" json:"requestBody,omitempty"`   // The request body applicable for this operation.
+	Responses    Responses               `yaml:"responses" json:"responses"`                           // Required. The list of possible responses as they are returned from executing this operation.
+	Callbacks    map[string]Callback     `yaml:"callbacks,omitempty" json:"callbacks,omitempty"`       // A map of possible out-of band callbacks related to the parent operation.
+	Deprecated   bool                    `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`     // Declares this operation to be deprecated.
+	Security     []swagger2.Security     `yaml:"security,omitempty" json:"security,omitempty"`         // A declaration of which security mechanisms can be used for this operation.
+	Servers      []Server                `yaml:"servers,omitempty" json:"servers,omitempty"`           // An alternative server array to service this operation.
+}
+
+// Parameter describes a single operation parameter. A unique parameter is defined by a combination of a name and location.
+type Parameter struct {
+	Ref             string               `yaml:"$ref,omitempty" json:"$ref,omitempty"`                       // A reference to a parameter in the components.
+	Name            string               `yaml:"name,omitempty" json:"name,omitempty"`                       // Required. The name of the parameter. Parameter names are case sensitive.
+	In              string               `yaml:"in,omitempty" json:"in,omitempty"`                           // Required. The location of the parameter. Possible values are "query", "header", "path" or "cookie".
+	Description     string               `yaml:"description,omitempty" json:"description,omitempty"`         // A brief description of the parameter.
+	Required        bool                 `yaml:"required,omitempty" json:"required,omitempty"`               // Determines whether this parameter is mandatory. If the parameter location is "path", this property is required and its value MUST be true.
+	Deprecated      bool                 `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`           // Specifies that a parameter is deprecated and SHOULD be transitioned out of usage.
+	AllowEmptyValue bool                 `yaml:"allowEmptyValue,omitempty" json:"allowEmptyValue,omitempty"` // Sets the ability to pass empty-valued parameters.
+	Style           string               `yaml:"style,omitempty" json:"style,omitempty"`                     // Describes how the parameter value will be serialized depending on the type of the parameter value.
+	Explode         *bool                `yaml:"explode,omitempty" json:"explode,omitempty"`                 // When this is true, parameter values of type array or object generate separate parameters for each value.
+	AllowReserved   bool                 `yaml:"allowReserved,omitempty" json:"allowReserved,omitempty"`     // Determines whether the parameter value SHOULD allow reserved characters to be included without percent-encoding.
+	Schema          *Schema              `yaml:"schema,omitempty" json:"schema,omitempty"`                   // The schema defining the type used for the parameter.
+	Example         interface{}          `yaml:"example,omitempty" json:"example,omitempty"`                 // Example of the parameter's potential value.
+	Examples        map[string]Example   `yaml:"examples,omitempty" json:"examples,omitempty"`               // Examples of the parameter's potential value.
+	Content         map[string]MediaType `yaml:"content,omitempty" json:"content,omitempty"`                 // A map containing the representations for the parameter.
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Ref         string               `yaml:"$ref,omitempty" json:"$ref,omitempty"`               // A reference to a request body in the components.
+	Description string               `yaml:"description,omitempty" json:"description,omitempty"` // A brief description of the request body.
+	Content     map[string]MediaType `yaml:"content,omitempty" json:"content,omitempty"`         // Required. The content of the request body, keyed by media type.
+	Required    bool                 `yaml:"required,omitempty" json:"required,omitempty"`       // Determines if the request body is required in the request.
+}
+
+// MediaType provides schema and examples for the media type identified by its key.
+type MediaType struct {
+	Schema   *Schema             `yaml:"schema,omitempty" json:"schema,omitempty"`     // The schema defining the content of the request, response, or parameter.
+	Example  interface{}         `yaml:"example,omitempty" json:"example,omitempty"`   // Example of the media type.
+	Examples map[string]Example  `yaml:"examples,omitempty" json:"examples,omitempty"` // Examples of the media type.
+	Encoding map[string]Encoding `yaml:"encoding,omitempty" json:"encoding,omitempty"` // A map between a property name and its encoding information. Only applies to multipart and application/x-www-form-urlencoded request bodies.
+}
+
+// Encoding is a single encoding definition applied to a single schema property.
+type Encoding struct {
+	ContentType   string            `yaml:"contentType,omitempty" json:"contentType,omitempty"`     // The Content-Type for encoding a specific property.
+	Headers       map[string]Header `yaml:"headers,omitempty" json:"headers,omitempty"`             // A map allowing additional information to be provided as headers.
+	Style         string            `yaml:"style,omitempty" json:"style,omitempty"`                 // Describes how a specific property value will be serialized depending on its type.
+	Explode       *bool             `yaml:"explode,omitempty" json:"explode,omitempty"`             // When this is true, property values of type array or object generate separate parameters for each value.
+	AllowReserved bool              `yaml:"allowReserved,omitempty" json:"allowReserved,omitempty"` // Determines whether the parameter value SHOULD allow reserved characters.
+}
+
+// Responses is a container for the expected responses of an operation, keyed by HTTP status code or "default".
+type Responses map[string]Response
+
+// Response describes a single response from an API Operation.
+type Response struct {
+	Ref         string               `yaml:"$ref,omitempty" json:"$ref,omitempty"`               // A reference to a response in the components.
+	Description string               `yaml:"description,omitempty" json:"description,omitempty"` // Required. A short description of the response.
+	Headers     map[string]Header    `yaml:"headers,omitempty" json:"headers,omitempty"`         // Maps a header name to its definition.
+	Content     map[string]MediaType `yaml:"content,omitempty" json:"content,omitempty"`         // A map containing descriptions of potential response payloads, keyed by media type.
+	Links       map[string]Link      `yaml:"links,omitempty" json:"links,omitempty"`             // A map of operations links that can be followed from the response.
+}
+
+// Header follows the structure of the Parameter Object, without a name or location.
+type Header struct {
+	Ref         string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`               // A reference to a header in the components.
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"` // A brief description of the header.
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`       // Determines whether this header is mandatory.
+	Deprecated  bool        `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`   // Specifies that a header is deprecated.
+	Style       string      `yaml:"style,omitempty" json:"style,omitempty"`             // Describes how the header value will be serialized.
+	Explode     *bool       `yaml:"explode,omitempty" json:"explode,omitempty"`         // When this is true, array or object values generate separate values.
+	Schema      *Schema     `yaml:"schema,omitempty" json:"schema,omitempty"`           // The schema defining the type used for the header.
+	Example     interface{} `yaml:"example,omitempty" json:"example,omitempty"`         // Example of the header's potential value.
+}
+
+// Example holds an example value with optional documentation.
+type Example struct {
+	Ref           string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`                   // A reference to an example in the components.
+	Summary       string      `yaml:"summary,omitempty" json:"summary,omitempty"`             // Short description for the example.
+	Description   string      `yaml:"description,omitempty" json:"description,omitempty"`     // Long description for the example.
+	Value         interface{} `yaml:"value,omitempty" json:"value,omitempty"`                 // Embedded literal example.
+	ExternalValue string      `yaml:"externalValue,omitempty" json:"externalValue,omitempty"` // A URL that points to the literal example.
+}
+
+// Link represents a possible design-time link for a response.
+type Link struct {
+	Ref          string                 `yaml:"$ref,omitempty" json:"$ref,omitempty"`                 // A reference to a link in the components.
+	OperationRef string                 `yaml:"operationRef,omitempty" json:"operationRef,omitempty"` // A relative or absolute URI reference to an OAS operation.
+	OperationId  string                 `yaml:"operationId,omitempty" json:"operationId,omitempty"`   // The name of an existing, resolvable OAS operation.
+	Parameters   map[string]interface{} `yaml:"parameters,omitempty" json:"parameters,omitempty"`     // A map representing parameters to pass to the linked operation.
+	RequestBody  interface{}            `yaml:"requestBody,omitempty" json:"requestBody,omitempty"`   // A literal value or expression to use as a request body when calling the target operation.
+	Description  string                 `yaml:"description,omitempty" json:"description,omitempty"`   // A description of the link.
+	Server       *Server                `yaml:"server,omitempty" json:"server,omitempty"`             // A server object to be used by the target operation.
+}
+
+// Callback is a map of possible out-of band callbacks related to the parent operation, keyed by runtime expression.
+type Callback map[string]PathItem
+
+// Schema represents an OpenAPI 3.0 schema, an extended subset of JSON Schema Specification Wright Draft 00.
+type Schema struct {
+	Ref                  string                  `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Title                string                  `yaml:"title,omitempty" json:"title,omitempty"`
+	MultipleOf           *float64                `yaml:"multipleOf,omitempty" json:"multipleOf,omitempty"`
+	Maximum              *float64                `yaml:"maximum,omitempty" json:"maximum,omitempty"`
+	ExclusiveMaximum     *bool                   `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"`
+	Minimum              *float64                `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	ExclusiveMinimum     *bool                   `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"`
+	MaxLength            *int                    `yaml:"maxLength,omitempty" json:"maxLength,omitempty"`
+	MinLength            *int                    `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+	Pattern              *string                 `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	MaxItems             *int                    `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+	MinItems             *int                    `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	UniqueItems          *bool                   `yaml:"uniqueItems,omitempty" json:"uniqueItems,omitempty"`
+	MaxProperties        *int                    `yaml:"maxProperties,omitempty" json:"maxProperties,omitempty"`
+	MinProperties        *int                    `yaml:"minProperties,omitempty" json:"minProperties,omitempty"`
+	Required             []string                `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum                 []interface{}           `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Type                 string                  `yaml:"type,omitempty" json:"type,omitempty"`
+	AllOf                []Schema                `yaml:"allOf,omitempty" json:"allOf,omitempty"`
+	OneOf                []Schema                `yaml:"oneOf,omitempty" json:"oneOf,omitempty"`
+	AnyOf                []Schema                `yaml:"anyOf,omitempty" json:"anyOf,omitempty"`
+	Not                  *Schema                 `yaml:"not,omitempty" json:"not,omitempty"`
+	Items                *Schema                 `yaml:"items,omitempty" json:"items,omitempty"`
+	Properties           map[string]Schema       `yaml:"properties,omitempty" json:"properties,omitempty"`
+	AdditionalProperties *AdditionalProperties   `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	Description          string                  `yaml:"description,omitempty" json:"description,omitempty"`
+	Format               string                  `yaml:"format,omitempty" json:"format,omitempty"`
+	Default              interface{}             `yaml:"default,omitempty" json:"default,omitempty"`
+	Nullable             bool                    `yaml:"nullable,omitempty" json:"nullable,omitempty"`
+	Discriminator        *Discriminator          `yaml:"discriminator,omitempty" json:"discriminator,omitempty"`
+	ReadOnly             bool                    `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
+	WriteOnly            bool                    `yaml:"writeOnly,omitempty" json:"writeOnly,omitempty"`
+	Xml                  *swagger2.Xml           `yaml:"xml,omitempty" json:"xml,omitempty"`
+	ExternalDocs         *swagger2.Documentation `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
+	Example              interface{}             `yaml:"example,omitempty" json:"example,omitempty"`
+	Deprecated           bool                    `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+}
+
+// AdditionalProperties holds the value of a schema's additionalProperties keyword, which is either a boolean or a schema.
+type AdditionalProperties struct {
+	Allowed bool    // Used when Schema is nil
+	Schema  *Schema // The schema of the map values
+}
+
+// Discriminator aids in serialization, deserialization, and validation when payloads may be one of a number of different schemas.
+type Discriminator struct {
+	PropertyName string            `yaml:"propertyName" json:"propertyName"`           // Required. The name of the property in the payload that will hold the discriminator value.
+	Mapping      map[string]string `yaml:"mapping,omitempty" json:"mapping,omitempty"` // An object to hold mappings between payload values and schema names or references.
+}
+
+// SecurityScheme defines a security scheme that can be used by the operations.
+type SecurityScheme struct {
+	Ref              string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`                         // A reference to a security scheme in the components.
+	Type             string      `yaml:"type" json:"type"`                                             // Required. The type of the security scheme. Valid values are "apiKey", "http", "oauth2", "openIdConnect".
+	Description      string      `yaml:"description,omitempty" json:"description,omitempty"`           // A short description for security scheme.
+	Name             string      `yaml:"name,omitempty" json:"name,omitempty"`                         // Required for apiKey. The name of the header, query or cookie parameter to be used.
+	In               string      `yaml:"in,omitempty" json:"in,omitempty"`                             // Required for apiKey. The location of the API key. Valid values are "query", "header" or "cookie".
+	Scheme           string      `yaml:"scheme,omitempty" json:"scheme,omitempty"`                     // Required for http. The name of the HTTP Authorization scheme to be used in the Authorization header.
+	BearerFormat     string      `yaml:"bearerFormat,omitempty" json:"bearerFormat,omitempty"`         // A hint to the client to identify how the bearer token is formatted.
+	Flows            *OAuthFlows `yaml:"flows,omitempty" json:"flows,omitempty"`                       // Required for oauth2. An object containing configuration information for the flow types supported.
+	OpenIdConnectUrl string      `yaml:"openIdConnectUrl,omitempty" json:"openIdConnectUrl,omitempty"` // Required for openIdConnect. OpenId Connect URL to discover OAuth2 configuration values.
+}
+
+// OAuthFlows allows configuration of the supported OAuth Flows.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `yaml:"implicit,omitempty" json:"implicit,omitempty"`                   // Configuration for the OAuth Implicit flow.
+	Password          *OAuthFlow `yaml:"password,omitempty" json:"password,omitempty"`                   // Configuration for the OAuth Resource Owner Password flow.
+	ClientCredentials *OAuthFlow `yaml:"clientCredentials,omitempty" json:"clientCredentials,omitempty"` // Configuration for the OAuth Client Credentials flow, previously called application in OpenAPI 2.0.
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode,omitempty" json:"authorizationCode,omitempty"` // Configuration for the OAuth Authorization Code flow, previously called accessCode in OpenAPI 2.0.
+}
+
+// OAuthFlow holds configuration details for a supported OAuth Flow.
+type OAuthFlow struct {
+	AuthorizationUrl string          `yaml:"authorizationUrl,omitempty" json:"authorizationUrl,omitempty"` // Required for implicit and authorizationCode. The authorization URL to be used for this flow.
+	TokenUrl         string          `yaml:"tokenUrl,omitempty" json:"tokenUrl,omitempty"`                 // Required for password, clientCredentials and authorizationCode. The token URL to be used for this flow.
+	RefreshUrl       string          `yaml:"refreshUrl,omitempty" json:"refreshUrl,omitempty"`             // The URL to be used for obtaining refresh tokens.
+	Scopes           swagger2.Scopes `yaml:"scopes" json:"scopes"`                                         // Required. The available scopes for the OAuth2 security scheme.
+}
@@ -0,0 +1,666 @@
+package openapi3
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Import parses OpenAPI 3 JSON or YAML data and converts it to a Swagger 2 document. The returned errors describe
+// anything that Swagger 2 cannot represent; err is only set when the data could not be parsed.
+func Import(in []byte) (swag *swagger2.Swagger, lossy []error, err error) {
+	var o *OpenAPI
+	if trimmed := bytes.TrimSpace(in); len(trimmed) > 0 && trimmed[0] == '{' {
+		o, err = LoadJson(in)
+	} else {
+		o, err = LoadYaml(in)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	swag, lossy = ToSwagger2(o)
+	return swag, lossy, nil
+}
+
+// ToSwagger2 converts an OpenAPI 3.0 document into a Swagger 2 document. The returned errors describe
+// anything that could not be represented exactly; the document is still usable when they are present.
+func ToSwagger2(o *OpenAPI) (*swagger2.Swagger, []error) {
+	c := &down{src: o, errs: make([]error, 0)}
+	s := &swagger2.Swagger{
+		Swagger:      "2.0",
+		Info:         o.Info,
+		Paths:        make(swagger2.Paths),
+		Security:     o.Security,
+		Tags:         o.Tags,
+		ExternalDocs: o.ExternalDocs,
+	}
+	s.Host, s.BasePath, s.Schemes = c.servers("servers", o.Servers)
+	c.host, c.basePath = s.Host, s.BasePath
+	if comp := o.Components; comp != nil {
+		if len(comp.Schemas) > 0 {
+			s.Definitions = make(swagger2.Definitions)
+			for n, sc := range comp.Schemas {
+				s.Definitions[n] = *c.schema("components.schemas."+n, &sc)
+			}
+		}
+		for n, p := range comp.Parameters {
+			if p.In == "cookie" {
+				c.lossy("components.parameters."+n, "cookie parameters are not supported; parameter dropped")
+				continue
+			}
+			if s.Parameters == nil {
+				s.Parameters = make(swagger2.Parameters)
+			}
+			s.Parameters[n] = c.parameter("components.parameters."+n, &p)
+		}
+		for n, r := range comp.Responses {
+			if s.Responses == nil {
+				s.Responses = make(swagger2.Responses)
+			}
+			s.Responses[n], _ = c.response("components.responses."+n, &r)
+		}
+		for n, sc := range comp.SecuritySchemes {
+			if d, ok := c.securityDefinition("components.securitySchemes."+n, &sc); ok {
+				if s.SecurityDefinitions == nil {
+					s.SecurityDefinitions = make(swagger2.SecurityDefinitions)
+				}
+				s.SecurityDefinitions[n] = d
+			}
+		}
+		if len(comp.Links) > 0 {
+			c.lossy("components.links", "links are not supported; %d dropped", len(comp.Links))
+		}
+		if len(comp.Callbacks) > 0 {
+			c.lossy("components.callbacks", "callbacks are not supported; %d dropped", len(comp.Callbacks))
+		}
+	}
+	for path, item := range o.Paths {
+		s.Paths[path] = c.pathItem(path, &item)
+	}
+	return s, c.errs
+}
+
+// down holds the state of an OpenAPI 3 to Swagger 2 conversion
+type down struct {
+	src      *OpenAPI
+	host     string
+	basePath string
+	errs     []error
+}
+
+// lossy records something that could not be converted exactly
+func (c *down) lossy(where, format string, args ...interface{}) {
+	c.errs = append(c.errs, fmt.Errorf(where+": "+format, args...))
+}
+
+// servers reduces a server list to a host, base path and set of schemes
+func (c *down) servers(where string, servers []Server) (host, basePath string, schemes []string) {
+	for i, srv := range servers {
+		u, err := url.Parse(expandServer(&srv))
+		if err != nil {
+			c.lossy(where, "server URL %s is not valid: %s", srv.Url, err)
+			continue
+		}
+		path := strings.TrimSuffix(u.Path, "/")
+		if i == 0 {
+			host, basePath = u.Host, path
+		} else if u.Host != host || path != basePath {
+			c.lossy(where, "server %s has a different host or base path than %s; server dropped", srv.Url, servers[0].Url)
+			continue
+		}
+		if u.Scheme != "" && !lists.Contains(schemes, u.Scheme) {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	return
+}
+
+// expandServer substitutes the default value of each server variable into the URL
+func expandServer(srv *Server) string {
+	u := srv.Url
+	for n, v := range srv.Variables {
+		u = strings.Replace(u, "{"+n+"}", v.Default, -1)
+	}
+	return u
+}
+
+// ref rewrites an OpenAPI 3 component reference into its Swagger 2 location
+func (c *down) ref(ref string) string {
+	if !strings.HasPrefix(ref, "#/components/") {
+		return ref
+	}
+	section, name := swagger2.ParseRef("#/" + strings.TrimPrefix(ref, "#/components/"))
+	switch section {
+	case "schemas":
+		return swagger2.MakeRef("definitions", name)
+	case "parameters", "responses":
+		return swagger2.MakeRef(section, name)
+	}
+	return ref
+}
+
+// component returns the name of the component a reference points at in the given section
+func component(ref, section string) (string, bool) {
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	_, name := swagger2.ParseRef("#/x/" + strings.TrimPrefix(ref, prefix))
+	return name, true
+}
+
+// pathItem converts a path item and its operations
+func (c *down) pathItem(path string, item *PathItem) swagger2.PathItem {
+	p := swagger2.PathItem{Ref: item.Ref}
+	if item.Summary != "" || item.Description != "" {
+		c.lossy(path, "path summary and description are not supported; dropped")
+	}
+	if len(item.Servers) > 0 {
+		c.lossy(path, "path level servers are not supported; dropped")
+	}
+	for i := range item.Parameters {
+		if param, ok := c.parameterOrRef(path, &item.Parameters[i]); ok {
+			p.Parameters = append(p.Parameters, param)
+		}
+	}
+	for _, method := range Methods {
+		op := item.Operation(method)
+		if op == nil {
+			continue
+		}
+		where := strings.ToUpper(method) + " " + path
+		if method == "trace" {
+			c.lossy(where, "trace operations are not supported; operation dropped")
+			continue
+		}
+		p.SetOperation(method, c.operation(where, op))
+	}
+	return p
+}
+
+// operation converts a single operation, turning its request body into a body or formData parameters
+func (c *down) operation(where string, op *Operation) *swagger2.Operation {
+	o := &swagger2.Operation{
+		Tags:         op.Tags,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		ExternalDocs: op.ExternalDocs,
+		OperationId:  op.OperationId,
+		Responses:    make(swagger2.Responses),
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+	}
+	if len(op.Servers) > 0 {
+		host, basePath, schemes := c.servers(where, op.Servers)
+		if host != c.host || basePath != c.basePath {
+			c.lossy(where, "operation servers have a different host or base path; dropped")
+		} else {
+			o.Schemes = schemes
+		}
+	}
+	for i := range op.Parameters {
+		if param, ok := c.parameterOrRef(where, &op.Parameters[i]); ok {
+			o.Parameters = append(o.Parameters, param)
+		}
+	}
+	if op.RequestBody != nil {
+		params, consumes := c.requestBody(where, op.RequestBody)
+		o.Parameters = append(o.Parameters, params...)
+		o.Consumes = consumes
+	}
+	if len(op.Callbacks) > 0 {
+		c.lossy(where, "callbacks are not supported; %d dropped", len(op.Callbacks))
+	}
+	produces := make([]string, 0)
+	for code, r := range op.Responses {
+		resp, types := c.response(where+" "+code, &r)
+		o.Responses[code] = resp
+		for _, t := range types {
+			if !lists.Contains(produces, t) {
+				produces = append(produces, t)
+			}
+		}
+	}
+	if len(produces) > 0 {
+		sort.Strings(produces)
+		o.Produces = produces
+	}
+	return o
+}
+
+// parameterOrRef converts a parameter, keeping references to shared parameters. Cookie parameters are dropped.
+func (c *down) parameterOrRef(where string, p *Parameter) (swagger2.Parameter, bool) {
+	if p.Ref != "" {
+		if name, ok := component(p.Ref, "parameters"); ok && c.src.Components != nil {
+			if shared, ok := c.src.Components.Parameters[name]; ok && shared.In == "cookie" {
+				c.lossy(where, "cookie parameter %s is not supported; parameter dropped", shared.Name)
+				return swagger2.Parameter{}, false
+			}
+		}
+		return swagger2.Parameter{ItemsDef: swagger2.ItemsDef{Ref: c.ref(p.Ref)}}, true
+	}
+	if p.In == "cookie" {
+		c.lossy(where, "cookie parameter %s is not supported; parameter dropped", p.Name)
+		return swagger2.Parameter{}, false
+	}
+	return c.parameter(where, p), true
+}
+
+// parameter converts a query, header or path parameter
+func (c *down) parameter(where string, p *Parameter) swagger2.Parameter {
+	param := swagger2.Parameter{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+	}
+	if p.Required {
+		param.Required = boolPtr(true)
+	}
+	schema := p.Schema
+	if schema == nil && len(p.Content) > 0 {
+		c.lossy(where, "parameter %s uses content; only its schema was kept", p.Name)
+		for _, mt := range mediaTypes(p.Content) {
+			schema = p.Content[mt].Schema
+			break
+		}
+	}
+	if schema != nil {
+		param.ItemsDef = *c.items(where+" parameter "+p.Name, c.resolveSchema(schema))
+	}
+	if param.Type == "array" {
+		param.CollectionFormat = c.collectionFormat(where+" parameter "+p.Name, p.Style, p.Explode, p.In)
+	}
+	return param
+}
+
+// collectionFormat maps a style and explode value onto the equivalent collection format
+func (c *down) collectionFormat(where, style string, explode *bool, in string) string {
+	if style == "" {
+		if in == "query" || in == "formData" {
+			style = "form"
+		} else {
+			style = "simple"
+		}
+	}
+	exploded := style == "form"
+	if explode != nil {
+		exploded = *explode
+	}
+	switch style {
+	case "form":
+		if !exploded {
+			return "csv"
+		}
+		if in == "query" || in == "formData" {
+			return "multi"
+		}
+	case "simple":
+		// explode makes no difference to arrays in the simple style
+		return "csv"
+	case "spaceDelimited":
+		if !exploded {
+			return "ssv"
+		}
+	case "pipeDelimited":
+		if !exploded {
+			return "pipes"
+		}
+	}
+	c.lossy(where, "style %s with explode=%t has no equivalent collectionFormat; using csv", style, exploded)
+	return "csv"
+}
+
+// requestBody converts a request body into either a body parameter or formData parameters, returning the consumed media types
+func (c *down) requestBody(where string, rb *RequestBody) ([]swagger2.Parameter, []string) {
+	if rb.Ref != "" {
+		name, _ := component(rb.Ref, "requestBodies")
+		shared, ok := c.lookupRequestBody(name)
+		if !ok {
+			c.lossy(where, "request body %s could not be resolved; dropped", rb.Ref)
+			return nil, nil
+		}
+		rb = &shared
+	}
+	types := mediaTypes(rb.Content)
+	if len(types) == 0 {
+		return nil, nil
+	}
+	form := make([]string, 0)
+	for _, mt := range types {
+		if mt == "multipart/form-data" || mt == "application/x-www-form-urlencoded" {
+			form = append(form, mt)
+		}
+	}
+	if len(form) > 0 {
+		if len(form) != len(types) {
+			c.lossy(where, "request body mixes form and non-form media types; only %s kept", strings.Join(form, ", "))
+		}
+		return c.formParameters(where, rb, rb.Content[form[0]]), form
+	}
+
+	// prefer a JSON schema when the media types disagree
+	chosen := types[0]
+	for _, mt := range types {
+		if strings.Contains(mt, "json") {
+			chosen = mt
+			break
+		}
+	}
+	body := swagger2.Parameter{Name: "body", In: "body", Description: rb.Description}
+	if rb.Required {
+		body.Required = boolPtr(true)
+	}
+	if m := rb.Content[chosen]; m.Schema != nil {
+		body.Schema = c.schema(where+" body", m.Schema)
+	} else {
+		body.Schema = &swagger2.Schema{}
+	}
+	return []swagger2.Parameter{body}, types
+}
+
+// formParameters turns the properties of a form request body into formData parameters
+func (c *down) formParameters(where string, rb *RequestBody, m MediaType) []swagger2.Parameter {
+	obj := m.Schema
+	if obj != nil {
+		obj = c.resolveSchema(obj)
+	}
+	if obj == nil || len(obj.Properties) == 0 {
+		c.lossy(where, "form request body has no properties; dropped")
+		return nil
+	}
+	params := make([]swagger2.Parameter, 0, len(obj.Properties))
+	for _, n := range propertyNames(obj.Properties) {
+		prop := obj.Properties[n]
+		param := swagger2.Parameter{Name: n, In: "formData", Description: prop.Description}
+		param.ItemsDef = *c.items(where+" field "+n, c.resolveSchema(&prop))
+		if param.Type == "string" && param.Format == "binary" {
+			param.Type = "file"
+			param.Format = ""
+		}
+		if lists.Contains(obj.Required, n) {
+			param.Required = boolPtr(true)
+		}
+		if enc, ok := m.Encoding[n]; ok && param.Type == "array" {
+			param.CollectionFormat = c.collectionFormat(where+" field "+n, enc.Style, enc.Explode, "formData")
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// lookupRequestBody finds a shared request body by name
+func (c *down) lookupRequestBody(name string) (RequestBody, bool) {
+	if c.src.Components == nil {
+		return RequestBody{}, false
+	}
+	rb, ok := c.src.Components.RequestBodies[name]
+	return rb, ok
+}
+
+// response converts a response, returning the media types it produces
+func (c *down) response(where string, r *Response) (swagger2.Response, []string) {
+	if r.Ref != "" {
+		name, _ := component(r.Ref, "responses")
+		var shared Response
+		ok := false
+		if c.src.Components != nil {
+			shared, ok = c.src.Components.Responses[name]
+		}
+		if !ok {
+			c.lossy(where, "response %s could not be resolved", r.Ref)
+			return swagger2.Response{Description: r.Description}, nil
+		}
+		// Swagger 2 responses cannot be references in this model, so the shared response is inlined
+		r = &shared
+	}
+	resp := swagger2.Response{Description: r.Description}
+	if len(r.Headers) > 0 {
+		resp.Headers = make(swagger2.Headers)
+		for n, h := range r.Headers {
+			if h.Ref != "" {
+				name, _ := component(h.Ref, "headers")
+				var shared Header
+				ok := false
+				if c.src.Components != nil {
+					shared, ok = c.src.Components.Headers[name]
+				}
+				if !ok {
+					c.lossy(where, "header %s could not be resolved", h.Ref)
+					continue
+				}
+				h = shared
+			}
+			hdr := swagger2.Header{Description: h.Description}
+			if h.Schema != nil {
+				hdr.ItemsDef = *c.items(where+" header "+n, c.resolveSchema(h.Schema))
+			}
+			if hdr.Type == "array" {
+				hdr.CollectionFormat = c.collectionFormat(where+" header "+n, h.Style, h.Explode, "header")
+			}
+			resp.Headers[n] = hdr
+		}
+	}
+	if len(r.Links) > 0 {
+		c.lossy(where, "links are not supported; %d dropped", len(r.Links))
+	}
+	types := mediaTypes(r.Content)
+	var first *Schema
+	for _, mt := range types {
+		m := r.Content[mt]
+		if m.Schema != nil {
+			if first == nil {
+				first = m.Schema
+				resp.Schema = c.schema(where, m.Schema)
+			} else if !sameSchema(first, m.Schema) {
+				c.lossy(where, "media type %s has a different schema than %s; only one schema can be kept", mt, types[0])
+			}
+		}
+		if m.Example != nil {
+			if resp.Examples == nil {
+				resp.Examples = make(swagger2.Example)
+			}
+			resp.Examples[mt] = m.Example
+		}
+	}
+	return resp, types
+}
+
+// securityDefinition converts a security scheme; false is returned if it has no Swagger 2 equivalent
+func (c *down) securityDefinition(where string, s *SecurityScheme) (swagger2.SecurityDefinition, bool) {
+	d := swagger2.SecurityDefinition{Description: s.Description}
+	switch s.Type {
+	case "apiKey":
+		if s.In == "cookie" {
+			c.lossy(where, "cookie api keys are not supported; scheme dropped")
+			return d, false
+		}
+		d.Type, d.Name, d.In = "apiKey", s.Name, s.In
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			d.Type = "basic"
+		case "bearer":
+			c.lossy(where, "bearer authentication is not supported; described as an Authorization header api key")
+			d.Type, d.Name, d.In = "apiKey", "Authorization", "header"
+		default:
+			c.lossy(where, "http scheme %s is not supported; scheme dropped", s.Scheme)
+			return d, false
+		}
+	case "oauth2":
+		if s.Flows == nil {
+			c.lossy(where, "oauth2 scheme has no flows; scheme dropped")
+			return d, false
+		}
+		d.Type = "oauth2"
+		flows := make([]string, 0)
+		if f := s.Flows.Implicit; f != nil {
+			flows = append(flows, "implicit")
+			d.Flow, d.AuthorizationUrl, d.Scopes = "implicit", f.AuthorizationUrl, f.Scopes
+		}
+		if f := s.Flows.Password; f != nil {
+			flows = append(flows, "password")
+			d.Flow, d.TokenUrl, d.Scopes = "password", f.TokenUrl, f.Scopes
+		}
+		if f := s.Flows.ClientCredentials; f != nil {
+			flows = append(flows, "clientCredentials")
+			d.Flow, d.TokenUrl, d.Scopes = "application", f.TokenUrl, f.Scopes
+		}
+		if f := s.Flows.AuthorizationCode; f != nil {
+			flows = append(flows, "authorizationCode")
+			d.Flow, d.AuthorizationUrl, d.TokenUrl, d.Scopes = "accessCode", f.AuthorizationUrl, f.TokenUrl, f.Scopes
+		}
+		if len(flows) == 0 {
+			c.lossy(where, "oauth2 scheme has no flows; scheme dropped")
+			return d, false
+		}
+		if len(flows) > 1 {
+			c.lossy(where, "only one oauth2 flow is supported; kept %s of %s", flows[len(flows)-1], strings.Join(flows, ", "))
+		}
+	default:
+		c.lossy(where, "%s security schemes are not supported; scheme dropped", s.Type)
+		return d, false
+	}
+	return d, true
+}
+
+// resolveSchema follows a reference to a component schema; used where Swagger 2 only allows simple types
+func (c *down) resolveSchema(s *Schema) *Schema {
+	for i := 0; s.Ref != "" && i < 32; i++ {
+		name, ok := component(s.Ref, "schemas")
+		if !ok || c.src.Components == nil {
+			break
+		}
+		target, ok := c.src.Components.Schemas[name]
+		if !ok {
+			break
+		}
+		s = &target
+	}
+	return s
+}
+
+// items converts a schema into the simple type used by non-body parameters, headers and array items
+func (c *down) items(where string, s *Schema) *swagger2.ItemsDef {
+	d := &swagger2.ItemsDef{
+		Ref:              c.ref(s.Ref),
+		Type:             s.Type,
+		Format:           s.Format,
+		Default:          s.Default,
+		Maximum:          s.Maximum,
+		ExclusiveMaximum: s.ExclusiveMaximum,
+		Minimum:          s.Minimum,
+		ExclusiveMinimum: s.ExclusiveMinimum,
+		MaxLength:        s.MaxLength,
+		MinLength:        s.MinLength,
+		Pattern:          s.Pattern,
+		MaxItems:         s.MaxItems,
+		MinItems:         s.MinItems,
+		UniqueItems:      s.UniqueItems,
+		Enum:             s.Enum,
+		MultipleOf:       s.MultipleOf,
+	}
+	if s.Items != nil {
+		if s.Items.Ref == "" && len(s.Items.Properties) > 0 {
+			c.lossy(where, "inline object properties of array items are not supported; dropped")
+		}
+		d.Items = c.items(where, s.Items)
+	}
+	if a := s.AdditionalProperties; a != nil {
+		if a.Schema != nil {
+			d.AdditionalProperties = c.items(where, a.Schema)
+		} else if !a.Allowed {
+			c.lossy(where, "additionalProperties: false is not supported; dropped")
+		}
+	}
+	c.unsupported(where, s)
+	return d
+}
+
+// unsupported reports schema keywords that Swagger 2 cannot express
+func (c *down) unsupported(where string, s *Schema) {
+	if len(s.OneOf) > 0 {
+		c.lossy(where, "oneOf is not supported; %d alternatives dropped", len(s.OneOf))
+	}
+	if len(s.AnyOf) > 0 {
+		c.lossy(where, "anyOf is not supported; %d alternatives dropped", len(s.AnyOf))
+	}
+	if s.Not != nil {
+		c.lossy(where, "not is not supported; dropped")
+	}
+	if s.Nullable {
+		c.lossy(where, "nullable is only supported on schemas; dropped")
+	}
+	if s.WriteOnly {
+		c.lossy(where, "writeOnly is not supported; dropped")
+	}
+	if s.Discriminator != nil && len(s.Discriminator.Mapping) > 0 {
+		c.lossy(where, "discriminator mappings are not supported; dropped")
+	}
+}
+
+// schema converts a schema, rewriting references to component schemas
+func (c *down) schema(where string, s *Schema) *swagger2.Schema {
+	plain := *s
+	plain.Nullable = false // carried as x-nullable below
+	d := &swagger2.Schema{
+		ItemsDef:      *c.items(where, &plain),
+		Title:         s.Title,
+		Description:   s.Description,
+		MaxProperties: s.MaxProperties,
+		MinProperties: s.MinProperties,
+		Required:      s.Required,
+		Xml:           s.Xml,
+		ExternalDocs:  s.ExternalDocs,
+		Example:       s.Example,
+	}
+	if s.ReadOnly {
+		d.ReadOnly = boolPtr(true)
+	}
+	if s.Nullable {
+		d.Extensions = swagger2.Extensions{"x-nullable": true}
+	}
+	if s.Discriminator != nil {
+		d.Discriminator = s.Discriminator.PropertyName
+	}
+	for i := range s.AllOf {
+		d.AllOf = append(d.AllOf, *c.schema(where, &s.AllOf[i]))
+	}
+	if len(s.Properties) > 0 {
+		d.Properties = make(map[string]swagger2.Schema)
+		for n, p := range s.Properties {
+			d.Properties[n] = *c.schema(where+"."+n, &p)
+		}
+	}
+	return d
+}
+
+// sameSchema is a shallow check that two schemas describe the same type
+func sameSchema(a, b *Schema) bool {
+	return a == b || (a.Ref == b.Ref && a.Type == b.Type && a.Format == b.Format && (a.Items == nil) == (b.Items == nil) &&
+		(a.Items == nil || sameSchema(a.Items, b.Items)))
+}
+
+// mediaTypes returns the media types of a content map in sorted order
+func mediaTypes(m map[string]MediaType) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// propertyNames returns the names of a schema's properties in sorted order
+func propertyNames(m map[string]Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,170 @@
+package openapi3
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestRoundTripExamples(t *testing.T) {
+	for file, swag := range loadExamples(t) {
+		doc, _ := FromSwagger2(swag)
+		back, _ := ToSwagger2(doc)
+		if errs := back.Validate(); len(errs) > 0 {
+			t.Errorf("%s: round trip does not validate:\n%s", file, swagger2.ErrorList(errs).Indent("\t"))
+		}
+		if len(back.Paths) != len(swag.Paths) || len(back.Definitions) != len(swag.Definitions) {
+			t.Errorf("%s: round trip lost paths or definitions", file)
+		}
+	}
+}
+
+const importSource = `
+openapi: 3.0.3
+info:
+  title: Vendor
+  version: "2"
+servers:
+  - url: https://{region}.example.com/api
+    variables:
+      region:
+        default: eu
+  - url: http://eu.example.com/api
+  - url: https://other.example.com/v2
+paths:
+  /items/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: session
+        in: cookie
+        schema:
+          type: string
+    get:
+      operationId: getItem
+      parameters:
+        - name: fields
+          in: query
+          style: form
+          explode: true
+          schema:
+            type: array
+            items:
+              type: string
+      responses:
+        "200":
+          description: the item
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+              example:
+                id: "1"
+          links:
+            self:
+              operationId: getItem
+      callbacks:
+        changed:
+          '{$request.body#/url}':
+            post:
+              responses:
+                "200":
+                  description: ok
+    put:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+      responses:
+        "204":
+          description: updated
+          headers:
+            X-Rate-Limit:
+              $ref: '#/components/headers/RateLimit'
+  /items/{id}/photo:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [file]
+              properties:
+                file:
+                  type: string
+                  format: binary
+      responses:
+        "204":
+          description: uploaded
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        id:
+          type: string
+        value:
+          oneOf:
+            - type: string
+            - type: integer
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes:
+            read: Read access
+`
+
+func TestImport(t *testing.T) {
+	swag, lossy, err := Import([]byte(importSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swag.Host != "eu.example.com" || swag.BasePath != "/api" || len(swag.Schemes) != 2 {
+		t.Errorf("unexpected host %q, base path %q and schemes %v", swag.Host, swag.BasePath, swag.Schemes)
+	}
+
+	item := swag.Paths["/items/{id}"]
+	if len(item.Parameters) != 1 || item.Parameters[0].Name != "id" {
+		t.Errorf("cookie parameter should have been dropped: %+v", item.Parameters)
+	}
+	get := item.Get
+	if get.Parameters[0].CollectionFormat != "multi" {
+		t.Errorf("exploded form style should become multi, got %q", get.Parameters[0].CollectionFormat)
+	}
+	if r := get.Responses["200"]; r.Schema == nil || r.Schema.Ref != "#/definitions/Item" || r.Examples["application/json"] == nil {
+		t.Errorf("response was not converted: %+v", r)
+	}
+	put := item.Put
+	if len(put.Parameters) != 1 || put.Parameters[0].In != "body" || put.Parameters[0].Schema.Ref != "#/definitions/Item" {
+		t.Errorf("request body was not converted to a body parameter: %+v", put.Parameters)
+	}
+	if _, ok := put.Responses["204"].Headers["X-Rate-Limit"]; ok {
+		t.Errorf("unresolved header should have been dropped: %+v", put.Responses["204"].Headers)
+	}
+	photo := swag.Paths["/items/{id}/photo"].Post
+	if len(photo.Parameters) != 1 || photo.Parameters[0].Type != "file" || photo.Parameters[0].In != "formData" {
+		t.Errorf("form request body was not converted to a file parameter: %+v", photo.Parameters)
+	}
+	if swag.SecurityDefinitions["oauth"].Flow != "application" {
+		t.Errorf("client credentials should become the application flow: %+v", swag.SecurityDefinitions["oauth"])
+	}
+
+	text := swagger2.ErrorList(lossy).String()
+	for _, expect := range []string{"different host or base path", "cookie parameter session", "links", "callbacks", "oneOf", "bearer", "header #/components/headers/RateLimit could not be resolved"} {
+		if !strings.Contains(text, expect) {
+			t.Errorf("expected a warning about %s, got:\n%s", expect, text)
+		}
+	}
+}
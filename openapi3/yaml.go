@@ -0,0 +1,45 @@
+package openapi3
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+// LoadYaml parses the incoming byte array as OpenAPI 3 YAML data
+func LoadYaml(in []byte) (*OpenAPI, error) {
+	var o OpenAPI
+	err := yaml.Unmarshal(in, &o)
+	if err != nil {
+		return nil, err
+	}
+	return &o, err
+}
+
+// Yaml serializes the OpenAPI 3 document into YAML format
+func (o *OpenAPI) Yaml() ([]byte, error) {
+	return yaml.Marshal(o)
+}
+
+// MarshalYAML writes additionalProperties as either a boolean or a schema
+func (a AdditionalProperties) MarshalYAML() (interface{}, error) {
+	if a.Schema != nil {
+		return a.Schema, nil
+	}
+	return a.Allowed, nil
+}
+
+// UnmarshalYAML reads additionalProperties from either a boolean or a schema
+func (a *AdditionalProperties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var b bool
+	if err := unmarshal(&b); err == nil {
+		a.Allowed = b
+		a.Schema = nil
+		return nil
+	}
+	var s Schema
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	a.Allowed = true
+	a.Schema = &s
+	return nil
+}
@@ -0,0 +1,521 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// JsonPatch returns a copy of a document with a JSON Patch (RFC 6902) applied, given as JSON or YAML. The patch is
+// applied as a whole: when an operation fails, for instance because its path no longer exists in the document,
+// the error names that operation and nothing is changed.
+func JsonPatch(s *Swagger, patch []byte) (*Swagger, error) {
+	v, err := decodeValue(patch)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("a JSON Patch must be a list of operations")
+	}
+	doc, err := documentValue(s)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range list {
+		fields, _ := e.(map[string]interface{})
+		var op struct {
+			Op, Path, From string
+			Value          interface{}
+		}
+		op.Op, _ = fields["op"].(string)
+		op.Path, _ = fields["path"].(string)
+		op.From, _ = fields["from"].(string)
+		op.Value = fields["value"]
+		fail := func(err error) error {
+			return fmt.Errorf("patch operation %d (%s %s): %s", i, op.Op, op.Path, err)
+		}
+		if _, ok := fields["path"].(string); !ok {
+			return nil, fail(errors.New("path is required"))
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if _, ok := fields["value"]; !ok {
+				return nil, fail(errors.New("value is required"))
+			}
+		case "move", "copy":
+			if _, ok := fields["from"].(string); !ok {
+				return nil, fail(errors.New("from is required"))
+			}
+		case "remove":
+		default:
+			return nil, fail(errors.New("unknown operation"))
+		}
+		switch op.Op {
+		case "add":
+			doc, err = addValue(doc, op.Path, op.Value)
+		case "remove":
+			doc, _, err = removeValue(doc, op.Path)
+		case "replace":
+			if doc, _, err = removeValue(doc, op.Path); err == nil {
+				doc, err = addValue(doc, op.Path, op.Value)
+			}
+		case "move":
+			var moved interface{}
+			if op.Path == op.From || strings.HasPrefix(op.Path, op.From+"/") {
+				if op.Path != op.From {
+					err = errors.New("cannot move a value into itself")
+				}
+				break
+			}
+			if doc, moved, err = removeValue(doc, op.From); err == nil {
+				doc, err = addValue(doc, op.Path, moved)
+			}
+		case "copy":
+			var copied interface{}
+			if copied, err = valueAt(doc, op.From); err == nil {
+				doc, err = addValue(doc, op.Path, copyValue(copied))
+			}
+		case "test":
+			var found interface{}
+			if found, err = valueAt(doc, op.Path); err == nil && !reflect.DeepEqual(found, op.Value) {
+				err = fmt.Errorf("value is %s, not %s", display(found), display(op.Value))
+			}
+		}
+		if err != nil {
+			return nil, fail(err)
+		}
+	}
+	return valueDocument(doc)
+}
+
+// MergePatch returns a copy of a document with a JSON Merge Patch (RFC 7386) applied, given as JSON or YAML. The
+// members of an object in the patch replace those of the document, objects are merged recursively, and null
+// removes a member.
+func MergePatch(s *Swagger, patch []byte) (*Swagger, error) {
+	p, err := decodeValue(patch)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := documentValue(s)
+	if err != nil {
+		return nil, err
+	}
+	return valueDocument(mergeValue(doc, p))
+}
+
+// mergeValue applies a merge patch to a value
+func mergeValue(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergeValue(t[k], v)
+		}
+	}
+	return t
+}
+
+// Overlay describes changes to a document with actions that target its parts with JSONPath expressions, so that
+// they can be applied again to new versions of the document. It follows the OpenAPI Overlay Specification.
+type Overlay struct {
+	Overlay string          `yaml:"overlay" json:"overlay"`                     // The version of the Overlay Specification, such as "1.0.0"
+	Info    OverlayInfo     `yaml:"info" json:"info"`                           // Describes the overlay
+	Extends string          `yaml:"extends,omitempty" json:"extends,omitempty"` // The URL of the document the overlay applies to
+	Actions []OverlayAction `yaml:"actions" json:"actions"`                     // The changes, applied in order
+}
+
+// OverlayInfo describes an overlay.
+type OverlayInfo struct {
+	Title   string `yaml:"title" json:"title"`
+	Version string `yaml:"version" json:"version"`
+}
+
+// OverlayAction changes or removes the parts of a document that a JSONPath expression matches.
+type OverlayAction struct {
+	Target      string      `yaml:"target" json:"target"`                               // A JSONPath expression, such as "$.paths['/pets'].get"
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"` // What the action does
+	Update      interface{} `yaml:"update,omitempty" json:"update,omitempty"`           // Merged into the objects matched, or appended to the arrays matched
+	Remove      bool        `yaml:"remove,omitempty" json:"remove,omitempty"`           // Whether to remove what is matched
+}
+
+// LoadOverlay reads an overlay from YAML or JSON.
+func LoadOverlay(in []byte) (*Overlay, error) {
+	var o Overlay
+	if err := yaml.Unmarshal(in, &o); err != nil {
+		return nil, err
+	}
+	for i := range o.Actions {
+		o.Actions[i].Update = jsonValue(o.Actions[i].Update)
+	}
+	return &o, nil
+}
+
+// ApplyOverlay returns a copy of a document with the actions of an overlay applied in order. An update is merged
+// into each object the target matches, its members replacing those of the object and objects being merged
+// recursively, and appended to each array it matches. An action whose target matches nothing, such as a path that
+// was removed upstream, or that has neither an update nor remove, is an error; the errors of all such actions are
+// returned together.
+func ApplyOverlay(s *Swagger, o *Overlay) (*Swagger, error) {
+	doc, err := documentValue(s)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]string, 0)
+	for i, action := range o.Actions {
+		if action.Update == nil && !action.Remove {
+			errs = append(errs, fmt.Sprintf("action %d: an update or remove is required", i))
+			continue
+		}
+		path, err := compileJsonPath(action.Target)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("action %d: %s", i, err))
+			continue
+		}
+		nodes := path.find(doc)
+		if len(nodes) == 0 {
+			errs = append(errs, fmt.Sprintf("action %d: %s matches nothing", i, action.Target))
+			continue
+		}
+		if action.Remove {
+			if len(nodes[0].tokens) == 0 {
+				errs = append(errs, fmt.Sprintf("action %d: cannot remove the document", i))
+				continue
+			}
+			// remove the last nodes first, so that the indexes of the others still hold
+			sort.Slice(nodes, func(a, b int) bool { return laterNode(nodes[a].tokens, nodes[b].tokens) })
+			for j, n := range nodes {
+				if j > 0 && n.pointer() == nodes[j-1].pointer() {
+					continue
+				}
+				removed, _, err := removeValue(doc, n.pointer())
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("action %d: %s", i, err))
+					continue
+				}
+				doc = removed
+			}
+			continue
+		}
+		update, err := normalValue(action.Update)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("action %d: %s", i, err))
+			continue
+		}
+		for _, n := range nodes {
+			var updated interface{}
+			switch v := n.value.(type) {
+			case map[string]interface{}:
+				updated = overlayValue(v, copyValue(update))
+			case []interface{}:
+				updated = append(v, copyValue(update))
+			default:
+				errs = append(errs, fmt.Sprintf("action %d: %s is not an object or an array", i, n.pointer()))
+				continue
+			}
+			if doc, err = setValue(doc, n.tokens, updated); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	return valueDocument(doc)
+}
+
+// laterNode returns true if a node comes after another in document order, comparing array indexes as numbers, so
+// that nodes sorted with it come after the nodes within them
+func laterNode(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		x, errA := strconv.Atoi(a[i])
+		y, errB := strconv.Atoi(b[i])
+		if errA == nil && errB == nil {
+			return x > y
+		}
+		return a[i] > b[i]
+	}
+	return len(a) > len(b)
+}
+
+// overlayValue merges an update into a value: objects are merged recursively and anything else is replaced
+func overlayValue(target, update interface{}) interface{} {
+	u, ok := update.(map[string]interface{})
+	t, isMap := target.(map[string]interface{})
+	if !ok || !isMap {
+		return update
+	}
+	for k, v := range u {
+		t[k] = overlayValue(t[k], v)
+	}
+	return t
+}
+
+// decodeValue reads a value from YAML or JSON, with the types that decoding JSON gives
+func decodeValue(in []byte) (interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(in, &v); err != nil {
+		return nil, err
+	}
+	return normalValue(jsonValue(v))
+}
+
+// normalValue returns a value with the types that decoding it from JSON gives, such as float64 for numbers
+func normalValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	err = json.Unmarshal(b, &decoded)
+	return decoded, err
+}
+
+// documentValue returns a document as a value decoded from JSON
+func documentValue(s *Swagger) (interface{}, error) {
+	b, err := s.Json()
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+// valueDocument returns the document of a value decoded from JSON. Members that the document has no field for,
+// such as a misspelled property of an operation, are an error rather than being lost.
+func valueDocument(v interface{}) (*Swagger, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s, err := LoadJson(b)
+	if err != nil {
+		return nil, err
+	}
+	kept, err := documentValue(s)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]string, 0)
+	for _, ptr := range droppedValues(v, kept, "") {
+		errs = append(errs, ptr+" is not part of a Swagger document")
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	return s, nil
+}
+
+// droppedValues returns the JSON Pointers of the members of a value decoded from JSON that are missing from
+// another. Empty members, which the document leaves out, are not missing.
+func droppedValues(v, kept interface{}, ptr string) []string {
+	dropped := make([]string, 0)
+	switch v := v.(type) {
+	case map[string]interface{}:
+		k, _ := kept.(map[string]interface{})
+		for _, name := range keys(v) {
+			if e, ok := k[name]; ok {
+				dropped = append(dropped, droppedValues(v[name], e, pointer(ptr, name))...)
+			} else if !emptyValue(v[name]) {
+				dropped = append(dropped, pointer(ptr, name))
+			}
+		}
+	case []interface{}:
+		k, _ := kept.([]interface{})
+		for i, e := range v {
+			if i < len(k) {
+				dropped = append(dropped, droppedValues(e, k[i], pointer(ptr, strconv.Itoa(i)))...)
+			}
+		}
+	}
+	return dropped
+}
+
+// emptyValue returns true for null, false, zero, empty strings and empty objects and arrays
+func emptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// copyValue returns a deep copy of a value decoded from JSON
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = copyValue(e)
+		}
+		return l
+	}
+	return v
+}
+
+// pointerTokens splits a JSON Pointer into its unescaped reference tokens
+func pointerTokens(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("%s is not a JSON Pointer", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = unescapePointer(t)
+	}
+	return tokens, nil
+}
+
+// arrayIndex returns the index of an array that a token names, which may be its length when appending
+func arrayIndex(token string, length int, appending bool) (int, error) {
+	if appending && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || token != strconv.Itoa(i) {
+		return 0, fmt.Errorf("%s is not an array index", token)
+	}
+	if i > length || i == length && !appending {
+		return 0, fmt.Errorf("index %d is out of range", i)
+	}
+	return i, nil
+}
+
+// valueAt returns the value a JSON Pointer points at
+func valueAt(doc interface{}, ptr string) (interface{}, error) {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return nil, err
+	}
+	v := doc
+	for j, t := range tokens {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = node[t]; !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer("", tokens[:j+1]...))
+			}
+		case []interface{}:
+			i, err := arrayIndex(t, len(node), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", pointer("", tokens[:j+1]...), err)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("%s does not exist", pointer("", tokens[:j+1]...))
+		}
+	}
+	return v, nil
+}
+
+// setValue replaces the value at reference tokens, which must exist, and returns the document
+func setValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := valueAt(doc, pointer("", tokens[:len(tokens)-1]...))
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		i, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	}
+	return doc, nil
+}
+
+// addValue adds a value at a JSON Pointer as the add operation of JSON Patch does, and returns the document
+func addValue(doc interface{}, ptr string, value interface{}) (interface{}, error) {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parentTokens, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, err := valueAt(doc, pointer("", parentTokens...))
+	if err != nil {
+		return nil, err
+	}
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", ptr, err)
+		}
+		grown := make([]interface{}, 0, len(node)+1)
+		grown = append(append(append(grown, node[:i]...), value), node[i:]...)
+		return setValue(doc, parentTokens, grown)
+	}
+	return nil, fmt.Errorf("%s is not an object or an array", pointer("", parentTokens...))
+}
+
+// removeValue removes the value at a JSON Pointer, which must exist, and returns the document and the value
+func removeValue(doc interface{}, ptr string) (interface{}, interface{}, error) {
+	value, err := valueAt(doc, ptr)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, _ := pointerTokens(ptr)
+	if len(tokens) == 0 {
+		return nil, value, nil
+	}
+	parentTokens, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, _ := valueAt(doc, pointer("", parentTokens...))
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		delete(node, last)
+	case []interface{}:
+		i, _ := arrayIndex(last, len(node), false)
+		shrunk := append(append(make([]interface{}, 0, len(node)-1), node[:i]...), node[i+1:]...)
+		doc, err = setValue(doc, parentTokens, shrunk)
+	}
+	return doc, value, err
+}
@@ -0,0 +1,169 @@
+package swagger2
+
+import (
+	"strings"
+	"testing"
+)
+
+const patchVendor = `
+swagger: "2.0"
+info: {title: Vendor, version: "1.0"}
+tags:
+  - {name: pets}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, type: string}
+      responses:
+        200: {description: OK, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+    post:
+      tags: [pets]
+      responses:
+        201: {description: Created.}
+  /internal/stats:
+    get:
+      x-internal: true
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+`
+
+func TestJsonPatch(t *testing.T) {
+	swag, err := LoadYaml([]byte(patchVendor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := JsonPatch(swag, []byte(`[
+		{"op": "test", "path": "/paths/~1pets/get/parameters/0/name", "value": "limit"},
+		{"op": "replace", "path": "/paths/~1pets/get/parameters/0/type", "value": "integer"},
+		{"op": "add", "path": "/paths/~1pets/get/parameters/-", "value": {"name": "offset", "in": "query", "type": "integer"}},
+		{"op": "add", "path": "/definitions/Pet/required", "value": ["name"]},
+		{"op": "copy", "from": "/definitions/Pet", "path": "/definitions/NewPet"},
+		{"op": "move", "from": "/paths/~1internal~1stats", "path": "/paths/~1stats"},
+		{"op": "remove", "path": "/paths/~1pets/post"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := patched.Paths["/pets"].Get.Parameters
+	if len(params) != 2 || params[0].Type != "integer" || params[1].Name != "offset" || patched.Paths["/pets"].Post != nil {
+		t.Errorf("unexpected operation %+v", patched.Paths["/pets"])
+	}
+	if _, ok := patched.Paths["/internal/stats"]; ok || patched.Paths["/stats"].Get == nil || len(patched.Definitions["NewPet"].Required) != 1 {
+		t.Errorf("unexpected document %+v", patched)
+	}
+	if swag.Paths["/pets"].Get.Parameters[0].Type != "string" {
+		t.Error("the document was modified")
+	}
+
+	tests := []struct {
+		patch, err string
+	}{
+		{`[{"op": "replace", "path": "/paths/~1cats/get/summary", "value": "Cats"}]`, "patch operation 0 (replace /paths/~1cats/get/summary): /paths/~1cats does not exist"},
+		{`[{"op": "test", "path": "/info/version", "value": "2.0"}]`, "patch operation 0 (test /info/version): value is 1.0, not 2.0"},
+		{`- {op: add, path: /paths/~1pets/get/parameters/5, value: {}}`, "patch operation 0 (add /paths/~1pets/get/parameters/5): /paths/~1pets/get/parameters/5: index 5 is out of range"},
+		{`[{"op": "move", "from": "/paths", "path": "/paths/x"}]`, "patch operation 0 (move /paths/x): cannot move a value into itself"},
+		{`[{"op": "add", "path": "/info/title"}]`, "patch operation 0 (add /info/title): value is required"},
+		{`[{"op": "rename", "path": "/info"}]`, "patch operation 0 (rename /info): unknown operation"},
+		{`{"op": "remove", "path": "/info"}`, "a JSON Patch must be a list of operations"},
+		{`[{"op": "add", "path": "/info/x-logo", "value": {"url": "logo.png"}}, {"op": "add", "path": "/paths/~1pets/get/descripton", "value": "Pets"}]`,
+			"/info/x-logo is not part of a Swagger document\n/paths/~1pets/get/descripton is not part of a Swagger document"},
+	}
+	for _, test := range tests {
+		if _, err := JsonPatch(swag, []byte(test.patch)); err == nil || err.Error() != test.err {
+			t.Errorf("%s: unexpected error %v", test.patch, err)
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	swag, err := LoadYaml([]byte(patchVendor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := MergePatch(swag, []byte(`
+info: {description: Our copy of the vendor API.}
+paths:
+  /internal/stats: null
+definitions:
+  Pet:
+    properties:
+      name: {description: The name of the pet.}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := patched.Definitions["Pet"].Properties["name"]
+	if patched.Info.Title != "Vendor" || patched.Info.Description == "" || len(patched.Paths) != 1 || name.Type != "string" || name.Description == "" {
+		t.Errorf("unexpected document %+v", patched)
+	}
+}
+
+func TestApplyOverlay(t *testing.T) {
+	swag, err := LoadYaml([]byte(patchVendor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := LoadOverlay([]byte(`
+overlay: 1.0.0
+info: {title: Our fixes, version: "1"}
+actions:
+  - target: $.paths[?(@.get.x-internal == true)]
+    remove: true
+  - target: $.paths['/pets'].*
+    update:
+      description: Needs an API key.
+      x-audience: public
+  - target: $.paths['/pets'].get.parameters[?(@.name == 'limit')]
+    update: {type: integer, maximum: 100}
+  - target: $.tags
+    update: {name: admin}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	patched, err := ApplyOverlay(swag, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := patched.Paths["/pets"].Get
+	if get.Description != "Needs an API key." || get.Extensions.String("x-audience") != "public" || patched.Paths["/pets"].Post.Description == "" {
+		t.Errorf("unexpected operation %+v", get)
+	}
+	if limit := get.Parameters[0]; limit.Type != "integer" || limit.Maximum == nil || *limit.Maximum != 100 {
+		t.Errorf("unexpected parameter %+v", limit)
+	}
+	if _, ok := patched.Paths["/internal/stats"]; ok || len(patched.Tags) != 2 {
+		t.Errorf("unexpected document %+v", patched)
+	}
+
+	removal := &Overlay{Actions: []OverlayAction{{Target: "$.paths['/pets'].get.parameters[1,0,1]", Remove: true}}}
+	two, err := JsonPatch(swag, []byte(`[{"op": "add", "path": "/paths/~1pets/get/parameters/-", "value": {"name": "offset", "in": "query", "type": "integer"}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched, err = ApplyOverlay(two, removal); err != nil {
+		t.Fatal(err)
+	}
+	if params := patched.Paths["/pets"].Get.Parameters; len(params) != 0 {
+		t.Errorf("unexpected parameters %+v", params)
+	}
+
+	overlay.Actions = append(overlay.Actions, OverlayAction{Target: "$.paths['/cats'].get", Update: map[string]interface{}{"summary": "Cats"}}, OverlayAction{Target: "$.info.title", Update: "Ours"}, OverlayAction{Target: "$.paths[", Remove: true}, OverlayAction{Target: "$.paths['/pets'].get", Description: "Nothing to do"})
+	_, err = ApplyOverlay(swag, overlay)
+	want := []string{
+		"action 4: $.paths['/cats'].get matches nothing",
+		"action 5: /info/title is not an object or an array",
+		"action 6: jsonpath $.paths[: at 8: selector expected",
+		"action 7: an update or remove is required",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package swagger2
+
+import (
+	"sort"
+	"strings"
+)
+
+// Methods lists the HTTP methods that a PathItem can describe, in the order they are declared.
+var Methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// Operation returns the operation for the given lower-case HTTP method, or nil if there isn't one.
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "post":
+		return p.Post
+	case "delete":
+		return p.Delete
+	case "options":
+		return p.Options
+	case "head":
+		return p.Head
+	case "patch":
+		return p.Patch
+	}
+	return nil
+}
+
+// SetOperation stores the operation for the given lower-case HTTP method. Unknown methods are ignored.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch method {
+	case "get":
+		p.Get = op
+	case "put":
+		p.Put = op
+	case "post":
+		p.Post = op
+	case "delete":
+		p.Delete = op
+	case "options":
+		p.Options = op
+	case "head":
+		p.Head = op
+	case "patch":
+		p.Patch = op
+	}
+}
+
+// BaseURL returns the URL the API is served at, made of the first of its schemes, preferring https, its host and its
+// base path. The host defaults to localhost, since the document then describes the host that serves it.
+func (s *Swagger) BaseURL() string {
+	scheme := "http"
+	for _, sc := range s.Schemes {
+		if sc == "https" {
+			scheme = sc
+			break
+		}
+	}
+	if scheme != "https" && len(s.Schemes) > 0 {
+		scheme = s.Schemes[0]
+	}
+	host := s.Host
+	if host == "" {
+		host = "localhost"
+	}
+	return scheme + "://" + host + strings.TrimRight(s.BasePath, "/")
+}
+
+// ResolveParameter returns the shared parameter that a parameter refers to, or the parameter itself if it is not a
+// reference to a shared parameter of the document.
+func (s *Swagger) ResolveParameter(p Parameter) Parameter {
+	if section, name := ParseRef(p.Ref); section == "parameters" {
+		if shared, ok := s.Parameters[name]; ok {
+			return shared
+		}
+	}
+	return p
+}
+
+// OperationParameters returns the parameters of an operation on a path followed by those of its path that it does
+// not override, with references to the parameters of the document resolved. An operation overrides the path
+// parameters with the same name and location, and the body parameter of the path if it has one. They are sorted
+// by location, in path, query, header, formData and body order, with path parameters in the order they appear in
+// the path.
+func (s *Swagger) OperationParameters(path string, item *PathItem, op *Operation) []Parameter {
+	all := make([]Parameter, 0, len(op.Parameters)+len(item.Parameters))
+	overridden := make(map[string]bool)
+	body := false
+	for _, p := range op.Parameters {
+		p = s.ResolveParameter(p)
+		overridden[p.In+":"+p.Name] = true
+		body = body || p.In == "body"
+		all = append(all, p)
+	}
+	for _, p := range item.Parameters {
+		// there is only one body, so that of the operation wins whatever its name
+		if p = s.ResolveParameter(p); !overridden[p.In+":"+p.Name] && !(body && p.In == "body") {
+			all = append(all, p)
+		}
+	}
+	order := map[string]int{"path": 0, "query": 1, "header": 2, "formData": 3, "body": 4}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].In == "path" && all[j].In == "path" {
+			return strings.Index(path, "{"+all[i].Name+"}") < strings.Index(path, "{"+all[j].Name+"}")
+		}
+		return order[all[i].In] < order[all[j].In]
+	})
+	return all
+}
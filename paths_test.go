@@ -0,0 +1,59 @@
+package swagger2
+
+import (
+	"reflect"
+	"testing"
+)
+
+const pathsDoc = `
+swagger: "2.0"
+info: {title: Paths, version: "1"}
+schemes: [http, https]
+host: api.example.com
+basePath: /v2/
+parameters:
+  limit: {name: limit, in: query, type: integer, default: 20}
+paths:
+  /owners/{owner}/pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+      - {name: owner, in: path, required: true, type: string}
+      - {name: verbose, in: query, type: boolean}
+    get:
+      parameters:
+        - $ref: "#/parameters/limit"
+        - {name: verbose, in: query, type: string}
+        - {name: X-Trace, in: header, type: string}
+      responses:
+        200: {description: OK}
+`
+
+func TestBaseURL(t *testing.T) {
+	swag, err := LoadYaml([]byte(pathsDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url := swag.BaseURL(); url != "https://api.example.com/v2" {
+		t.Errorf("unexpected base URL %s", url)
+	}
+	if url := (&Swagger{Schemes: []string{"ws"}}).BaseURL(); url != "ws://localhost" {
+		t.Errorf("unexpected base URL %s", url)
+	}
+}
+
+func TestOperationParameters(t *testing.T) {
+	swag, err := LoadYaml([]byte(pathsDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/owners/{owner}/pets/{id}"
+	item := swag.Paths[path]
+	names := make([]string, 0)
+	for _, p := range swag.OperationParameters(path, &item, item.Get) {
+		names = append(names, p.In+":"+p.Name+":"+p.Type)
+	}
+	want := []string{"path:owner:string", "path:id:string", "query:limit:integer", "query:verbose:string", "header:X-Trace:string"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected parameters %v, got %v", want, names)
+	}
+}
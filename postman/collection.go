@@ -0,0 +1,382 @@
+// Package postman exports Swagger 2 documents as Postman collections, in version 2.1 of the collection format.
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// SchemaURL identifies version 2.1 of the collection format.
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Options configures an export.
+type Options struct {
+	Name    string // Name of the collection; defaults to the title of the document
+	BaseURL string // Value of the baseUrl variable; defaults to the URL made of the schemes, host and base path
+}
+
+// collection is a Postman collection
+type collection struct {
+	Info     info          `json:"info"`
+	Item     []interface{} `json:"item"`
+	Auth     *auth         `json:"auth,omitempty"`
+	Variable []*variable   `json:"variable,omitempty"`
+}
+
+type info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// folder holds the requests of a tag
+type folder struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Item        []interface{} `json:"item"`
+}
+
+// item is a request with the responses saved for it, of which there are none
+type item struct {
+	Name     string        `json:"name"`
+	Request  *request      `json:"request"`
+	Response []interface{} `json:"response"`
+}
+
+type request struct {
+	Method      string       `json:"method"`
+	Header      []*variable  `json:"header"`
+	URL         *url         `json:"url"`
+	Body        *requestBody `json:"body,omitempty"`
+	Auth        *auth        `json:"auth,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+type url struct {
+	Raw      string      `json:"raw"`
+	Host     []string    `json:"host"`
+	Path     []string    `json:"path,omitempty"`
+	Query    []*variable `json:"query,omitempty"`
+	Variable []*variable `json:"variable,omitempty"`
+}
+
+// variable is a key and value, used for variables, headers, query parameters and form fields
+type variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value,omitempty"`
+	Src         string `json:"src,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type requestBody struct {
+	Mode       string       `json:"mode"`
+	Raw        string       `json:"raw,omitempty"`
+	URLEncoded []*variable  `json:"urlencoded,omitempty"`
+	FormData   []*variable  `json:"formdata,omitempty"`
+	Options    *bodyOptions `json:"options,omitempty"`
+}
+
+type bodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// auth configures how Postman authorizes requests, with the settings of the type in the field of the same name
+type auth struct {
+	Type   string      `json:"type"`
+	Basic  []*variable `json:"basic,omitempty"`
+	APIKey []*variable `json:"apikey,omitempty"`
+	OAuth2 []*variable `json:"oauth2,omitempty"`
+}
+
+// exporter builds a collection
+type exporter struct {
+	swag  *swagger2.Swagger
+	lossy []error
+	vars  map[string]bool // the credential variables the collection declares
+}
+
+// Collection exports a document as a Postman collection. There is a folder for each tag, in the order the tags are
+// declared, with a request for each operation; operations without tags are at the top of the collection. URLs
+// start with the baseUrl variable. Path parameters become variables of the URL, and query and header parameters
+// are listed with sample values, disabled when they are optional. Request bodies are samples made from their
+// schemas. Security definitions become the authorization of the collection and of the operations that need
+// something else, with the credentials in variables named after the definition: {{name}} for an API key,
+// {{nameUsername}} and {{namePassword}} for basic authentication and {{nameToken}} for OAuth2. The errors list what
+// the collection could not express.
+func Collection(swag *swagger2.Swagger, opts Options) ([]byte, []error) {
+	x := &exporter{swag: swag, vars: make(map[string]bool)}
+	c := &collection{
+		Info: info{Name: opts.Name, Description: swag.Info.Description, Version: swag.Info.Version, Schema: SchemaURL},
+		Item: make([]interface{}, 0),
+	}
+	if c.Info.Name == "" {
+		c.Info.Name = swag.Info.Title
+	}
+	base := opts.BaseURL
+	if base == "" {
+		base = swag.BaseURL()
+	}
+	c.Variable = append(c.Variable, &variable{Key: "baseUrl", Value: strings.TrimRight(base, "/"), Type: "string"})
+	c.Auth = x.auth("security", swag.Security)
+
+	folders := make(map[string]*folder)
+	order := make([]string, 0)
+	for _, t := range swag.Tags {
+		if folders[t.Name] == nil {
+			folders[t.Name] = &folder{Name: t.Name, Description: t.Description, Item: make([]interface{}, 0)}
+			order = append(order, t.Name)
+		}
+	}
+	paths := make([]string, 0, len(swag.Paths))
+	for p := range swag.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	untagged := make([]interface{}, 0)
+	for _, p := range paths {
+		pi := swag.Paths[p]
+		for _, method := range swagger2.Methods {
+			op := pi.Operation(method)
+			if op == nil {
+				continue
+			}
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, x.item(p, method, &pi, op))
+			}
+			for _, t := range op.Tags {
+				if folders[t] == nil {
+					folders[t] = &folder{Name: t, Item: make([]interface{}, 0)}
+					order = append(order, t)
+				}
+				folders[t].Item = append(folders[t].Item, x.item(p, method, &pi, op))
+			}
+		}
+	}
+	for _, t := range order {
+		if len(folders[t].Item) > 0 {
+			c.Item = append(c.Item, folders[t])
+		}
+	}
+	c.Item = append(c.Item, untagged...)
+
+	names := make([]string, 0, len(x.vars))
+	for n := range x.vars {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		c.Variable = append(c.Variable, &variable{Key: n, Type: "string"})
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, append(x.lossy, err)
+	}
+	return append(b, '\n'), x.lossy
+}
+
+// unsupported records something the collection cannot express
+func (x *exporter) unsupported(where, format string, args ...interface{}) {
+	x.lossy = append(x.lossy, fmt.Errorf(where+": "+format, args...))
+}
+
+// item builds the request for an operation
+func (x *exporter) item(path, method string, pi *swagger2.PathItem, op *swagger2.Operation) *item {
+	where := "paths." + path + "." + method
+	name := op.Summary
+	if name == "" {
+		name = op.OperationId
+	}
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+	r := &request{Method: strings.ToUpper(method), Header: make([]*variable, 0), Description: op.Description}
+	if op.Deprecated {
+		r.Description = strings.TrimSpace("Deprecated. " + r.Description)
+	}
+	u := &url{Host: []string{"{{baseUrl}}"}}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		// Postman only knows variables that are a whole segment of the path
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && strings.Count(seg, "{") == 1 {
+			seg = ":" + seg[1:len(seg)-1]
+		}
+		u.Path = append(u.Path, seg)
+	}
+	consumes := op.Consumes
+	if consumes == nil {
+		consumes = x.swag.Consumes
+	}
+	defs := x.swag.Definitions
+	for _, p := range x.swag.OperationParameters(path, pi, op) {
+		required := p.Required != nil && *p.Required
+		switch p.In {
+		case "path":
+			value := strings.Join(p.EncodeValue(defs.SampleItems(&p.ItemsDef)), ",")
+			u.Variable = append(u.Variable, &variable{Key: p.Name, Value: value, Description: p.Description})
+		case "query":
+			for _, v := range p.EncodeValue(defs.SampleItems(&p.ItemsDef)) {
+				u.Query = append(u.Query, &variable{Key: p.Name, Value: v, Description: p.Description, Disabled: !required})
+			}
+		case "header":
+			value := strings.Join(p.EncodeValue(defs.SampleItems(&p.ItemsDef)), ",")
+			r.Header = append(r.Header, &variable{Key: p.Name, Value: value, Description: p.Description, Disabled: !required})
+		case "formData":
+			if r.Body == nil {
+				r.Body = &requestBody{Mode: "urlencoded"}
+				if lists.Contains(consumes, "multipart/form-data") {
+					r.Body.Mode = "formdata"
+				}
+			}
+			if p.Type == "file" {
+				if r.Body.Mode != "formdata" {
+					x.unsupported(where+".parameters."+p.Name, "file parameters need multipart/form-data")
+				}
+				r.Body.FormData = append(r.Body.FormData, &variable{Key: p.Name, Type: "file", Description: p.Description, Disabled: !required})
+				continue
+			}
+			for _, v := range p.EncodeValue(defs.SampleItems(&p.ItemsDef)) {
+				field := &variable{Key: p.Name, Value: v, Type: "text", Description: p.Description, Disabled: !required}
+				if r.Body.Mode == "formdata" {
+					r.Body.FormData = append(r.Body.FormData, field)
+				} else {
+					r.Body.URLEncoded = append(r.Body.URLEncoded, field)
+				}
+			}
+		case "body":
+			r.Body = x.body(where, &p, consumes)
+			contentType := "application/json"
+			if len(consumes) > 0 && !lists.Contains(consumes, contentType) {
+				contentType = consumes[0]
+			}
+			r.Header = append(r.Header, &variable{Key: "Content-Type", Value: contentType})
+		}
+	}
+	if r.Body != nil && r.Body.Mode == "urlencoded" {
+		r.Header = append(r.Header, &variable{Key: "Content-Type", Value: "application/x-www-form-urlencoded"})
+	}
+	u.Raw = raw(u)
+	r.URL = u
+	if op.Security != nil {
+		r.Auth = x.auth(where+".security", op.Security)
+	}
+	return &item{Name: name, Request: r, Response: make([]interface{}, 0)}
+}
+
+// body builds a raw body with a sample of the schema of a body parameter
+func (x *exporter) body(where string, p *swagger2.Parameter, consumes []string) *requestBody {
+	b := &requestBody{Mode: "raw", Options: &bodyOptions{}}
+	b.Options.Raw.Language = "json"
+	if len(consumes) > 0 && !lists.Contains(consumes, "application/json") {
+		b.Options.Raw.Language = "text"
+		if strings.Contains(consumes[0], "xml") {
+			b.Options.Raw.Language = "xml"
+			x.unsupported(where+".parameters."+p.Name, "the sample body is JSON, the operation consumes %s", consumes[0])
+		}
+	}
+	if p.Schema == nil {
+		return b
+	}
+	if v := x.swag.Definitions.Sample(p.Schema); v != nil {
+		if raw, err := json.MarshalIndent(v, "", "  "); err == nil {
+			b.Raw = string(raw)
+		}
+	}
+	return b
+}
+
+// raw writes the URL as it appears in Postman, with the query parameters that are enabled
+func raw(u *url) string {
+	s := u.Host[0]
+	for _, seg := range u.Path {
+		s += "/" + seg
+	}
+	query := make([]string, 0)
+	for _, q := range u.Query {
+		if !q.Disabled {
+			query = append(query, q.Key+"="+q.Value)
+		}
+	}
+	if len(query) > 0 {
+		s += "?" + strings.Join(query, "&")
+	}
+	return s
+}
+
+// auth configures the authorization for security requirements. Postman authorizes a request in one way only, so
+// only the first scheme of the first requirement is used. No requirements means no authorization.
+func (x *exporter) auth(where string, reqs []swagger2.Security) *auth {
+	if reqs == nil {
+		return nil
+	}
+	if len(reqs) == 0 || len(reqs[0]) == 0 {
+		return &auth{Type: "noauth"}
+	}
+	names := make([]string, 0, len(reqs[0]))
+	for n := range reqs[0] {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(reqs) > 1 || len(names) > 1 {
+		x.unsupported(where, "only the %s security scheme is used, Postman authorizes a request in one way", names[0])
+	}
+	name := names[0]
+	def, ok := x.swag.SecurityDefinitions[name]
+	if !ok {
+		x.unsupported(where, "unknown security definition %s", name)
+		return nil
+	}
+	switch def.Type {
+	case "basic":
+		x.vars[name+"Username"], x.vars[name+"Password"] = true, true
+		return &auth{Type: "basic", Basic: []*variable{
+			{Key: "username", Value: "{{" + name + "Username}}", Type: "string"},
+			{Key: "password", Value: "{{" + name + "Password}}", Type: "string"},
+		}}
+	case "apiKey":
+		x.vars[name] = true
+		in := "header"
+		if def.In == "query" {
+			in = "query"
+		}
+		return &auth{Type: "apikey", APIKey: []*variable{
+			{Key: "key", Value: def.Name, Type: "string"},
+			{Key: "value", Value: "{{" + name + "}}", Type: "string"},
+			{Key: "in", Value: in, Type: "string"},
+		}}
+	case "oauth2":
+		x.vars[name+"Token"] = true
+		settings := []*variable{
+			{Key: "accessToken", Value: "{{" + name + "Token}}", Type: "string"},
+			{Key: "tokenType", Value: "Bearer", Type: "string"},
+			{Key: "addTokenTo", Value: "header", Type: "string"},
+		}
+		grants := map[string]string{"implicit": "implicit", "password": "password_credentials", "application": "client_credentials", "accessCode": "authorization_code"}
+		if grant := grants[def.Flow]; grant != "" {
+			settings = append(settings, &variable{Key: "grant_type", Value: grant, Type: "string"})
+		}
+		if def.AuthorizationUrl != "" {
+			settings = append(settings, &variable{Key: "authUrl", Value: def.AuthorizationUrl, Type: "string"})
+		}
+		if def.TokenUrl != "" {
+			settings = append(settings, &variable{Key: "accessTokenUrl", Value: def.TokenUrl, Type: "string"})
+		}
+		if scopes := reqs[0][name]; len(scopes) > 0 {
+			settings = append(settings, &variable{Key: "scope", Value: strings.Join(scopes, " "), Type: "string"})
+		}
+		return &auth{Type: "oauth2", OAuth2: settings}
+	}
+	x.unsupported(where, "security definitions of type %s", def.Type)
+	return nil
+}
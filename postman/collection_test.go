@@ -0,0 +1,184 @@
+package postman
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestExamples(t *testing.T) {
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			out, _ := Collection(swag, Options{})
+			var c map[string]interface{}
+			if err := json.Unmarshal(out, &c); err != nil || c["info"] == nil {
+				t.Errorf("%s: unexpected output %s\n%s", file, err, out)
+			}
+		}
+	}
+}
+
+const shelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0", description: Adopt a pet.}
+host: shelter.example.com
+basePath: /v1
+schemes: [http, https]
+consumes: [application/json]
+tags:
+  - {name: pets, description: Pets waiting for a home.}
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+  login: {type: basic}
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://shelter.example.com/auth
+    tokenUrl: https://shelter.example.com/token
+    scopes: {"write:pets": Change pets}
+security:
+  - key: []
+parameters:
+  trace: {name: X-Trace, in: header, type: string, description: Trace id.}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      summary: List pets
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat, dog], required: true}
+        - {name: ids, in: query, type: array, items: {type: integer}, collectionFormat: multi}
+        - {name: fields, in: query, type: array, items: {type: string}, collectionFormat: pipes, default: [name, tag]}
+        - $ref: "#/parameters/trace"
+      responses:
+        200: {description: The pets.}
+    post:
+      tags: [pets]
+      operationId: addPet
+      security:
+        - oauth: ["write:pets"]
+      parameters:
+        - {name: pet, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201: {description: Created.}
+  /pets/{petId}/photo:
+    parameters:
+      - {name: petId, in: path, required: true, type: integer, format: int64}
+    put:
+      tags: [photos]
+      deprecated: true
+      security: []
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: photo, in: formData, type: file, required: true}
+        - {name: caption, in: formData, type: string}
+      responses:
+        200: {description: OK}
+  /health:
+    get:
+      security:
+        - login: []
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      id: {type: integer, format: int64, readOnly: true}
+      name: {type: string, example: Rex}
+      born: {type: string, format: date}
+      tags: {type: array, items: {type: string}}
+      parent: {$ref: "#/definitions/Pet"}
+`
+
+func TestCollection(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, errs := Collection(swag, Options{})
+	if len(errs) > 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	out := squash(string(b))
+	for _, want := range []string{
+		`"name": "Pet Shelter",`,
+		`"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"`,
+		"\"key\": \"baseUrl\",\n      \"value\": \"https://shelter.example.com/v1\"",
+		"\"auth\": {\n    \"type\": \"apikey\",\n    \"apikey\": [\n      {\n        \"key\": \"key\",\n        \"value\": \"X-Key\",",
+		`"value": "{{key}}",`,
+		"\"name\": \"pets\",\n      \"description\": \"Pets waiting for a home.\",",
+		`"raw": "{{baseUrl}}/pets?kind=cat",`,
+		"\"key\": \"kind\",\n                \"value\": \"cat\"\n              }",
+		"\"key\": \"ids\",\n                \"value\": \"0\",\n                \"disabled\": true",
+		"\"key\": \"fields\",\n                \"value\": \"name|tag\",\n                \"disabled\": true",
+		"\"key\": \"X-Trace\",\n                \"value\": \"string\",\n                \"description\": \"Trace id.\",\n                \"disabled\": true",
+		`"name": "addPet",`,
+		`"raw": "{\n  \"born\": \"1970-01-01\",\n  \"name\": \"Rex\",\n  \"parent\": null,\n  \"tags\": [\n    \"string\"\n  ]\n}"`,
+		"\"type\": \"oauth2\",",
+		"\"key\": \"grant_type\",\n                  \"value\": \"authorization_code\",",
+		"\"key\": \"scope\",\n                  \"value\": \"write:pets\",",
+		`"raw": "{{baseUrl}}/pets/:petId/photo",`,
+		"\"key\": \"petId\",\n                \"value\": \"0\"",
+		"\"mode\": \"formdata\",",
+		"\"key\": \"photo\",\n                  \"type\": \"file\"\n",
+		"\"type\": \"noauth\"",
+		`"description": "Deprecated."`,
+		"\"name\": \"GET /health\",",
+		"\"type\": \"basic\",",
+		`"value": "{{loginUsername}}",`,
+		"\"key\": \"oauthToken\",\n      \"type\": \"string\"",
+	} {
+		if !strings.Contains(out, squash(want)) {
+			t.Errorf("expected %q in:\n%s", want, b)
+		}
+	}
+	if strings.Index(out, `"name":"pets"`) > strings.Index(out, `"name":"photos"`) || strings.Index(out, `"name":"photos"`) > strings.Index(out, `"name":"GET /health"`) {
+		t.Errorf("unexpected order of items:\n%s", out)
+	}
+	if strings.Contains(out, `"id":`) {
+		t.Errorf("read only property in the sample body:\n%s", out)
+	}
+}
+
+// squash drops the indentation of JSON, so that what is expected does not depend on how deep it is
+func squash(s string) string {
+	return strings.ReplaceAll(indentation.ReplaceAllString(s, ""), `": `, `":`)
+}
+
+var indentation = regexp.MustCompile(`\n\s*`)
+
+func TestLossy(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	swag.Security = []swagger2.Security{{"key": {}, "login": {}}}
+	_, errs := Collection(swag, Options{Name: "Shelter", BaseURL: "http://localhost:8080/"})
+	if text := swagger2.ErrorList(errs).String(); !strings.Contains(text, "security: only the key security scheme is used") {
+		t.Errorf("unexpected errors:\n%s", text)
+	}
+}
@@ -0,0 +1,41 @@
+package protogen
+
+import (
+	"encoding/json"
+)
+
+// Lock records the numbers an export gave to fields and enum values, so that exporting a later version of the
+// document keeps them. The numbers of fields that have since been removed stay reserved.
+type Lock struct {
+	Messages map[string]map[string]int `json:"messages,omitempty"` // Message name to field name to number
+	Enums    map[string]map[string]int `json:"enums,omitempty"`    // Enum name to value to number
+}
+
+// NewLock returns an empty lock.
+func NewLock() *Lock {
+	return &Lock{Messages: make(map[string]map[string]int), Enums: make(map[string]map[string]int)}
+}
+
+// LoadLock reads a lock from JSON.
+func LoadLock(b []byte) (*Lock, error) {
+	l := NewLock()
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, err
+	}
+	if l.Messages == nil {
+		l.Messages = make(map[string]map[string]int)
+	}
+	if l.Enums == nil {
+		l.Enums = make(map[string]map[string]int)
+	}
+	return l, nil
+}
+
+// Marshal writes the lock as indented JSON.
+func (l *Lock) Marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
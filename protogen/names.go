@@ -0,0 +1,120 @@
+package protogen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// words splits a name into words on punctuation and on changes of case
+func words(name string) []string {
+	list := make([]string, 0)
+	word := make([]rune, 0)
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			list = append(list, string(word))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return list
+}
+
+// messageName turns an arbitrary name into a message, enum, service or rpc name, such as "pet_status" into
+// "PetStatus"
+func messageName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		b.WriteString(strings.ToUpper(w[:1]) + strings.ToLower(w[1:]))
+	}
+	return leadingLetter(b.String())
+}
+
+// fieldName turns an arbitrary name into a field name, such as "petId" into "pet_id"
+func fieldName(name string) string {
+	list := words(name)
+	for i, w := range list {
+		list[i] = strings.ToLower(w)
+	}
+	return strings.ToLower(leadingLetter(strings.Join(list, "_")))
+}
+
+// constantName turns an arbitrary name into an enum value name, such as "in stock" into "IN_STOCK"
+func constantName(name string) string {
+	list := words(name)
+	for i, w := range list {
+		list[i] = strings.ToUpper(w)
+	}
+	return strings.Join(list, "_")
+}
+
+// leadingLetter makes sure an identifier starts with a letter
+func leadingLetter(id string) string {
+	if id == "" {
+		return "X"
+	}
+	if !unicode.IsLetter([]rune(id)[0]) {
+		return "X" + id
+	}
+	return id
+}
+
+// jsonName returns the JSON name protoc derives from a field name, such as "petId" for "pet_id"
+func jsonName(field string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range field {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// namer hands out names that are unique within a scope
+type namer map[string]bool
+
+// unique returns the name, adding a number to it if it is already taken
+func (n namer) unique(id string) string {
+	candidate := id
+	for i := 2; n[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", id, i)
+	}
+	n[candidate] = true
+	return candidate
+}
+
+// comment formats a description as proto comment lines
+func comment(indent, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(indent+"// "+strings.TrimRight(line, " \t\r"), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,720 @@
+// Package protogen exports Swagger 2 documents as Protocol Buffers definitions, with a gRPC service whose rpcs
+// carry google.api.http options that mirror the operations.
+package protogen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Options controls the export.
+type Options struct {
+	Package   string // The proto package; defaults to "api"
+	GoPackage string // The go_package option, left out if empty
+	Service   string // The name of the service; defaults to the document's title followed by "Service"
+	Lock      *Lock  // Numbers kept from an earlier export; the numbers given to new fields are added to it
+}
+
+// Proto exports a document as a proto3 file. Each definition becomes a message, or an enum if it is a string
+// enum, and each operation an rpc of the service. Field numbers come from the x-proto-field extension of a
+// property, then from the lock, and are otherwise given in order after the highest number used so far, so that
+// they stay the same as the document changes. The returned errors describe anything that could not be
+// represented exactly; the file is still usable when they are present.
+func Proto(swag *swagger2.Swagger, opts Options) ([]byte, []error) {
+	if opts.Package == "" {
+		opts.Package = "api"
+	}
+	e := &exporter{
+		swag:    swag,
+		opts:    opts,
+		errs:    make([]error, 0),
+		imports: make(map[string]bool),
+		ids:     make(namer),
+		names:   make(map[string]string),
+	}
+	for _, name := range e.definitionNames() {
+		e.names[name] = e.ids.unique(messageName(name))
+	}
+	for _, name := range e.definitionNames() {
+		def := e.swag.Definitions[name]
+		e.definition(name, &def)
+	}
+	e.service()
+	return e.source(), e.errs
+}
+
+// exporter holds the state of an export
+type exporter struct {
+	swag    *swagger2.Swagger
+	opts    Options
+	errs    []error
+	imports map[string]bool
+	ids     namer             // names in use at the top level of the file
+	names   map[string]string // definition name to message or enum name
+	order   []interface{}     // top level messages and enums, in the order they are written
+	rpcs    bytes.Buffer
+}
+
+// message is a message with its fields and nested types
+type message struct {
+	name     string // full name within the package, such as "Pet.Category", which the lock is keyed by
+	local    string // name within the enclosing message
+	doc      string
+	fields   []*field
+	ids      namer // field names
+	scope    namer // names of nested types
+	nested   []*message
+	enums    []*enum
+	reserved []int
+	removed  []string // names of fields that are in the lock but no longer in the message
+}
+
+// field is a message field
+type field struct {
+	name   string
+	json   string // the JSON name, if it is not the one protoc derives from the field name
+	typ    string
+	label  string // "repeated", "optional" or ""
+	doc    string
+	number int
+	fixed  int // the number given by the x-proto-field extension
+}
+
+// enum is an enum with its values
+type enum struct {
+	name   string
+	local  string
+	doc    string
+	values []*enumValue
+}
+
+// enumValue is the constant for a value of an enum
+type enumValue struct {
+	value  string // the value in the document
+	name   string
+	number int
+}
+
+// pathParam matches a templated path segment
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// kinds of field type, which decide the label of a field
+const (
+	scalar = iota
+	composite
+	repeated
+	mapped
+)
+
+// lossy records something that could not be exported exactly
+func (e *exporter) lossy(where, format string, args ...interface{}) {
+	e.errs = append(e.errs, fmt.Errorf(where+": "+format, args...))
+}
+
+// definitionNames returns the definition names in sorted order
+func (e *exporter) definitionNames() []string {
+	names := make([]string, 0, len(e.swag.Definitions))
+	for n := range e.swag.Definitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// definition exports a definition as a top level message or enum
+func (e *exporter) definition(name string, s *swagger2.Schema) {
+	where := "definitions." + name
+	id := e.names[name]
+	doc := s.Description
+	if doc == "" {
+		doc = s.Title
+	}
+	switch {
+	case isEnum(s):
+		en := e.enum(id, id, doc, s.Enum)
+		e.order = append(e.order, en)
+	case isObject(s):
+		m := e.message(id, id, s, where)
+		m.doc = doc
+		e.order = append(e.order, m)
+	default:
+		m := newMessage(id, id)
+		m.doc = doc
+		f := e.wrap(m, s, where)
+		e.lossy(where, "%s definitions are exported as messages with a single %s field", describe(s), f.name)
+		e.order = append(e.order, m)
+	}
+}
+
+// describe names the kind of schema that is not an object
+func describe(s *swagger2.Schema) string {
+	switch {
+	case s.Ref != "":
+		return "reference"
+	case s.Type == "":
+		return "untyped"
+	case s.Type == "object":
+		return "map"
+	}
+	return s.Type
+}
+
+// isEnum returns true if a schema is exported as an enum
+func isEnum(s *swagger2.Schema) bool {
+	return s.Ref == "" && s.Type == "string" && s.Enum != nil
+}
+
+// isObject returns true if a schema is exported as a message with a field for each property
+func isObject(s *swagger2.Schema) bool {
+	return s.Ref == "" && (len(s.Properties) > 0 || len(s.AllOf) > 0 || (s.Type == "object" && s.AdditionalProperties == nil))
+}
+
+// newMessage returns an empty message
+func newMessage(name, local string) *message {
+	return &message{name: name, local: local, ids: make(namer), scope: make(namer)}
+}
+
+// message builds a message with a field for each property of an object schema, including those of the schemas
+// it combines with allOf
+func (e *exporter) message(name, local string, s *swagger2.Schema, where string) *message {
+	m := newMessage(name, local)
+	props, required := e.properties(s, make(map[string]bool))
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := props[k]
+		e.field(m, k, &p, lists.Contains(required, k), where+".properties."+k)
+	}
+	e.number(m, where)
+	return m
+}
+
+// properties collects the properties of a schema and the schemas it combines with allOf
+func (e *exporter) properties(s *swagger2.Schema, seen map[string]bool) (map[string]swagger2.Schema, []string) {
+	props := make(map[string]swagger2.Schema)
+	required := append([]string{}, s.Required...)
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			name, ok := e.swag.Definitions.RefName(part.Ref)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			def := e.swag.Definitions[name]
+			part = def
+		}
+		p, r := e.properties(&part, seen)
+		for k, v := range p {
+			props[k] = v
+		}
+		required = append(required, r...)
+	}
+	for k, v := range s.Properties {
+		props[k] = v
+	}
+	return props, required
+}
+
+// field adds a field for a property to a message
+func (e *exporter) field(m *message, prop string, s *swagger2.Schema, required bool, where string) *field {
+	f := &field{name: m.ids.unique(fieldName(prop)), doc: s.Description, fixed: s.Extensions.Int("x-proto-field")}
+	if jsonName(f.name) != prop {
+		f.json = prop
+	}
+	var kind int
+	f.typ, kind = e.fieldType(m, prop, &s.ItemsDef, s, where)
+	switch {
+	case kind == repeated:
+		f.label = "repeated"
+	case kind == scalar && !required:
+		f.label = "optional"
+	}
+	m.fields = append(m.fields, f)
+	return f
+}
+
+// wrap adds the single field of a message that holds a value which is not an object, named items for arrays and
+// value otherwise
+func (e *exporter) wrap(m *message, s *swagger2.Schema, where string) *field {
+	name := "value"
+	if s.Ref == "" && s.Type == "array" {
+		name = "items"
+	}
+	f := e.field(m, name, s, true, where)
+	e.number(m, where)
+	return f
+}
+
+// fieldType returns the type of a field and its kind. s is the schema when there is one, which allows inline
+// objects; they become messages nested in m, as do enums.
+func (e *exporter) fieldType(m *message, hint string, d *swagger2.ItemsDef, s *swagger2.Schema, where string) (string, int) {
+	if d.Ref != "" {
+		name, _ := e.swag.Definitions.RefName(d.Ref)
+		id, ok := e.names[name]
+		if !ok {
+			e.lossy(where, "the reference %s cannot be resolved", d.Ref)
+			return e.wellKnown("Value"), composite
+		}
+		if def := e.swag.Definitions[name]; isEnum(&def) {
+			return id, scalar
+		}
+		return id, composite
+	}
+	if s != nil && (len(s.Properties) > 0 || len(s.AllOf) > 0) {
+		local := m.scope.unique(messageName(hint))
+		m.nested = append(m.nested, e.message(m.name+"."+local, local, s, where))
+		return local, composite
+	}
+	if d.Type == "string" && d.Enum != nil {
+		local := m.scope.unique(messageName(hint))
+		m.enums = append(m.enums, e.enum(m.name+"."+local, local, "", d.Enum))
+		return local, scalar
+	}
+	switch d.Type {
+	case "array":
+		items := d.Items
+		switch {
+		case items == nil:
+			return e.wellKnown("Value"), repeated
+		case items.Type == "array":
+			e.lossy(where, "arrays of arrays are exported as repeated google.protobuf.ListValue")
+			return e.wellKnown("ListValue"), repeated
+		case items.Type == "object" && items.Ref == "":
+			e.lossy(where, "arrays of inline objects are exported as repeated google.protobuf.Struct")
+			return e.wellKnown("Struct"), repeated
+		}
+		t, _ := e.fieldType(m, hint+"Item", items, nil, where+".items")
+		return t, repeated
+	case "object":
+		value := d.AdditionalProperties
+		if value == nil {
+			return e.wellKnown("Struct"), composite
+		}
+		if value.Type == "array" || (value.Type == "object" && value.Ref == "") {
+			e.lossy(where, "maps of arrays or maps are exported as maps of google.protobuf.Value")
+			return "map<string, " + e.wellKnown("Value") + ">", mapped
+		}
+		t, _ := e.fieldType(m, hint+"Value", value, nil, where+".additionalProperties")
+		return "map<string, " + t + ">", mapped
+	case "string":
+		switch d.Format {
+		case "date-time":
+			e.imports["google/protobuf/timestamp.proto"] = true
+			return "google.protobuf.Timestamp", composite
+		case "byte", "binary":
+			return "bytes", scalar
+		}
+		return "string", scalar
+	case "integer":
+		if d.Format == "int32" {
+			return "int32", scalar
+		}
+		return "int64", scalar
+	case "number":
+		if d.Format == "float" {
+			return "float", scalar
+		}
+		return "double", scalar
+	case "boolean":
+		return "bool", scalar
+	case "file":
+		return "bytes", scalar
+	}
+	return e.wellKnown("Value"), composite
+}
+
+// wellKnown returns a type of google/protobuf/struct.proto, importing it
+func (e *exporter) wellKnown(name string) string {
+	e.imports["google/protobuf/struct.proto"] = true
+	return "google.protobuf." + name
+}
+
+// reservedRange returns true if a field number cannot be used
+func reservedRange(n int) bool {
+	return n <= 0 || (n >= 19000 && n <= 19999) || n > 536870911
+}
+
+// number gives each field of a message its number: the one from its x-proto-field extension, the one in the
+// lock, or the next free one after the highest number in the lock. The numbers of fields that are in the lock but no
+// longer in the message are reserved.
+func (e *exporter) number(m *message, where string) {
+	var locked map[string]int
+	if e.opts.Lock != nil {
+		locked = e.opts.Lock.Messages[m.name]
+	}
+	used := make(map[int]bool)
+	highest := 0
+	for _, n := range locked {
+		if n > highest {
+			highest = n
+		}
+	}
+	for _, f := range m.fields {
+		if f.fixed == 0 {
+			continue
+		}
+		if used[f.fixed] || reservedRange(f.fixed) {
+			e.lossy(where, "field %s cannot have the number %d", f.name, f.fixed)
+			continue
+		}
+		f.number = f.fixed
+		used[f.number] = true
+	}
+	for _, f := range m.fields {
+		if n, ok := locked[f.name]; ok && f.number == 0 && !used[n] {
+			f.number = n
+			used[n] = true
+		}
+	}
+	for _, f := range m.fields {
+		if f.number != 0 {
+			continue
+		}
+		highest++
+		for used[highest] || reservedRange(highest) {
+			highest++
+		}
+		f.number = highest
+		used[highest] = true
+	}
+	sort.Slice(m.fields, func(i, j int) bool { return m.fields[i].number < m.fields[j].number })
+
+	present := make(map[string]bool)
+	for _, f := range m.fields {
+		present[f.name] = true
+	}
+	record := make(map[string]int)
+	for name, n := range locked {
+		if !present[name] {
+			record[name] = n
+			m.removed = append(m.removed, name)
+			if !used[n] {
+				m.reserved = append(m.reserved, n)
+			}
+		}
+	}
+	sort.Strings(m.removed)
+	sort.Ints(m.reserved)
+	if e.opts.Lock != nil {
+		for _, f := range m.fields {
+			record[f.name] = f.number
+		}
+		if len(record) > 0 {
+			e.opts.Lock.Messages[m.name] = record
+		}
+	}
+}
+
+// enum builds an enum for string values. The zero value is the unspecified one that proto3 requires, and the
+// others are numbered like fields.
+func (e *exporter) enum(name, local, doc string, values []interface{}) *enum {
+	en := &enum{name: name, local: local, doc: doc}
+	prefix := constantName(local)
+	ids := make(namer)
+	ids[prefix+"_UNSPECIFIED"] = true
+	var locked map[string]int
+	if e.opts.Lock != nil {
+		locked = e.opts.Lock.Enums[name]
+	}
+	used := map[int]bool{0: true}
+	highest := 0
+	for _, n := range locked {
+		if n > highest {
+			highest = n
+		}
+	}
+	for _, v := range values {
+		text := fmt.Sprint(v)
+		constant := constantName(text)
+		if constant == "" {
+			constant = "VALUE"
+		}
+		ev := &enumValue{value: text, name: ids.unique(prefix + "_" + constant)}
+		if n, ok := locked[text]; ok && !used[n] {
+			ev.number = n
+			used[n] = true
+		}
+		en.values = append(en.values, ev)
+	}
+	record := make(map[string]int)
+	for k, n := range locked {
+		record[k] = n
+	}
+	for _, ev := range en.values {
+		if ev.number == 0 {
+			highest++
+			for used[highest] {
+				highest++
+			}
+			ev.number = highest
+			used[highest] = true
+		}
+		record[ev.value] = ev.number
+	}
+	sort.SliceStable(en.values, func(i, j int) bool { return en.values[i].number < en.values[j].number })
+	if e.opts.Lock != nil {
+		e.opts.Lock.Enums[name] = record
+	}
+	return en
+}
+
+// service writes an rpc for each operation, along with the request and response messages they need
+func (e *exporter) service() {
+	paths := make([]string, 0, len(e.swag.Paths))
+	for p := range e.swag.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	rpcs := make(namer)
+	for _, path := range paths {
+		item := e.swag.Paths[path]
+		for _, method := range swagger2.Methods {
+			if op := item.Operation(method); op != nil {
+				e.rpc(rpcs, path, method, &item, op)
+			}
+		}
+	}
+}
+
+// rpc writes the rpc for an operation
+func (e *exporter) rpc(rpcs namer, path, method string, item *swagger2.PathItem, op *swagger2.Operation) {
+	where := "paths." + path + "." + method
+	var name string
+	if op.OperationId != "" {
+		name = rpcs.unique(messageName(op.OperationId))
+	} else {
+		name = rpcs.unique(messageName(method + " " + pathParam.ReplaceAllString(path, "by $1")))
+	}
+
+	reqName := e.ids.unique(name + "Request")
+	req := newMessage(reqName, reqName)
+	params := e.parameters(op, item, path)
+	fields := make(map[string]string)
+	body := ""
+	for _, p := range params {
+		pw := where + ".parameters." + p.Name
+		var f *field
+		if p.In == "body" && p.Schema != nil {
+			f = e.field(req, p.Name, p.Schema, p.Required != nil && *p.Required, pw)
+			body = f.name
+		} else {
+			f = e.field(req, p.Name, &swagger2.Schema{ItemsDef: p.ItemsDef, Description: p.Description}, p.In == "path" || (p.Required != nil && *p.Required), pw)
+		}
+		f.doc = p.Description
+		fields[p.Name] = f.name
+		switch p.In {
+		case "header":
+			e.lossy(pw, "header parameters are not bound by google.api.http")
+		case "formData":
+			body = "*"
+		}
+	}
+	e.number(req, where)
+	reqType := req.name
+	if len(req.fields) == 0 {
+		delete(e.ids, reqName)
+		e.imports["google/protobuf/empty.proto"] = true
+		reqType = "google.protobuf.Empty"
+	} else {
+		e.order = append(e.order, req)
+	}
+
+	resType, responseBody := e.response(name, op, where)
+
+	e.imports["google/api/annotations.proto"] = true
+	if e.rpcs.Len() > 0 {
+		e.rpcs.WriteString("\n")
+	}
+	text := strings.TrimSpace(op.Summary)
+	if desc := strings.TrimSpace(op.Description); desc != "" && desc != text {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += desc
+	}
+	e.rpcs.WriteString(comment("  ", text))
+	fmt.Fprintf(&e.rpcs, "  rpc %s(%s) returns (%s) {\n", name, reqType, resType)
+	template := strings.TrimRight(e.swag.BasePath, "/") + pathParam.ReplaceAllStringFunc(path, func(s string) string {
+		if f, ok := fields[s[1:len(s)-1]]; ok {
+			return "{" + f + "}"
+		}
+		return s
+	})
+	e.rpcs.WriteString("    option (google.api.http) = {\n")
+	switch method {
+	case "head", "options":
+		fmt.Fprintf(&e.rpcs, "      custom: {\n        kind: %q\n        path: %q\n      }\n", strings.ToUpper(method), template)
+	default:
+		fmt.Fprintf(&e.rpcs, "      %s: %q\n", method, template)
+	}
+	if body != "" {
+		fmt.Fprintf(&e.rpcs, "      body: %q\n", body)
+	}
+	if responseBody != "" {
+		fmt.Fprintf(&e.rpcs, "      response_body: %q\n", responseBody)
+	}
+	e.rpcs.WriteString("    };\n")
+	if op.Deprecated {
+		e.rpcs.WriteString("    option deprecated = true;\n")
+	}
+	e.rpcs.WriteString("  }\n")
+}
+
+// response returns the type an rpc returns, from the first successful response of the operation, and the field
+// of a wrapping message that holds the body
+func (e *exporter) response(rpc string, op *swagger2.Operation, where string) (string, string) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) == 0 {
+		if _, ok := op.Responses["default"]; ok {
+			codes = append(codes, "default")
+		}
+	}
+	if len(codes) == 0 || op.Responses[codes[0]].Schema == nil {
+		e.imports["google/protobuf/empty.proto"] = true
+		return "google.protobuf.Empty", ""
+	}
+	s := op.Responses[codes[0]].Schema
+	where += ".responses." + codes[0]
+	if name, ok := e.swag.Definitions.RefName(s.Ref); ok && s.Ref != "" {
+		if def, ok := e.swag.Definitions[name]; ok && !isEnum(&def) {
+			return e.names[name], ""
+		}
+	}
+	id := e.ids.unique(rpc + "Response")
+	if isObject(s) {
+		m := e.message(id, id, s, where)
+		e.order = append(e.order, m)
+		return id, ""
+	}
+	m := newMessage(id, id)
+	f := e.wrap(m, s, where)
+	e.order = append(e.order, m)
+	return id, f.name
+}
+
+// parameters resolves the parameters of an operation and those of its path
+func (e *exporter) parameters(op *swagger2.Operation, item *swagger2.PathItem, path string) []*swagger2.Parameter {
+	params := e.swag.OperationParameters(path, item, op)
+	all := make([]*swagger2.Parameter, len(params))
+	for i := range params {
+		all[i] = &params[i]
+	}
+	return all
+}
+
+// source writes the file
+func (e *exporter) source() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by swagproto. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", e.opts.Package)
+	if len(e.imports) > 0 {
+		imports := make([]string, 0, len(e.imports))
+		for i := range e.imports {
+			imports = append(imports, i)
+		}
+		sort.Strings(imports)
+		for _, i := range imports {
+			fmt.Fprintf(&b, "import %q;\n", i)
+		}
+		b.WriteString("\n")
+	}
+	if e.opts.GoPackage != "" {
+		fmt.Fprintf(&b, "option go_package = %q;\n\n", e.opts.GoPackage)
+	}
+	for _, d := range e.order {
+		switch d := d.(type) {
+		case *message:
+			writeMessage(&b, "", d)
+		case *enum:
+			writeEnum(&b, "", d)
+		}
+		b.WriteString("\n")
+	}
+	if e.rpcs.Len() > 0 {
+		name := e.opts.Service
+		if name == "" && e.swag.Info.Title != "" {
+			name = messageName(e.swag.Info.Title) + "Service"
+		} else if name == "" {
+			name = "APIService"
+		}
+		text := e.swag.Info.Description
+		if text == "" {
+			text = e.swag.Info.Title
+		}
+		b.WriteString(comment("", text))
+		fmt.Fprintf(&b, "service %s {\n", name)
+		b.Write(e.rpcs.Bytes())
+		b.WriteString("}\n")
+	}
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+// writeMessage writes a message and its nested types
+func writeMessage(b *bytes.Buffer, indent string, m *message) {
+	b.WriteString(comment(indent, m.doc))
+	fmt.Fprintf(b, "%smessage %s {\n", indent, m.local)
+	inner := indent + "  "
+	for _, f := range m.fields {
+		b.WriteString(comment(inner, f.doc))
+		b.WriteString(inner)
+		if f.label != "" {
+			b.WriteString(f.label + " ")
+		}
+		fmt.Fprintf(b, "%s %s = %d", f.typ, f.name, f.number)
+		if f.json != "" {
+			fmt.Fprintf(b, " [json_name = %q]", f.json)
+		}
+		b.WriteString(";\n")
+	}
+	if len(m.reserved) > 0 {
+		list := make([]string, 0, len(m.reserved))
+		for _, n := range m.reserved {
+			list = append(list, strconv.Itoa(n))
+		}
+		fmt.Fprintf(b, "%sreserved %s;\n", inner, strings.Join(list, ", "))
+	}
+	if len(m.removed) > 0 {
+		list := make([]string, 0, len(m.removed))
+		for _, n := range m.removed {
+			list = append(list, strconv.Quote(n))
+		}
+		fmt.Fprintf(b, "%sreserved %s;\n", inner, strings.Join(list, ", "))
+	}
+	for _, en := range m.enums {
+		b.WriteString("\n")
+		writeEnum(b, inner, en)
+	}
+	for _, n := range m.nested {
+		b.WriteString("\n")
+		writeMessage(b, inner, n)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// writeEnum writes an enum
+func writeEnum(b *bytes.Buffer, indent string, en *enum) {
+	b.WriteString(comment(indent, en.doc))
+	fmt.Fprintf(b, "%senum %s {\n", indent, en.local)
+	fmt.Fprintf(b, "%s  %s_UNSPECIFIED = 0;\n", indent, constantName(en.local))
+	for _, v := range en.values {
+		fmt.Fprintf(b, "%s  %s = %d;\n", indent, v.name, v.number)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
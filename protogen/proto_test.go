@@ -0,0 +1,175 @@
+package protogen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestExamples(t *testing.T) {
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			src, _ := Proto(swag, Options{})
+			if !strings.Contains(string(src), "syntax = \"proto3\";") || strings.Count(string(src), "{") != strings.Count(string(src), "}") {
+				t.Errorf("%s: unexpected output\n%s", file, src)
+			}
+		}
+	}
+}
+
+const shelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+basePath: /v1
+paths:
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: petId, in: path, required: true, type: integer, format: int64}
+        - {name: X-Trace, in: header, type: string}
+      responses:
+        200:
+          description: The pet.
+          schema: {$ref: "#/definitions/Pet"}
+    head:
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+      responses:
+        200: {description: The pet exists.}
+  /pets/{petId}/photo:
+    post:
+      operationId: uploadPhoto
+      deprecated: true
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+        - {name: photo, in: formData, type: file}
+      responses:
+        200:
+          description: The photo URLs.
+          schema: {type: array, items: {type: string}}
+definitions:
+  Animal:
+    type: object
+    required: [name]
+    properties:
+      name: {type: string, description: What it answers to.}
+  Pet:
+    allOf:
+      - $ref: "#/definitions/Animal"
+      - type: object
+        properties:
+          status: {$ref: "#/definitions/Status"}
+          born: {type: string, format: date-time}
+          tags: {type: object, additionalProperties: {type: integer, format: int32}}
+          grid: {type: array, items: {type: array, items: {type: number}}}
+          owner:
+            type: object
+            properties:
+              kind: {type: string, enum: [person, shelter]}
+          x-id: {type: string, x-proto-field: 10}
+  Status:
+    type: string
+    enum: [available, on hold]
+  Album:
+    type: object
+    properties:
+      photos: {type: array, items: {type: object, properties: {url: {type: string}}}}
+`
+
+func TestProto(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, errs := Proto(swag, Options{Package: "shelter.v1", GoPackage: "example.com/shelter"})
+	out := string(src)
+	for _, want := range []string{
+		"package shelter.v1;",
+		"import \"google/api/annotations.proto\";\nimport \"google/protobuf/empty.proto\";\nimport \"google/protobuf/struct.proto\";\nimport \"google/protobuf/timestamp.proto\";",
+		`option go_package = "example.com/shelter";`,
+		"message Pet {\n  google.protobuf.Timestamp born = 1;\n  repeated google.protobuf.ListValue grid = 2;\n  // What it answers to.\n  string name = 3;\n  Owner owner = 4;\n  optional Status status = 5;\n  map<string, int32> tags = 6;\n  optional string x_id = 10 [json_name = \"x-id\"];\n",
+		"  message Owner {\n    optional Kind kind = 1;\n\n    enum Kind {\n      KIND_UNSPECIFIED = 0;\n      KIND_PERSON = 1;\n      KIND_SHELTER = 2;\n    }\n  }",
+		"enum Status {\n  STATUS_UNSPECIFIED = 0;\n  STATUS_AVAILABLE = 1;\n  STATUS_ON_HOLD = 2;\n}",
+		"message GetPetRequest {\n  int64 pet_id = 1;\n  optional string x_trace = 2 [json_name = \"X-Trace\"];\n}",
+		"service PetShelterService {",
+		"rpc GetPet(GetPetRequest) returns (Pet) {\n    option (google.api.http) = {\n      get: \"/v1/pets/{pet_id}\"\n    };\n  }",
+		"rpc HeadPetsByPetId(HeadPetsByPetIdRequest) returns (google.protobuf.Empty) {\n    option (google.api.http) = {\n      custom: {\n        kind: \"HEAD\"\n        path: \"/v1/pets/{pet_id}\"\n      }\n    };\n  }",
+		"      body: \"*\"\n      response_body: \"items\"\n    };\n    option deprecated = true;",
+		"message UploadPhotoResponse {\n  repeated string items = 1;\n}",
+		"message Album {\n  repeated google.protobuf.Struct photos = 1;\n}",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	text := swagger2.ErrorList(errs).String()
+	for _, want := range []string{"arrays of arrays", "definitions.Album.properties.photos: arrays of inline objects", "paths./pets/{petId}.get.parameters.X-Trace: header parameters"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the lossy errors:\n%s", want, text)
+		}
+	}
+}
+
+func TestLock(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := NewLock()
+	Proto(swag, Options{Lock: lock})
+	buf, err := lock.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a field that sorts first is added and another is removed, and a new enum value is added before the others
+	pet := swag.Definitions["Pet"]
+	props := pet.AllOf[1].Properties
+	delete(props, "grid")
+	props["age"] = swagger2.Schema{ItemsDef: swagger2.ItemsDef{Type: "integer"}}
+	status := swag.Definitions["Status"]
+	status.Enum = append([]interface{}{"adopted"}, status.Enum...)
+	swag.Definitions["Status"] = status
+
+	lock, err = LoadLock(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, _ := Proto(swag, Options{Lock: lock})
+	out := string(src)
+	for _, want := range []string{
+		"google.protobuf.Timestamp born = 1;",
+		"string name = 3;",
+		"optional string x_id = 10 [json_name = \"x-id\"];\n  optional int64 age = 11;\n  reserved 2;\n  reserved \"grid\";",
+		"STATUS_AVAILABLE = 1;\n  STATUS_ON_HOLD = 2;\n  STATUS_ADOPTED = 3;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if lock.Messages["Pet"]["grid"] != 2 || lock.Messages["Pet"]["age"] != 11 || lock.Enums["Status"]["adopted"] != 3 {
+		t.Errorf("unexpected lock %+v", lock)
+	}
+}
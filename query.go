@@ -0,0 +1,238 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Match is a node of a document that Query found.
+type Match struct {
+	Pointer string      // The JSON Pointer of the node
+	Value   interface{} // The node, as Get returns it
+}
+
+// Get returns the part of a document that a JSON Pointer points at, with the Go type of the field that holds it:
+// "/paths/~1pets/get" gives an *Operation, "/definitions/Pet" a Schema and "/info/title" a string. Vendor
+// extensions, such as "/paths/~1pets/get/x-audience", are found in the Extensions of operations and schemas.
+func Get(s *Swagger, ptr string) (interface{}, error) {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(s)
+	for j, t := range tokens {
+		var ok bool
+		if v, ok = lookup(v, t); !ok || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, fmt.Errorf("%s does not exist", pointer("", tokens[:j+1]...))
+		}
+	}
+	return v.Interface(), nil
+}
+
+// Set stores a value in a document at a JSON Pointer. The value is converted to the type of the field that holds
+// it through JSON when it is not of that type, so that a map[string]interface{} can be stored as a Schema. Members
+// of maps and missing objects such as the contact of the info are created as needed, and "-" appends to a list, as
+// in JSON Patch.
+func Set(s *Swagger, ptr string, value interface{}) error {
+	tokens, err := pointerTokens(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("cannot replace the document")
+	}
+	return assign(reflect.ValueOf(s).Elem(), tokens, 0, value)
+}
+
+// Query returns the nodes of a document that a JSONPath expression matches, such as
+// "$.paths..parameters[?(@.in == 'header')]", in document order with the members of maps sorted by name. Their
+// values are given as Get returns them.
+func Query(s *Swagger, expr string) ([]Match, error) {
+	path, err := compileJsonPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := documentValue(s)
+	if err != nil {
+		return nil, err
+	}
+	nodes := path.find(doc)
+	matches := make([]Match, 0, len(nodes))
+	for _, n := range nodes {
+		v, err := Get(s, n.pointer())
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, Match{Pointer: n.pointer(), Value: v})
+	}
+	return matches, nil
+}
+
+// lookup returns the member of a value that a reference token names
+func lookup(v reflect.Value, token string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ext, ok := structField(v, token)
+		if !ok || !ext {
+			return f, ok
+		}
+		return lookup(f, token)
+	case reflect.Map:
+		key, ok := mapKey(v, token)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return v.MapIndex(key), true
+	case reflect.Slice, reflect.Array:
+		i, err := arrayIndex(token, v.Len(), false)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		return v.Index(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// structField returns the field of a struct that a reference token names by its JSON name, looking into embedded
+// structs. For the names of vendor extensions it returns the Extensions that hold them, with ext set.
+func structField(v reflect.Value, token string) (f reflect.Value, ext bool, ok bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		switch {
+		case sf.Type == reflect.TypeOf(Extensions{}):
+			if strings.HasPrefix(token, "x-") {
+				return v.Field(i), true, true
+			}
+		case sf.Anonymous && name == "":
+			if f, ext, ok := structField(v.Field(i), token); ok {
+				return f, ext, ok
+			}
+		case name == token:
+			return v.Field(i), false, true
+		}
+	}
+	return reflect.Value{}, false, false
+}
+
+// mapKey returns the key of a map that a reference token names, if it is in the map
+func mapKey(m reflect.Value, token string) (reflect.Value, bool) {
+	if m.Type().Key().Kind() == reflect.String {
+		key := reflect.ValueOf(token).Convert(m.Type().Key())
+		return key, m.MapIndex(key).IsValid()
+	}
+	// maps decoded from YAML have keys of any type
+	for _, key := range m.MapKeys() {
+		if fmt.Sprint(key.Interface()) == token {
+			return key, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// assign stores a value at the reference tokens from i on below a value that can be set
+func assign(v reflect.Value, tokens []string, i int, value interface{}) error {
+	if i == len(tokens) {
+		return convertTo(v, value, tokens)
+	}
+	missing := func() error {
+		return fmt.Errorf("%s does not exist", pointer("", tokens[:i+1]...))
+	}
+	token := tokens[i]
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if v.Type().Elem().Kind() != reflect.Struct {
+				return missing()
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assign(v.Elem(), tokens, i, value)
+	case reflect.Interface:
+		if v.IsNil() {
+			return missing()
+		}
+		c := reflect.New(v.Elem().Type()).Elem()
+		c.Set(v.Elem())
+		if err := assign(c, tokens, i, value); err != nil {
+			return err
+		}
+		v.Set(c)
+		return nil
+	case reflect.Struct:
+		f, ext, ok := structField(v, token)
+		switch {
+		case !ok:
+			return missing()
+		case ext:
+			return assign(f, tokens, i, value)
+		}
+		return assign(f, tokens, i+1, value)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key, ok := mapKey(v, token)
+		if !ok && v.Type().Key().Kind() != reflect.String {
+			key = reflect.ValueOf(token)
+		}
+		c := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			c.Set(existing)
+		} else if i+1 < len(tokens) {
+			return missing()
+		}
+		if err := assign(c, tokens, i+1, value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, c)
+		return nil
+	case reflect.Slice:
+		n, err := arrayIndex(token, v.Len(), i+1 == len(tokens))
+		if err != nil {
+			return fmt.Errorf("%s: %s", pointer("", tokens[:i+1]...), err)
+		}
+		if n == v.Len() {
+			c := reflect.New(v.Type().Elem()).Elem()
+			if err := assign(c, tokens, i+1, value); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, c))
+			return nil
+		}
+		return assign(v.Index(n), tokens, i+1, value)
+	}
+	return missing()
+}
+
+// convertTo stores a value in a destination, converting it through JSON if it is not of the type of the destination
+func convertTo(dst reflect.Value, value interface{}, tokens []string) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if v := reflect.ValueOf(value); v.Type().AssignableTo(dst.Type()) {
+		dst.Set(v)
+		return nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	converted := reflect.New(dst.Type())
+	if err := json.Unmarshal(b, converted.Interface()); err != nil {
+		return fmt.Errorf("%s: cannot store %s as %s", pointer("", tokens...), display(value), dst.Type())
+	}
+	dst.Set(converted.Elem())
+	return nil
+}
@@ -0,0 +1,143 @@
+package swagger2
+
+import (
+	"strings"
+	"testing"
+)
+
+const queryShelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+paths:
+  /pets:
+    parameters:
+      - {name: X-Request-Id, in: header, type: string}
+    get:
+      x-audience: public
+      parameters:
+        - {name: limit, in: query, type: integer}
+        - {name: X-Tenant, in: header, type: string}
+      responses:
+        200:
+          description: OK
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+          examples:
+            application/json: [{name: Rex}]
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+`
+
+func TestGet(t *testing.T) {
+	swag, err := LoadYaml([]byte(queryShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ptr  string
+		want interface{}
+	}{
+		{"/info/title", "Pet Shelter"},
+		{"/paths/~1pets/get/x-audience", "public"},
+		{"/paths/~1pets/get/parameters/1/in", "header"},
+		{"/paths/~1pets/get/responses/200/schema/items/$ref", "#/definitions/Pet"},
+		{"/paths/~1pets/get/responses/200/examples/application~1json/0/name", "Rex"},
+		{"/definitions/Pet/properties/name/type", "string"},
+	}
+	for _, test := range tests {
+		if v, err := Get(swag, test.ptr); err != nil || v != test.want {
+			t.Errorf("%s: got %v, %v", test.ptr, v, err)
+		}
+	}
+	if v, err := Get(swag, "/paths/~1pets/get"); err != nil || v.(*Operation) != swag.Paths["/pets"].Get {
+		t.Errorf("unexpected operation %v, %v", v, err)
+	}
+	if v, err := Get(swag, "/definitions/Pet"); err != nil || v.(Schema).Type != "object" {
+		t.Errorf("unexpected definition %v, %v", v, err)
+	}
+	for ptr, want := range map[string]string{
+		"/paths/~1cats/get":              "/paths/~1cats does not exist",
+		"/paths/~1pets/post/summary":     "/paths/~1pets/post does not exist",
+		"/paths/~1pets/get/parameters/2": "/paths/~1pets/get/parameters/2 does not exist",
+		"/paths/~1pets/get/x-internal":   "/paths/~1pets/get/x-internal does not exist",
+		"/info/title/x":                  "/info/title/x does not exist",
+		"info":                           "info is not a JSON Pointer",
+	} {
+		if _, err := Get(swag, ptr); err == nil || err.Error() != want {
+			t.Errorf("%s: unexpected error %v", ptr, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	swag, err := LoadYaml([]byte(queryShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sets := []struct {
+		ptr   string
+		value interface{}
+	}{
+		{"/info/contact/name", "Shelter team"},
+		{"/paths/~1pets/get/summary", "List pets"},
+		{"/paths/~1pets/get/x-audience", "partner"},
+		{"/paths/~1pets/get/parameters/0/maximum", 100},
+		{"/paths/~1pets/get/parameters/-", map[string]interface{}{"name": "offset", "in": "query", "type": "integer"}},
+		{"/definitions/Pet/properties/name/description", "The name of the pet."},
+		{"/definitions/Owner", map[string]interface{}{"type": "object"}},
+		{"/paths/~1pets/get/responses/200/examples/application~1json/0/name", "Max"},
+		{"/tags", []Tag{{Name: "pets"}}},
+	}
+	for _, set := range sets {
+		if err := Set(swag, set.ptr, set.value); err != nil {
+			t.Errorf("%s: %s", set.ptr, err)
+		}
+	}
+	get := swag.Paths["/pets"].Get
+	if swag.Info.Contact.Name != "Shelter team" || get.Summary != "List pets" || get.Extensions.String("x-audience") != "partner" ||
+		*get.Parameters[0].Maximum != 100 || len(get.Parameters) != 3 || get.Parameters[2].Name != "offset" ||
+		swag.Definitions["Pet"].Properties["name"].Description == "" || swag.Definitions["Owner"].Type != "object" || len(swag.Tags) != 1 {
+		t.Errorf("unexpected document %+v", swag)
+	}
+	if v, _ := Get(swag, "/paths/~1pets/get/responses/200/examples/application~1json/0/name"); v != "Max" {
+		t.Errorf("unexpected example %v", v)
+	}
+	for ptr, want := range map[string]string{
+		"":                                  "cannot replace the document",
+		"/paths/~1cats/get/summary":         "/paths/~1cats does not exist",
+		"/paths/~1pets/get/parameters/9/in": "/paths/~1pets/get/parameters/9: index 9 is out of range",
+		"/info/version":                     `/info/version: cannot store {"major":2} as string`,
+	} {
+		if err := Set(swag, ptr, map[string]int{"major": 2}); err == nil || err.Error() != want {
+			t.Errorf("%s: unexpected error %v", ptr, err)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	swag, err := LoadYaml([]byte(queryShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := Query(swag, "$.paths..parameters[?(@.in=='header')]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0)
+	for _, m := range matches {
+		names = append(names, m.Pointer+" "+m.Value.(Parameter).Name)
+	}
+	want := "/paths/~1pets/parameters/0 X-Request-Id, /paths/~1pets/get/parameters/1 X-Tenant"
+	if strings.Join(names, ", ") != want {
+		t.Errorf("unexpected matches %s", strings.Join(names, ", "))
+	}
+	matches, err = Query(swag, "$.paths.*[?(@.x-audience == 'public')]")
+	if err != nil || len(matches) != 1 || matches[0].Value.(*Operation) != swag.Paths["/pets"].Get {
+		t.Errorf("unexpected matches %v, %v", matches, err)
+	}
+	if _, err := Query(swag, "paths"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,260 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// SchemaFor returns the schema for a Go type, along with the definitions for the named struct types it uses.
+// See Registry for how types are described.
+func SchemaFor(t reflect.Type) (*Schema, Definitions) {
+	r := NewRegistry()
+	return r.Schema(t), r.Definitions
+}
+
+// Registry builds definitions from Go types, so that a spec can be published from the types a service already uses.
+// Each named struct type becomes a definition and is referred to by name, which also allows types that refer to
+// themselves. Struct fields are described as follows:
+//
+//   - The property name comes from the json tag, and fields tagged "-" or that are not exported are skipped.
+//   - Fields without omitempty are required.
+//   - Embedded structs are combined with allOf, unless the json tag names them.
+//   - time.Time is a date-time string, []byte a byte string, maps are objects with additionalProperties, and
+//     slices and arrays are arrays.
+//   - The description, enum (comma separated), minimum, maximum and pattern tags add to the property's schema.
+//     For arrays they describe the items.
+type Registry struct {
+	Definitions Definitions // The definitions built so far
+	names       map[reflect.Type]string
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{Definitions: make(Definitions), names: make(map[reflect.Type]string)}
+}
+
+// Schema returns the schema for a Go type, adding definitions for the named struct types it uses. The schema for
+// a named struct type is a reference to its definition.
+func (r *Registry) Schema(t reflect.Type) *Schema {
+	return r.schema(t, "")
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawType     = reflect.TypeOf(json.RawMessage{})
+	unnamedChar = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// schema describes a type. hint names the definition made for an anonymous struct that cannot be described inline.
+func (r *Registry) schema(t reflect.Type, hint string) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{ItemsDef: ItemsDef{Type: "string", Format: "date-time"}}
+	case rawType:
+		return &Schema{}
+	}
+	s := &Schema{}
+	switch t.Kind() {
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		s.Type, s.Format = "integer", "int32"
+	case reflect.Int, reflect.Int64:
+		s.Type, s.Format = "integer", "int64"
+	case reflect.Uint8, reflect.Uint16:
+		s.Type, s.Format = "integer", "int32"
+		s.Minimum = new(float64)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.Type, s.Format = "integer", "int64"
+		s.Minimum = new(float64)
+	case reflect.Float32:
+		s.Type, s.Format = "number", "float"
+	case reflect.Float64:
+		s.Type, s.Format = "number", "double"
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			s.Type, s.Format = "string", "byte"
+			break
+		}
+		s.Type = "array"
+		s.Items = r.items(t.Elem(), hint+"Item")
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+	case reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = r.items(t.Elem(), hint+"Value")
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t, hint)
+		}
+		return &Schema{ItemsDef: ItemsDef{Ref: MakeRef("definitions", r.define(t))}}
+	}
+	return s
+}
+
+// items describes the type of array items or map values, which cannot have properties. An anonymous struct is
+// made into a definition named hint.
+func (r *Registry) items(t reflect.Type, hint string) *ItemsDef {
+	s := r.schema(t, hint)
+	if len(s.Properties) == 0 && len(s.AllOf) == 0 {
+		return &s.ItemsDef
+	}
+	name := r.unique(hint)
+	r.Definitions[name] = *s
+	return &ItemsDef{Ref: MakeRef("definitions", name)}
+}
+
+// define adds the definition for a named struct type if it is not there already, and returns its name
+func (r *Registry) define(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+	name := unnamedChar.ReplaceAllString(t.Name(), "_")
+	if _, taken := r.Definitions[name]; taken {
+		// a type of the same name from another package; qualify it with the package name
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = r.unique(unnamedChar.ReplaceAllString(pkg, "_") + "." + name)
+	}
+	r.names[t] = name
+	r.Definitions[name] = Schema{} // reserve the name while the fields are described, which may refer back to it
+	r.Definitions[name] = *r.object(t, name)
+	return name
+}
+
+// unique returns a definition name that is not yet taken
+func (r *Registry) unique(name string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := r.Definitions[candidate]; !taken {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(i)
+	}
+}
+
+// object describes the fields of a struct, combining it with its embedded structs using allOf
+func (r *Registry) object(t reflect.Type, name string) *Schema {
+	own := &Schema{ItemsDef: ItemsDef{Type: "object"}, Properties: make(map[string]Schema)}
+	all := make([]Schema, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		embedded := f.Type
+		for embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if f.Anonymous && opts[0] == "" && embedded.Kind() == reflect.Struct {
+			all = append(all, *r.schema(embedded, name+embedded.Name()))
+			continue
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		prop := opts[0]
+		if prop == "" {
+			prop = f.Name
+		}
+		s := r.schema(f.Type, name+exportedName(prop))
+		for _, o := range opts[1:] {
+			switch o {
+			case "omitempty":
+			case "string":
+				// the value is written as a JSON string
+				*s = Schema{ItemsDef: ItemsDef{Type: "string"}}
+			}
+		}
+		if !lists.Contains(opts[1:], "omitempty") {
+			own.Required = append(own.Required, prop)
+		}
+		fieldTags(f.Tag, s)
+		own.Properties[prop] = *s
+	}
+	if len(own.Properties) == 0 {
+		own.Properties = nil
+	}
+	if len(all) == 0 {
+		return own
+	}
+	if own.Properties != nil {
+		all = append(all, *own)
+	}
+	return &Schema{AllOf: all}
+}
+
+// fieldTags applies the description, enum, minimum, maximum and pattern tags of a struct field
+func fieldTags(tag reflect.StructTag, s *Schema) {
+	if d, ok := tag.Lookup("description"); ok {
+		s.Description = d
+	}
+	values := &s.ItemsDef
+	if s.Type == "array" && s.Items != nil {
+		values = s.Items
+	}
+	if e, ok := tag.Lookup("enum"); ok {
+		values.Enum = make([]interface{}, 0)
+		for _, v := range strings.Split(e, ",") {
+			values.Enum = append(values.Enum, enumValue(values.Type, strings.TrimSpace(v)))
+		}
+	}
+	if m, ok := tag.Lookup("minimum"); ok {
+		if f, err := strconv.ParseFloat(m, 64); err == nil {
+			values.Minimum = &f
+		}
+	}
+	if m, ok := tag.Lookup("maximum"); ok {
+		if f, err := strconv.ParseFloat(m, 64); err == nil {
+			values.Maximum = &f
+		}
+	}
+	if p, ok := tag.Lookup("pattern"); ok {
+		values.Pattern = &p
+	}
+}
+
+// enumValue converts the text of an enum value to the type of the schema
+func enumValue(typ, text string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	}
+	return text
+}
+
+// exportedName capitalizes the first letter of a name, so that definitions made for anonymous structs read as
+// their parent's name followed by the field's
+func exportedName(name string) string {
+	c, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(c)) + name[size:]
+}
@@ -0,0 +1,81 @@
+package swagger2
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type reflectBase struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+type reflectPet struct {
+	reflectBase
+	Name     string                 `json:"name" description:"The pet's name." pattern:"^[A-Z]"`
+	Status   string                 `json:"status,omitempty" enum:"available, sold"`
+	Age      *uint8                 `json:"age,omitempty" maximum:"30"`
+	Sizes    []int                  `json:"sizes,omitempty" enum:"1,2,3"`
+	Labels   map[string]string      `json:"labels,omitempty"`
+	Photo    []byte                 `json:"photo,omitempty"`
+	Parent   *reflectPet            `json:"parent,omitempty"`
+	Friends  []reflectPet           `json:"friends,omitempty"`
+	Owner    struct{ Name string }  `json:"owner"`
+	Visits   []struct{ At string }  `json:"visits,omitempty"`
+	Extra    map[string]interface{} `json:"-"`
+	Count    int                    `json:",string"`
+	internal string
+}
+
+func TestSchemaFor(t *testing.T) {
+	s, defs := SchemaFor(reflect.TypeOf([]reflectPet{}))
+	if s.Type != "array" || s.Items.Ref != "#/definitions/reflectPet" {
+		t.Fatalf("unexpected schema %+v", s)
+	}
+	pet, ok := defs["reflectPet"]
+	if !ok || len(pet.AllOf) != 2 || pet.AllOf[0].Ref != "#/definitions/reflectBase" {
+		t.Fatalf("embedded structs should become allOf, got %+v", pet)
+	}
+	if base := defs["reflectBase"]; len(base.Required) != 1 || base.Properties["created"].Format != "date-time" {
+		t.Errorf("unexpected base %+v", base)
+	}
+	own := pet.AllOf[1]
+	if want := []string{"name", "owner", "Count"}; !reflect.DeepEqual(own.Required, want) {
+		t.Errorf("expected required %v, got %v", want, own.Required)
+	}
+	props := own.Properties
+	if props["name"].Description != "The pet's name." || *props["name"].Pattern != "^[A-Z]" {
+		t.Errorf("unexpected name %+v", props["name"])
+	}
+	if !reflect.DeepEqual(props["status"].Enum, []interface{}{"available", "sold"}) {
+		t.Errorf("unexpected status enum %v", props["status"].Enum)
+	}
+	if age := props["age"]; age.Format != "int32" || *age.Minimum != 0 || *age.Maximum != 30 {
+		t.Errorf("unexpected age %+v", age)
+	}
+	if sizes := props["sizes"]; sizes.Items.Type != "integer" || len(sizes.Items.Enum) != 3 || sizes.Items.Enum[0] != int64(1) {
+		t.Errorf("enum tags on arrays should describe the items, got %+v", sizes.Items)
+	}
+	if props["labels"].AdditionalProperties.Type != "string" || props["photo"].Format != "byte" {
+		t.Errorf("unexpected labels or photo %+v %+v", props["labels"], props["photo"])
+	}
+	if props["parent"].Ref != "#/definitions/reflectPet" || props["friends"].Items.Ref != "#/definitions/reflectPet" {
+		t.Errorf("a type should be able to refer to itself")
+	}
+	if owner := props["owner"]; owner.Properties["Name"].Type != "string" {
+		t.Errorf("anonymous structs should be described inline, got %+v", owner)
+	}
+	if visits := props["visits"]; visits.Items.Ref != "#/definitions/reflectPetVisitsItem" || defs["reflectPetVisitsItem"].Properties["At"].Type != "string" {
+		t.Errorf("anonymous structs in arrays should become definitions, got %+v", visits.Items)
+	}
+	if props["Count"].Type != "string" || props["Extra"].Type != "" || props["internal"].Type != "" {
+		t.Errorf("unexpected Count, Extra or internal properties")
+	}
+
+	op := &Operation{Responses: Responses{"200": Response{Description: "The pets.", Schema: s}}}
+	swag := &Swagger{Swagger: "2.0", Info: Info{Title: "Pets", Version: "1.0"}, Paths: Paths{"/pets": PathItem{Get: op}}, Definitions: defs}
+	if errs := swag.Validate(); len(errs) > 0 {
+		t.Errorf("definitions do not validate:\n%s", ErrorList(errs).Indent("\t"))
+	}
+}
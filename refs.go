@@ -0,0 +1,51 @@
+package swagger2
+
+import (
+	"strings"
+)
+
+// ParseRef splits a local reference such as "#/definitions/Pet" into its section ("definitions") and name ("Pet").
+// Both are empty if the reference does not point inside the current document.
+func ParseRef(ref string) (section, name string) {
+	if !strings.HasPrefix(ref, "#/") {
+		return "", ""
+	}
+	parts := strings.SplitN(ref[2:], "/", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return unescapePointer(parts[0]), unescapePointer(parts[1])
+}
+
+// MakeRef builds a local reference from the given path tokens, such as "#/definitions/Pet" for ("definitions", "Pet").
+func MakeRef(tokens ...string) string {
+	ref := "#"
+	for _, t := range tokens {
+		ref += "/" + escapePointer(t)
+	}
+	return ref
+}
+
+// RefName returns the name of the definition a reference points at. Besides "#/definitions/Pet" it accepts the
+// short form "Pet" used by some documents, as long as a definition of that name exists.
+func (d Definitions) RefName(ref string) (string, bool) {
+	if section, name := ParseRef(ref); section == "definitions" {
+		return name, true
+	}
+	if ref != "" && !strings.ContainsAny(ref, "#/") {
+		if _, ok := d[ref]; ok {
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+// escapePointer escapes a JSON Pointer reference token.
+func escapePointer(s string) string {
+	return strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+}
+
+// unescapePointer reverses escapePointer.
+func unescapePointer(s string) string {
+	return strings.Replace(strings.Replace(s, "~1", "/", -1), "~0", "~", -1)
+}
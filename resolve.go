@@ -0,0 +1,232 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFile reads a document from a file, as YAML or JSON depending on its extension, along with the files it
+// refers to with relative references such as "definitions/Pet.yaml" or "common.yaml#/definitions/Error". An
+// entry of the definitions, parameters or responses of the document that only refers to a file becomes that file,
+// and the other references to the file become local references to the entry, so that a document written by Split
+// loads back as it was. Other references to files are replaced by what they refer to, which must not refer back to
+// itself. References to other hosts are not supported.
+func LoadFile(name string) (*Swagger, error) {
+	r := &resolver{files: make(map[string]interface{}), locals: make(map[string]string), resolving: make(map[string]bool)}
+	root, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	r.root = root
+	doc, err := r.load(root)
+	if err != nil {
+		return nil, err
+	}
+	top, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: not a document", name)
+	}
+	type entry struct {
+		section, name, key string
+	}
+	entries := make([]entry, 0)
+	for _, section := range []string{"definitions", "parameters", "responses"} {
+		m, _ := top[section].(map[string]interface{})
+		for _, n := range keys(m) {
+			if ref, ok := onlyRef(m[n]); ok && !strings.HasPrefix(ref, "#") {
+				key, err := r.key(root, ref)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %s", section, n, err)
+				}
+				if _, taken := r.locals[key]; !taken {
+					r.locals[key] = MakeRef(section, n)
+					entries = append(entries, entry{section, n, key})
+				}
+			}
+		}
+	}
+	done := make(map[string]bool)
+	for _, e := range entries {
+		done[e.section+"."+e.name] = true
+		v, err := r.target(e.key)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", e.section, e.name, err)
+		}
+		if v, err = r.resolve(v, fileOf(e.key)); err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", e.section, e.name, err)
+		}
+		top[e.section].(map[string]interface{})[e.name] = v
+	}
+	for k, v := range top {
+		if m, ok := v.(map[string]interface{}); ok && (k == "definitions" || k == "parameters" || k == "responses") {
+			for n, entry := range m {
+				if done[k+"."+n] {
+					continue
+				}
+				if m[n], err = r.resolve(entry, root); err != nil {
+					return nil, fmt.Errorf("%s.%s: %s", k, n, err)
+				}
+			}
+			continue
+		}
+		if top[k], err = r.resolve(v, root); err != nil {
+			return nil, fmt.Errorf("%s: %s", k, err)
+		}
+	}
+	b, err := json.Marshal(top)
+	if err != nil {
+		return nil, err
+	}
+	return LoadJson(b)
+}
+
+// resolver loads the files of a document and resolves the references between them
+type resolver struct {
+	root      string                 // the absolute name of the root file
+	files     map[string]interface{} // the content of the files loaded so far, by absolute name
+	locals    map[string]string      // the local references that replace references to files, by key
+	resolving map[string]bool        // the keys being resolved, to catch references to themselves
+}
+
+// load returns the content of a file, read as YAML or JSON depending on its extension
+func (r *resolver) load(name string) (interface{}, error) {
+	if v, ok := r.files[name]; ok {
+		return v, nil
+	}
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(b, &v)
+		v = jsonValue(v)
+	} else {
+		err = json.Unmarshal(b, &v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+	r.files[name] = v
+	return v, nil
+}
+
+// key returns the absolute file name and fragment that a reference made from a file points at, such as
+// "/api/definitions/Pet.yaml#" or "/api/common.yaml#/definitions/Error"
+func (r *resolver) key(from, ref string) (string, error) {
+	file, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, fragment = ref[:i], ref[i+1:]
+	}
+	if strings.Contains(file, "://") {
+		return "", fmt.Errorf("%s: references to other hosts are not supported", ref)
+	}
+	if file == "" {
+		file = from
+	} else if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(from), filepath.FromSlash(file))
+	}
+	return filepath.Clean(file) + "#" + fragment, nil
+}
+
+// fileOf returns the file name of a key
+func fileOf(key string) string {
+	return key[:strings.LastIndex(key, "#")]
+}
+
+// target returns what a key points at, following the JSON Pointer of its fragment
+func (r *resolver) target(key string) (interface{}, error) {
+	i := strings.LastIndex(key, "#")
+	v, err := r.load(key[:i])
+	if err != nil {
+		return nil, err
+	}
+	fragment := key[i+1:]
+	if fragment == "" {
+		return v, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = unescapePointer(token)
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = node[token]; !ok {
+				return nil, fmt.Errorf("%s: %s not found", filepath.Base(key), token)
+			}
+		case []interface{}:
+			var n int
+			if _, err := fmt.Sscan(token, &n); err != nil || n < 0 || n >= len(node) {
+				return nil, fmt.Errorf("%s: %s not found", filepath.Base(key), token)
+			}
+			v = node[n]
+		default:
+			return nil, fmt.Errorf("%s: %s not found", filepath.Base(key), token)
+		}
+	}
+	return v, nil
+}
+
+// resolve returns a copy of a value of a file, with its references to other files replaced by local references
+// or by what they point at. Local references of the root file are kept.
+func (r *resolver) resolve(v interface{}, file string) (interface{}, error) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok && !(file == r.root && strings.HasPrefix(ref, "#")) {
+			key, err := r.key(file, ref)
+			if err != nil {
+				return nil, err
+			}
+			if local, ok := r.locals[key]; ok {
+				return map[string]interface{}{"$ref": local}, nil
+			}
+			if fileOf(key) == r.root && strings.HasPrefix(key[len(r.root):], "#/") {
+				return map[string]interface{}{"$ref": key[len(r.root):]}, nil
+			}
+			if r.resolving[key] {
+				return nil, fmt.Errorf("%s refers to itself", ref)
+			}
+			r.resolving[key] = true
+			defer delete(r.resolving, key)
+			target, err := r.target(key)
+			if err != nil {
+				return nil, err
+			}
+			return r.resolve(target, fileOf(key))
+		}
+		m := make(map[string]interface{}, len(node))
+		for k, e := range node {
+			resolved, err := r.resolve(e, file)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = resolved
+		}
+		return m, nil
+	case []interface{}:
+		l := make([]interface{}, len(node))
+		for i, e := range node {
+			resolved, err := r.resolve(e, file)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = resolved
+		}
+		return l, nil
+	}
+	return v, nil
+}
+
+// onlyRef returns the reference of a value that is nothing but a reference
+func onlyRef(v interface{}) (string, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
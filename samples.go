@@ -0,0 +1,188 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Sample returns a value that matches a schema, such as a request body to start from. It uses the example of the
+// schema or of the definition it refers to, then its default, then the first of its enum values, and otherwise
+// makes up a value of the right type and format. Objects get a value for each property that is not read only.
+// A definition that refers to itself is given a null value where it recurs. Maps come from JSON-compatible
+// map[string]interface{} values, even when the examples were loaded from YAML.
+func (d Definitions) Sample(s *Schema) interface{} {
+	return d.sample(s, make(map[string]bool))
+}
+
+// SampleItems returns a value that matches a parameter, header or items definition, as Sample does for schemas.
+func (d Definitions) SampleItems(i *ItemsDef) interface{} {
+	return d.sample(&Schema{ItemsDef: *i}, make(map[string]bool))
+}
+
+func (d Definitions) sample(s *Schema, seen map[string]bool) interface{} {
+	if s.Example != nil {
+		return jsonValue(s.Example)
+	}
+	if s.Ref != "" {
+		name, ok := d.RefName(s.Ref)
+		def, found := d[name]
+		if !ok || !found || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return d.sample(&def, seen)
+	}
+	if s.Default != nil {
+		return jsonValue(s.Default)
+	}
+	if len(s.Enum) > 0 {
+		return jsonValue(s.Enum[0])
+	}
+	if len(s.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for i := range s.AllOf {
+			if m, ok := d.sample(&s.AllOf[i], seen).(map[string]interface{}); ok {
+				for k, v := range m {
+					merged[k] = v
+				}
+			}
+		}
+		if m, ok := d.object(s, seen).(map[string]interface{}); ok {
+			for k, v := range m {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+	switch s.Type {
+	case "array":
+		if s.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{d.sample(&Schema{ItemsDef: *s.Items}, seen)}
+	case "integer":
+		if s.Minimum != nil {
+			return int64(*s.Minimum)
+		}
+		return 0
+	case "number":
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		return sampleString(s.Format)
+	case "file":
+		return nil
+	case "object", "":
+		return d.object(s, seen)
+	}
+	return nil
+}
+
+// object returns a sample of an object schema, or nil if the schema has no type and no properties
+func (d Definitions) object(s *Schema, seen map[string]bool) interface{} {
+	if s.Type == "" && len(s.Properties) == 0 && s.AdditionalProperties == nil {
+		return nil
+	}
+	m := make(map[string]interface{}, len(s.Properties))
+	for name, p := range s.Properties {
+		if p.ReadOnly != nil && *p.ReadOnly {
+			continue
+		}
+		m[name] = d.sample(&p, seen)
+	}
+	if s.AdditionalProperties != nil && len(s.Properties) == 0 {
+		m["key"] = d.sample(&Schema{ItemsDef: *s.AdditionalProperties}, seen)
+	}
+	return m
+}
+
+// EncodeValue returns the strings a value of a parameter is sent as. Arrays are joined with the separator of the
+// collection format, csv by default, except with the multi format, where each element is sent on its own. Other
+// values give a single string, with objects written as JSON.
+func (i *ItemsDef) EncodeValue(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return []string{valueString(v)}
+	}
+	values := make([]string, len(list))
+	for k, e := range list {
+		values[k] = valueString(e)
+	}
+	if i.CollectionFormat == "multi" {
+		return values
+	}
+	sep := map[string]string{"ssv": " ", "tsv": "\t", "pipes": "|"}[i.CollectionFormat]
+	if sep == "" {
+		sep = ","
+	}
+	return []string{strings.Join(values, sep)}
+}
+
+// valueString writes a single value as a string
+func valueString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// sampleString returns a string in the given format
+func sampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "1970-01-01T00:00:00Z"
+	case "date":
+		return "1970-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "byte":
+		return "c3RyaW5n"
+	case "password":
+		return "password"
+	}
+	return "string"
+}
+
+// jsonValue converts the maps YAML decodes into maps with string keys, so that the value can be marshaled as JSON
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = jsonValue(e)
+		}
+		return l
+	}
+	return v
+}
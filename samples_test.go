@@ -0,0 +1,84 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const sampleDoc = `
+swagger: "2.0"
+info: {title: Samples, version: "1"}
+schemes: [http, https]
+host: api.example.com
+basePath: /v2/
+parameters:
+  limit: {name: limit, in: query, type: integer, default: 20}
+paths:
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+      - {name: verbose, in: query, type: boolean}
+    get:
+      parameters:
+        - $ref: "#/parameters/limit"
+        - {name: verbose, in: query, type: string}
+        - {name: X-Trace, in: header, type: string}
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    example: {name: Rex, tags: [good]}
+  Node:
+    allOf:
+      - $ref: Base
+      - properties:
+          children: {type: array, items: {$ref: "#/definitions/Node"}}
+          kind: {type: string, enum: [leaf, branch]}
+          seen: {type: string, format: date-time}
+          weight: {type: number, minimum: 1.5}
+          labels: {type: object, additionalProperties: {type: integer}}
+  Base:
+    properties:
+      id: {type: integer, readOnly: true}
+      name: {type: string}
+`
+
+func TestSamples(t *testing.T) {
+	swag, err := LoadYaml([]byte(sampleDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		schema Schema
+		want   string
+	}{
+		{Schema{ItemsDef: ItemsDef{Ref: "#/definitions/Pet"}}, `{"name":"Rex","tags":["good"]}`},
+		{Schema{ItemsDef: ItemsDef{Ref: "#/definitions/Node"}}, `{"children":[null],"kind":"leaf","labels":{"key":0},"name":"string","seen":"1970-01-01T00:00:00Z","weight":1.5}`},
+		{Schema{ItemsDef: ItemsDef{Type: "string", Format: "uuid"}}, `"00000000-0000-0000-0000-000000000000"`},
+		{Schema{}, `null`},
+	} {
+		b, err := json.Marshal(swag.Definitions.Sample(&c.schema))
+		if err != nil || string(b) != c.want {
+			t.Errorf("expected sample %s, got %s (%v)", c.want, b, err)
+		}
+	}
+
+	limit := swag.Parameters["limit"]
+	for _, c := range []struct {
+		def  ItemsDef
+		v    interface{}
+		want []string
+	}{
+		{ItemsDef{Type: "array"}, []interface{}{"a", 1}, []string{"a,1"}},
+		{ItemsDef{Type: "array", CollectionFormat: "pipes"}, []interface{}{"a", "b"}, []string{"a|b"}},
+		{ItemsDef{Type: "array", CollectionFormat: "multi"}, []interface{}{"a", "b"}, []string{"a", "b"}},
+		{ItemsDef{Type: "boolean"}, true, []string{"true"}},
+		{ItemsDef{Type: "integer"}, swag.Definitions.SampleItems(&limit.ItemsDef), []string{"20"}},
+	} {
+		if got := c.def.EncodeValue(c.v); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("expected %q for %v, got %q", c.want, c.v, got)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Package snippet renders the operations of Swagger 2 documents as ready to run curl and HTTPie commands.
+package snippet
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Options configures a snippet.
+type Options struct {
+	// Values holds parameter values by name. The value of the body parameter is sent as it is if it is a string,
+	// and as JSON otherwise. Required parameters without a value get a sample value, made from their schema for
+	// the body, and optional ones are left out.
+	Values map[string]interface{}
+
+	// BaseURL replaces the URL made of the schemes, host and base path of the document.
+	BaseURL string
+}
+
+// request is what a snippet sends
+type request struct {
+	method  string
+	url     string
+	headers []header
+	auth    string // the user and password of basic authentication
+	body    string
+	form    []field
+	files   bool // whether the form is multipart
+}
+
+// header is a header to send
+type header struct {
+	name, value string
+}
+
+// field is a form field, or a file to upload
+type field struct {
+	name, value string
+	file        bool
+}
+
+// Curl renders an operation, given by its path and HTTP method, as a curl command.
+func Curl(swag *swagger2.Swagger, method, path string, opts Options) (string, error) {
+	r, err := build(swag, method, path, opts)
+	if err != nil {
+		return "", err
+	}
+	first := "curl "
+	switch r.method {
+	case "GET":
+	case "HEAD":
+		first += "--head "
+	default:
+		first += "-X " + r.method + " "
+	}
+	args := []string{first + quote(r.url)}
+	for _, h := range r.headers {
+		args = append(args, "-H "+quote(h.name+": "+h.value))
+	}
+	if r.auth != "" {
+		args = append(args, "-u "+quote(r.auth))
+	}
+	for _, f := range r.form {
+		switch {
+		case f.file:
+			args = append(args, "-F "+quote(f.name+"=@"+f.value))
+		case r.files:
+			args = append(args, "-F "+quote(f.name+"="+f.value))
+		default:
+			args = append(args, "--data-urlencode "+quote(f.name+"="+f.value))
+		}
+	}
+	if r.body != "" {
+		args = append(args, "--data-raw "+quote(r.body))
+	}
+	return strings.Join(args, " \\\n  "), nil
+}
+
+// HTTPie renders an operation, given by its path and HTTP method, as an HTTPie command.
+func HTTPie(swag *swagger2.Swagger, method, path string, opts Options) (string, error) {
+	r, err := build(swag, method, path, opts)
+	if err != nil {
+		return "", err
+	}
+	first := "http "
+	if r.form != nil {
+		if r.files {
+			first += "--multipart "
+		} else {
+			first += "--form "
+		}
+	}
+	if r.auth != "" {
+		first += "-a " + quote(r.auth) + " "
+	}
+	args := []string{first + r.method + " " + quote(r.url)}
+	for _, h := range r.headers {
+		args = append(args, quote(h.name+":"+h.value))
+	}
+	for _, f := range r.form {
+		if f.file {
+			args = append(args, quote(f.name+"@"+f.value))
+		} else {
+			args = append(args, quote(f.name+"="+f.value))
+		}
+	}
+	if r.body != "" {
+		args = append(args, "--raw "+quote(r.body))
+	}
+	return strings.Join(args, " \\\n  "), nil
+}
+
+// build collects what a snippet sends for an operation
+func build(swag *swagger2.Swagger, method, path string, opts Options) (*request, error) {
+	method = strings.ToLower(method)
+	item, ok := swag.Paths[path]
+	if !ok {
+		return nil, fmt.Errorf("paths.%s: no such path", path)
+	}
+	op := item.Operation(method)
+	if op == nil {
+		return nil, fmt.Errorf("paths.%s.%s: no such operation", path, method)
+	}
+	r := &request{method: strings.ToUpper(method)}
+	base := opts.BaseURL
+	if base == "" {
+		base = swag.BaseURL()
+	}
+	consumes := op.Consumes
+	if consumes == nil {
+		consumes = swag.Consumes
+	}
+	produces := op.Produces
+	if produces == nil {
+		produces = swag.Produces
+	}
+	query := make([]string, 0)
+	for _, p := range swag.OperationParameters(path, &item, op) {
+		v, given := opts.Values[p.Name]
+		required := p.In == "path" || p.Required != nil && *p.Required
+		if !given && !required {
+			continue
+		}
+		if p.In == "body" {
+			if !given && p.Schema != nil {
+				v = swag.Definitions.Sample(p.Schema)
+			}
+			if s, ok := v.(string); ok {
+				r.body = s
+			} else if b, err := json.MarshalIndent(v, "", "  "); err == nil {
+				r.body = string(b)
+			} else {
+				return nil, fmt.Errorf("paths.%s.%s.parameters.%s: %s", path, method, p.Name, err)
+			}
+			contentType := "application/json"
+			if len(consumes) > 0 && !lists.Contains(consumes, contentType) {
+				contentType = consumes[0]
+			}
+			r.headers = append(r.headers, header{name: "Content-Type", value: contentType})
+			continue
+		}
+		if !given {
+			v = swag.Definitions.SampleItems(&p.ItemsDef)
+		}
+		values := p.EncodeValue(v)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			for _, s := range values {
+				query = append(query, url.QueryEscape(p.Name)+"="+url.QueryEscape(s))
+			}
+		case "header":
+			r.headers = append(r.headers, header{name: p.Name, value: strings.Join(values, ",")})
+		case "formData":
+			if r.form == nil {
+				r.form = make([]field, 0)
+				r.files = lists.Contains(consumes, "multipart/form-data")
+			}
+			if p.Type == "file" {
+				file := p.Name
+				if s, ok := v.(string); ok && given {
+					file = s
+				}
+				r.form = append(r.form, field{name: p.Name, value: file, file: true})
+				r.files = true
+				continue
+			}
+			for _, s := range values {
+				r.form = append(r.form, field{name: p.Name, value: s})
+			}
+		}
+	}
+	if len(produces) > 0 {
+		accept := produces[0]
+		if lists.Contains(produces, "application/json") {
+			accept = "application/json"
+		}
+		r.headers = append([]header{{name: "Accept", value: accept}}, r.headers...)
+	}
+	security := op.Security
+	if security == nil {
+		security = swag.Security
+	}
+	q := r.authorize(swag, security)
+	query = append(query, q...)
+	r.url = strings.TrimRight(base, "/") + path
+	if len(query) > 0 {
+		r.url += "?" + strings.Join(query, "&")
+	}
+	return r, nil
+}
+
+// authorize adds the credentials of the first security requirement, as references to environment variables named
+// after the security definitions, such as $PETSTORE_AUTH_TOKEN. It returns the query parameters of API keys sent
+// in the query.
+func (r *request) authorize(swag *swagger2.Swagger, security []swagger2.Security) []string {
+	if len(security) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(security[0]))
+	for n := range security[0] {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	query := make([]string, 0)
+	for _, n := range names {
+		def, ok := swag.SecurityDefinitions[n]
+		if !ok {
+			continue
+		}
+		switch def.Type {
+		case "basic":
+			r.auth = env(envName(n, "username")) + ":" + env(envName(n, "password"))
+		case "apiKey":
+			if def.In == "query" {
+				query = append(query, url.QueryEscape(def.Name)+"="+env(envName(n, "")))
+			} else {
+				r.headers = append(r.headers, header{name: def.Name, value: env(envName(n, ""))})
+			}
+		case "oauth2":
+			r.headers = append(r.headers, header{name: "Authorization", value: "Bearer " + env(envName(n, "token"))})
+		}
+	}
+	return query
+}
+
+// envName returns the name of the environment variable for a credential, such as API_KEY for api_key or
+// PETSTORE_AUTH_TOKEN for the token of petstore_auth
+func envName(name, suffix string) string {
+	var b strings.Builder
+	prev := '_'
+	for i, c := range name {
+		switch {
+		case unicode.IsUpper(c) && i > 0 && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			b.WriteByte('_')
+			b.WriteRune(c)
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			b.WriteRune(unicode.ToUpper(c))
+		default:
+			if prev != '_' {
+				b.WriteByte('_')
+			}
+			c = '_'
+		}
+		prev = c
+	}
+	s := strings.Trim(b.String(), "_")
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "API_" + s
+	}
+	if suffix != "" {
+		s += "_" + strings.ToUpper(suffix)
+	}
+	return s
+}
+
+// env marks a reference to an environment variable in an argument, for quote to let the shell expand it
+func env(name string) string {
+	return "\x00" + name + "\x00"
+}
+
+// quote quotes an argument for a POSIX shell, unless it is made only of characters the shell leaves alone. An
+// argument that refers to environment variables is quoted between double quotes, so that the shell expands them.
+func quote(s string) string {
+	if strings.Contains(s, "\x00") {
+		parts := strings.Split(s, "\x00")
+		escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`)
+		for i := range parts {
+			if i%2 == 0 {
+				parts[i] = escape.Replace(parts[i])
+			} else {
+				parts[i] = "${" + parts[i] + "}"
+			}
+		}
+		return `"` + strings.Join(parts, "") + `"`
+	}
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,164 @@
+package snippet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+const shelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+host: shelter.example.com
+basePath: /v1
+schemes: [https]
+produces: [application/json, application/xml]
+securityDefinitions:
+  api_key: {type: apiKey, name: X-Key, in: header}
+  queryKey: {type: apiKey, name: key, in: query}
+  login: {type: basic}
+  petstore_auth: {type: oauth2, flow: implicit, authorizationUrl: "https://shelter.example.com/auth", scopes: {}}
+security:
+  - api_key: []
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: kind, in: query, type: string, enum: [cat, dog], required: true}
+        - {name: ids, in: query, type: array, items: {type: integer}, collectionFormat: multi}
+        - {name: fields, in: query, type: array, items: {type: string}, collectionFormat: ssv}
+        - {name: X-Trace, in: header, type: string}
+      responses:
+        200: {description: OK}
+    post:
+      security:
+        - petstore_auth: []
+      parameters:
+        - {name: pet, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201: {description: Created.}
+  /pets/{petId}/photo:
+    put:
+      security:
+        - login: []
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: petId, in: path, required: true, type: string}
+        - {name: photo, in: formData, type: file, required: true}
+        - {name: caption, in: formData, type: string}
+      responses:
+        200: {description: OK}
+  /pets/{petId}/notes:
+    post:
+      security: []
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+        - {name: note, in: formData, type: string, required: true}
+      responses:
+        200: {description: OK}
+    head:
+      security:
+        - queryKey: []
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+      responses:
+        200: {description: OK}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string, example: "Rex's"}
+`
+
+func TestSnippets(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		method, path string
+		values       map[string]interface{}
+		curl, httpie string
+	}{
+		{
+			"GET", "/pets", map[string]interface{}{"ids": []interface{}{1, 2}, "fields": []interface{}{"name", "tag"}, "X-Trace": "a b"},
+			"curl 'https://shelter.example.com/v1/pets?kind=cat&ids=1&ids=2&fields=name+tag' \\\n" +
+				"  -H 'Accept: application/json' \\\n  -H 'X-Trace: a b' \\\n  -H \"X-Key: ${API_KEY}\"",
+			"http GET 'https://shelter.example.com/v1/pets?kind=cat&ids=1&ids=2&fields=name+tag' \\\n" +
+				"  Accept:application/json \\\n  'X-Trace:a b' \\\n  \"X-Key:${API_KEY}\"",
+		},
+		{
+			"post", "/pets", nil,
+			"curl -X POST https://shelter.example.com/v1/pets \\\n" +
+				"  -H 'Accept: application/json' \\\n  -H 'Content-Type: application/json' \\\n" +
+				"  -H \"Authorization: Bearer ${PETSTORE_AUTH_TOKEN}\" \\\n" +
+				"  --data-raw '{\n  \"name\": \"Rex'\\''s\"\n}'",
+			"http POST https://shelter.example.com/v1/pets \\\n" +
+				"  Accept:application/json \\\n  Content-Type:application/json \\\n  \"Authorization:Bearer ${PETSTORE_AUTH_TOKEN}\" \\\n" +
+				"  --raw '{\n  \"name\": \"Rex'\\''s\"\n}'",
+		},
+		{
+			"PUT", "/pets/{petId}/photo", map[string]interface{}{"petId": "a/b", "photo": "rex.jpg"},
+			"curl -X PUT https://shelter.example.com/v1/pets/a%2Fb/photo \\\n  -H 'Accept: application/json' \\\n" +
+				"  -u \"${LOGIN_USERNAME}:${LOGIN_PASSWORD}\" \\\n  -F photo=@rex.jpg",
+			"http --multipart -a \"${LOGIN_USERNAME}:${LOGIN_PASSWORD}\" PUT https://shelter.example.com/v1/pets/a%2Fb/photo \\\n" +
+				"  Accept:application/json \\\n  photo@rex.jpg",
+		},
+		{
+			"POST", "/pets/{petId}/notes", map[string]interface{}{"note": "it's $5"},
+			"curl -X POST https://shelter.example.com/v1/pets/0/notes \\\n  -H 'Accept: application/json' \\\n" +
+				"  --data-urlencode 'note=it'\\''s $5'",
+			"http --form POST https://shelter.example.com/v1/pets/0/notes \\\n  Accept:application/json \\\n  'note=it'\\''s $5'",
+		},
+		{
+			"HEAD", "/pets/{petId}/notes", map[string]interface{}{"petId": 7},
+			"curl --head \"https://shelter.example.com/v1/pets/7/notes?key=${QUERY_KEY}\" \\\n  -H 'Accept: application/json'",
+			"http HEAD \"https://shelter.example.com/v1/pets/7/notes?key=${QUERY_KEY}\" \\\n  Accept:application/json",
+		},
+	} {
+		curl, err := Curl(swag, c.method, c.path, Options{Values: c.values})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if curl != c.curl {
+			t.Errorf("%s %s: expected\n%s\ngot\n%s", c.method, c.path, c.curl, curl)
+		}
+		httpie, err := HTTPie(swag, c.method, c.path, Options{Values: c.values})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if httpie != c.httpie {
+			t.Errorf("%s %s: expected\n%s\ngot\n%s", c.method, c.path, c.httpie, httpie)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(shelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	curl, err := Curl(swag, "POST", "/pets", Options{BaseURL: "http://localhost:8080/", Values: map[string]interface{}{"pet": `{"name":"Tom"}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(curl, " http://localhost:8080/pets ") || !strings.HasSuffix(curl, `--data-raw '{"name":"Tom"}'`) {
+		t.Errorf("unexpected command\n%s", curl)
+	}
+	if _, err := Curl(swag, "GET", "/cats", Options{}); err == nil || err.Error() != "paths./cats: no such path" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := HTTPie(swag, "DELETE", "/pets", Options{}); err == nil || err.Error() != "paths./pets.delete: no such operation" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	for in, want := range map[string]string{"api_key": "API_KEY", "petstoreAuth": "PETSTORE_AUTH", "OAuth2": "OAUTH2", "x-key": "X_KEY", "2fa": "API_2FA"} {
+		if got := envName(in, ""); got != want {
+			t.Errorf("%s: expected %s, got %s", in, want, got)
+		}
+	}
+}
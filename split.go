@@ -0,0 +1,181 @@
+package swagger2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/babelrpc/swagger2/internal/lists"
+	"gopkg.in/yaml.v2"
+)
+
+// SplitOptions configures Split.
+type SplitOptions struct {
+	Root  string // The name of the root file, "swagger.yaml" by default. Its extension chooses YAML or JSON for all files.
+	Paths bool   // Whether each path item gets its own file too
+}
+
+// Split writes a document as a set of files, the reverse of LoadFile. Each definition, shared parameter and shared
+// response gets its own file in the definitions, parameters and responses directories, and each path item one in
+// the paths directory if asked to. The entries of the root file refer to those files, and references between
+// files are relative, such as "../definitions/Pet.yaml" in a path item. Split returns the content of the files by
+// their slash-separated names.
+func Split(s *Swagger, opts SplitOptions) (map[string][]byte, error) {
+	root := opts.Root
+	if root == "" {
+		root = "swagger.yaml"
+	}
+	ext := path.Ext(root)
+	b, err := s.Json()
+	if err != nil {
+		return nil, err
+	}
+	var top map[string]interface{}
+	if err := json.Unmarshal(b, &top); err != nil {
+		return nil, err
+	}
+	sp := &splitter{defs: s.Definitions, root: root, files: make(map[string]string)}
+	sections := []string{"definitions", "parameters", "responses"}
+	if opts.Paths {
+		sections = append(sections, "paths")
+	}
+	used := make(map[string]bool)
+	for _, section := range sections {
+		m, _ := top[section].(map[string]interface{})
+		for _, name := range keys(m) {
+			base := fileName.ReplaceAllString(strings.Trim(name, "/"), "_")
+			if base == "" {
+				base = "root"
+			}
+			file := section + "/" + base + ext
+			for n := 2; used[strings.ToLower(file)]; n++ {
+				file = fmt.Sprintf("%s/%s_%d%s", section, base, n, ext)
+			}
+			used[strings.ToLower(file)] = true
+			sp.files[MakeRef(section, name)] = file
+		}
+	}
+	files := make(map[string][]byte)
+	for _, section := range sections {
+		m, _ := top[section].(map[string]interface{})
+		for name, v := range m {
+			file := sp.files[MakeRef(section, name)]
+			if files[file], err = marshalFile(sp.rewrite(v, section), ext, nil); err != nil {
+				return nil, err
+			}
+			m[name] = map[string]interface{}{"$ref": file}
+		}
+	}
+	for k, v := range top {
+		if k != "definitions" && k != "parameters" && k != "responses" && (k != "paths" || !opts.Paths) {
+			top[k] = sp.rewrite(v, "")
+		}
+	}
+	order := []string{"swagger", "info", "host", "basePath", "schemes", "consumes", "produces", "paths", "definitions",
+		"parameters", "responses", "securityDefinitions", "security", "tags", "externalDocs"}
+	if files[root], err = marshalFile(top, ext, order); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// fileName matches the characters that are left out of file names
+var fileName = regexp.MustCompile(`[^A-Za-z0-9._{}-]+`)
+
+// splitter rewrites the references of a document being split
+type splitter struct {
+	defs  Definitions
+	root  string
+	files map[string]string // the files of what is split, by local reference
+}
+
+// rewrite returns a value of the directory of a file with its local references made relative to that directory
+func (sp *splitter) rewrite(v interface{}, dir string) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, e := range node {
+			if ref, ok := e.(string); ok && k == "$ref" {
+				node[k] = sp.ref(ref, dir)
+			} else {
+				node[k] = sp.rewrite(e, dir)
+			}
+		}
+	case []interface{}:
+		for i, e := range node {
+			node[i] = sp.rewrite(e, dir)
+		}
+	}
+	return v
+}
+
+// ref returns a local reference as seen from a directory: the file it was split into, or the root file
+func (sp *splitter) ref(ref, dir string) string {
+	if name, ok := sp.defs.RefName(ref); ok {
+		ref = MakeRef("definitions", name)
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return ref
+	}
+	target, ok := sp.files[ref]
+	if !ok {
+		if dir == "" {
+			return ref
+		}
+		target = sp.root + ref
+	}
+	if dir == "" {
+		return target
+	}
+	if path.Dir(target) == dir {
+		return path.Base(target)
+	}
+	return "../" + target
+}
+
+// marshalFile writes a value as YAML or JSON depending on an extension, with the keys of an object in the given
+// order first
+func marshalFile(v interface{}, ext string, order []string) ([]byte, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok || order == nil {
+		if ext == ".json" {
+			return json.MarshalIndent(v, "", "  ")
+		}
+		return yaml.Marshal(v)
+	}
+	ordered := make([]string, 0, len(m))
+	for _, k := range order {
+		if _, ok := m[k]; ok {
+			ordered = append(ordered, k)
+		}
+	}
+	for _, k := range keys(m) {
+		if !lists.Contains(order, k) {
+			ordered = append(ordered, k)
+		}
+	}
+	if ext != ".json" {
+		slice := make(yaml.MapSlice, len(ordered))
+		for i, k := range ordered {
+			slice[i] = yaml.MapItem{Key: k, Value: m[k]}
+		}
+		return yaml.Marshal(slice)
+	}
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, k := range ordered {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		name, _ := json.Marshal(k)
+		value, err := json.MarshalIndent(m[k], "  ", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", name, value)
+	}
+	b.WriteString("\n}")
+	return b.Bytes(), nil
+}
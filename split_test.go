@@ -0,0 +1,148 @@
+package swagger2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const splitShelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+basePath: /shelter
+parameters:
+  limit: {name: limit, in: query, type: integer}
+responses:
+  NotFound: {description: Not found., schema: {$ref: "#/definitions/Error"}}
+paths:
+  /:
+    get:
+      responses:
+        200: {description: OK}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+    get:
+      parameters:
+        - $ref: "#/parameters/limit"
+      responses:
+        200: {description: OK, schema: {$ref: "#/definitions/Pet"}}
+        404: {description: Not found., schema: {$ref: Error}}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+      owner: {$ref: "#/definitions/Owner"}
+  Owner:
+    type: object
+    properties:
+      pets: {type: array, items: {$ref: "#/definitions/Pet"}}
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+`
+
+func TestSplit(t *testing.T) {
+	swag, err := LoadYaml([]byte(splitShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []SplitOptions{{}, {Root: "api.json", Paths: true}} {
+		files, err := Split(swag, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		for name, b := range files {
+			file := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(file, b, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		root := opts.Root
+		if root == "" {
+			root = "swagger.yaml"
+		}
+		loaded, err := LoadFile(filepath.Join(dir, root))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changes := Diff(swag, loaded); len(changes) > 0 {
+			t.Errorf("%s: unexpected changes\n%s", root, changes)
+		}
+		if errs := loaded.Validate(); len(errs) > 0 {
+			t.Errorf("%s: unexpected errors %v", root, errs)
+		}
+		if !opts.Paths {
+			if len(files) != 6 || !strings.Contains(string(files["definitions/Pet.yaml"]), "$ref: Owner.yaml") ||
+				!strings.Contains(string(files["responses/NotFound.yaml"]), "$ref: ../definitions/Error.yaml") ||
+				!strings.Contains(string(files["swagger.yaml"]), "$ref: definitions/Pet.yaml") {
+				t.Errorf("unexpected files %v", files)
+			}
+			continue
+		}
+		if len(files) != 8 || !strings.Contains(string(files["paths/pets_{id}.json"]), `"$ref": "../parameters/limit.json"`) ||
+			!strings.Contains(string(files["paths/root.json"]), "OK") ||
+			!strings.HasPrefix(string(files["api.json"]), "{\n  \"swagger\": \"2.0\",\n  \"info\": {") {
+			t.Errorf("unexpected files %v", files)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"swagger.yaml": `
+swagger: "2.0"
+info: {title: Shelter, version: "1.0"}
+paths:
+  /pets:
+    $ref: paths.yaml#/pets
+definitions:
+  Pet: {$ref: "common.yaml#/Pet"}
+`,
+		"paths.yaml": `
+pets:
+  get:
+    responses:
+      200: {description: OK, schema: {$ref: "common.yaml#/Pet"}}
+      default: {description: Error, schema: {$ref: "common.yaml#/Error"}}
+`,
+		"common.yaml": `
+Pet: {type: object, properties: {self: {$ref: "#/Pet"}}}
+Error: {type: object, properties: {message: {type: string}}}
+Loop: {$ref: "#/Loop"}
+`,
+		"loop.yaml": `
+swagger: "2.0"
+info: {title: Loop, version: "1.0"}
+paths: {}
+definitions:
+  Loop: {type: object, properties: {next: {$ref: "common.yaml#/Loop"}}}
+`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	swag, err := LoadFile(filepath.Join(dir, "swagger.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := swag.Paths["/pets"].Get.Responses
+	if responses["200"].Schema.Ref != "#/definitions/Pet" || responses["default"].Schema.Properties["message"].Type != "string" ||
+		swag.Definitions["Pet"].Properties["self"].Ref != "#/definitions/Pet" {
+		t.Errorf("unexpected document %+v", swag)
+	}
+	if _, err := LoadFile(filepath.Join(dir, "loop.yaml")); err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
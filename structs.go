@@ -78,8 +78,7 @@ type Operation struct {
 	Schemes      []string       `yaml:"schemes,omitempty" json:"schemes,omitempty"`           // The transfer protocol for the operation. Values MUST be from the list: "http", "https", "ws", "wss". The value overrides the Swagger Object schemes definition.
 	Deprecated   bool           `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`     // Declares this operation to be deprecated. Usage of the declared operation should be refrained. Default value is false.
 	Security     []Security     `yaml:"security,omitempty" json:"security,omitempty"`         // A declaration of which security schemes are applied for this operation. The list of values describes alternative security schemes that can be used (that is, there is a logical OR between the security requirements). This definition overrides any declared top-level security. To remove a top-level security declaration, an empty array can be used.
-
-	// Vendor extensions: Allows extensions to the Swagger Schema. The field name MUST begin with x-, for example, x-internal-id. The value can be null, a primitive, an array or an object. See Vendor Extensions for further details.
+	Extensions   Extensions     `yaml:",inline" json:"-"`                                     // Vendor extensions, such as x-audience. Field names MUST begin with x-.
 }
 
 // Documentation allows referencing an external resource for extended documentation.
@@ -208,6 +207,7 @@ type Schema struct {
 	Xml           *Xml              `yaml:"xml,omitempty" json:"xml,omitempty"`                     // This MAY be used only on properties schemas. It has no effect on root schemas. Adds Additional metadata to describe the XML representation format of this property.
 	ExternalDocs  *Documentation    `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`   // Additional external documentation for this schema.
 	Example       interface{}       `yaml:"example,omitempty" json:"example,omitempty"`             // A free-form property to include a an example of an instance for this schema.
+	Extensions    Extensions        `yaml:",inline" json:"-"`                                       // Vendor extensions, such as x-nullable. Field names MUST begin with x-.
 }
 
 // Xml allows extra definitions when translating the JSON definition to XML. The XML Object contains additional information about the available options.
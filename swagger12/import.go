@@ -0,0 +1,476 @@
+package swagger12
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// primitives maps the Swagger 1.2 primitive types onto their Swagger 2 type
+var primitives = map[string]string{
+	"integer": "integer",
+	"number":  "number",
+	"string":  "string",
+	"boolean": "boolean",
+}
+
+// Import loads the API declaration of every resource in the listing and assembles them into a single Swagger 2
+// document. err is set if a declaration could not be loaded; the returned errors describe anything that could not
+// be converted exactly.
+func Import(listing *ResourceListing, loader Loader) (swag *swagger2.Swagger, lossy []error, err error) {
+	decls := make([]*ApiDeclaration, 0, len(listing.Apis))
+	for _, res := range listing.Apis {
+		buf, err := loader.Load(res.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to load %s: %s", res.Path, err)
+		}
+		decl, err := LoadDeclaration(buf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse %s: %s", res.Path, err)
+		}
+		decls = append(decls, decl)
+	}
+	swag, lossy = Convert(listing, decls...)
+	return swag, lossy, nil
+}
+
+// Convert assembles a resource listing and its already loaded API declarations into a single Swagger 2 document.
+// The returned errors describe anything that could not be converted exactly.
+func Convert(listing *ResourceListing, decls ...*ApiDeclaration) (*swagger2.Swagger, []error) {
+	c := &converter{
+		swag: &swagger2.Swagger{
+			Swagger: "2.0",
+			Info:    info(listing),
+			Paths:   make(swagger2.Paths),
+		},
+		errs: make([]error, 0),
+	}
+	for _, res := range listing.Apis {
+		if res.Description != "" {
+			c.swag.Tags = append(c.swag.Tags, swagger2.Tag{Name: tagName(res.Path), Description: res.Description})
+		}
+	}
+	names := make([]string, 0, len(listing.Authorizations))
+	for n := range listing.Authorizations {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		c.authorization(n, listing.Authorizations[n])
+	}
+	// models are shared between declarations, so they are all known before any are converted
+	c.models = make(map[string]bool)
+	for _, decl := range decls {
+		for n := range decl.Models {
+			c.models[n] = true
+		}
+	}
+	for i, decl := range decls {
+		c.declaration(i == 0, decl)
+	}
+	return c.swag, c.errs
+}
+
+// converter holds the state of a Swagger 1.2 import
+type converter struct {
+	swag   *swagger2.Swagger
+	models map[string]bool
+	errs   []error
+}
+
+// lossy records something that could not be converted exactly
+func (c *converter) lossy(where, format string, args ...interface{}) {
+	c.errs = append(c.errs, fmt.Errorf(where+": "+format, args...))
+}
+
+// info converts the metadata of the listing
+func info(listing *ResourceListing) swagger2.Info {
+	i := swagger2.Info{Title: "API", Version: listing.ApiVersion}
+	if i.Version == "" {
+		i.Version = "1.0"
+	}
+	if listing.Info != nil {
+		i.Title = listing.Info.Title
+		i.Description = listing.Info.Description
+		i.TermsOfService = listing.Info.TermsOfServiceUrl
+		if listing.Info.Contact != "" {
+			i.Contact = &swagger2.Contact{Email: listing.Info.Contact}
+		}
+		if listing.Info.License != "" || listing.Info.LicenseUrl != "" {
+			i.License = &swagger2.License{Name: listing.Info.License, Url: listing.Info.LicenseUrl}
+		}
+	}
+	return i
+}
+
+// tagName derives a tag from a resource path such as "/pet"
+func tagName(path string) string {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "/"), ".json")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	return path
+}
+
+// authorization converts an authorization scheme into a security definition
+func (c *converter) authorization(name string, a Authorization) {
+	where := "authorizations." + name
+	d := swagger2.SecurityDefinition{}
+	switch a.Type {
+	case "basicAuth":
+		d.Type = "basic"
+	case "apiKey":
+		d.Type, d.Name, d.In = "apiKey", a.Keyname, a.PassAs
+	case "oauth2":
+		d.Type = "oauth2"
+		d.Scopes = make(swagger2.Scopes)
+		for _, s := range a.Scopes {
+			d.Scopes[s.Scope] = s.Description
+		}
+		g := a.GrantTypes
+		switch {
+		case g != nil && g.AuthorizationCode != nil:
+			d.Flow = "accessCode"
+			d.AuthorizationUrl = g.AuthorizationCode.TokenRequestEndpoint.Url
+			d.TokenUrl = g.AuthorizationCode.TokenEndpoint.Url
+			if g.Implicit != nil {
+				c.lossy(where, "only one oauth2 grant type is supported; the implicit grant was dropped")
+			}
+		case g != nil && g.Implicit != nil:
+			d.Flow = "implicit"
+			d.AuthorizationUrl = g.Implicit.LoginEndpoint.Url
+		default:
+			c.lossy(where, "oauth2 authorization has no grant types; dropped")
+			return
+		}
+	default:
+		c.lossy(where, "unknown authorization type %q; dropped", a.Type)
+		return
+	}
+	if c.swag.SecurityDefinitions == nil {
+		c.swag.SecurityDefinitions = make(swagger2.SecurityDefinitions)
+	}
+	c.swag.SecurityDefinitions[name] = d
+}
+
+// security converts the authorizations required by an operation
+func security(auth map[string][]Scope) []swagger2.Security {
+	if len(auth) == 0 {
+		return nil
+	}
+	sec := make(swagger2.Security)
+	for n, scopes := range auth {
+		list := make([]string, 0, len(scopes))
+		for _, s := range scopes {
+			list = append(list, s.Scope)
+		}
+		sec[n] = list
+	}
+	return []swagger2.Security{sec}
+}
+
+// declaration adds the models and operations of an API declaration to the document
+func (c *converter) declaration(first bool, decl *ApiDeclaration) {
+	where := decl.ResourcePath
+	if where == "" {
+		where = decl.BasePath
+	}
+	prefix := c.basePath(first, where, decl.BasePath)
+
+	for n, m := range decl.Models {
+		c.model(n, m)
+	}
+
+	tag := tagName(decl.ResourcePath)
+	for _, api := range decl.Apis {
+		path := prefix + api.Path
+		item := c.swag.Paths[path]
+		for i := range api.Operations {
+			op := &api.Operations[i]
+			method := strings.ToLower(op.Method)
+			opWhere := op.Method + " " + path
+			if item.Operation(method) != nil {
+				c.lossy(opWhere, "operation is declared more than once; keeping the first")
+				continue
+			}
+			if !lists.Contains(swagger2.Methods, method) {
+				c.lossy(opWhere, "method is not supported; dropped")
+				continue
+			}
+			o := c.operation(opWhere, decl, op)
+			if tag != "" {
+				o.Tags = []string{tag}
+			}
+			item.SetOperation(method, o)
+		}
+		c.swag.Paths[path] = item
+	}
+}
+
+// basePath sets the host and base path from the first declaration, and returns the prefix needed to place the
+// paths of later declarations under it
+func (c *converter) basePath(first bool, where, basePath string) string {
+	u, err := url.Parse(basePath)
+	if err != nil {
+		c.lossy(where, "basePath %s is not a valid URL: %s", basePath, err)
+		return ""
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	if first {
+		c.swag.Host = u.Host
+		c.swag.BasePath = path
+		if u.Scheme != "" {
+			c.swag.Schemes = []string{u.Scheme}
+		}
+		return ""
+	}
+	if u.Host != "" && u.Host != c.swag.Host {
+		c.lossy(where, "basePath %s is on a different host than %s; its paths were placed under %s", basePath, c.swag.Host, c.swag.Host)
+	}
+	if u.Scheme != "" && !lists.Contains(c.swag.Schemes, u.Scheme) {
+		c.swag.Schemes = append(c.swag.Schemes, u.Scheme)
+	}
+	if path == c.swag.BasePath {
+		return ""
+	}
+	if strings.HasPrefix(path, c.swag.BasePath+"/") {
+		return strings.TrimPrefix(path, c.swag.BasePath)
+	}
+	c.lossy(where, "basePath %s is not under %s; its paths were placed under %s", basePath, c.swag.BasePath, c.swag.BasePath)
+	return ""
+}
+
+// operation converts a single operation
+func (c *converter) operation(where string, decl *ApiDeclaration, op *Operation) *swagger2.Operation {
+	o := &swagger2.Operation{
+		Summary:     op.Summary,
+		Description: op.Notes,
+		OperationId: op.Nickname,
+		Responses:   make(swagger2.Responses),
+		Deprecated:  op.Deprecated == "true",
+		Consumes:    op.Consumes,
+		Produces:    op.Produces,
+		Security:    security(op.Authorizations),
+	}
+	if op.Authorizations == nil {
+		o.Security = security(decl.Authorizations)
+	}
+	if o.Consumes == nil {
+		o.Consumes = decl.Consumes
+	}
+	if o.Produces == nil {
+		o.Produces = decl.Produces
+	}
+
+	hasFile := false
+	for _, p := range op.Parameters {
+		param := c.parameter(where, &p)
+		if param.Type == "file" {
+			hasFile = true
+		}
+		o.Parameters = append(o.Parameters, param)
+	}
+	if hasFile && !lists.Contains(o.Consumes, "multipart/form-data") {
+		o.Consumes = append(o.Consumes, "multipart/form-data")
+	}
+
+	success := false
+	for _, r := range op.ResponseMessages {
+		resp := swagger2.Response{Description: r.Message}
+		if r.ResponseModel != "" {
+			resp.Schema = c.schema(where, &DataType{Type: r.ResponseModel})
+		}
+		if resp.Description == "" {
+			resp.Description = strconv.Itoa(r.Code)
+		}
+		if r.Code >= 200 && r.Code < 300 {
+			success = true
+			if resp.Schema == nil && op.Type != "void" && op.Type != "" {
+				resp.Schema = c.schema(where, &op.DataType)
+			}
+		}
+		o.Responses[strconv.Itoa(r.Code)] = resp
+	}
+	if !success {
+		resp := swagger2.Response{Description: "Success"}
+		if op.Type != "void" && (op.Type != "" || op.Ref != "") {
+			resp.Schema = c.schema(where, &op.DataType)
+		}
+		o.Responses["200"] = resp
+	}
+	return o
+}
+
+// parameter converts a single parameter
+func (c *converter) parameter(where string, p *Parameter) swagger2.Parameter {
+	param := swagger2.Parameter{Name: p.Name, Description: p.Description}
+	if p.Required || p.ParamType == "path" {
+		param.Required = boolPtr(true)
+	}
+	switch p.ParamType {
+	case "form":
+		param.In = "formData"
+	case "body":
+		if strings.EqualFold(p.Type, "File") {
+			// 1.2 documents commonly describe uploads as a File body
+			param.In = "formData"
+		} else {
+			param.In = "body"
+			param.Schema = c.schema(where, &p.DataType)
+			return param
+		}
+	default:
+		param.In = p.ParamType
+	}
+	param.ItemsDef = *c.items(where+" parameter "+p.Name, &p.DataType)
+	if p.AllowMultiple && param.Type != "array" {
+		items := param.ItemsDef
+		param.ItemsDef = swagger2.ItemsDef{Type: "array", Items: &items, CollectionFormat: "csv"}
+	}
+	return param
+}
+
+// isModel returns true if the type names a model rather than a primitive
+func isModel(t string) bool {
+	if _, ok := primitives[t]; ok {
+		return false
+	}
+	switch t {
+	case "", "array", "set", "void", "File":
+		return false
+	}
+	return true
+}
+
+// items converts a data type into the simple type used by non-body parameters and array items
+func (c *converter) items(where string, t *DataType) *swagger2.ItemsDef {
+	d := &swagger2.ItemsDef{Format: t.Format, Default: t.DefaultValue}
+	switch {
+	case t.Ref != "":
+		d.Ref = swagger2.MakeRef("definitions", t.Ref)
+		return d
+	case t.Type == "array" || t.Type == "set":
+		d.Type = "array"
+		if t.Type == "set" || t.UniqueItems {
+			d.UniqueItems = boolPtr(true)
+		}
+		if t.Items != nil {
+			d.Items = c.items(where, &DataType{Type: t.Items.Type, Ref: t.Items.Ref, Format: t.Items.Format})
+		} else {
+			c.lossy(where, "array has no items; assuming strings")
+			d.Items = &swagger2.ItemsDef{Type: "string"}
+		}
+	case strings.EqualFold(t.Type, "File"):
+		d.Type = "file"
+	case isModel(t.Type):
+		if !c.models[t.Type] {
+			c.lossy(where, "type %s is not a known model", t.Type)
+		}
+		d.Ref = swagger2.MakeRef("definitions", t.Type)
+		return d
+	default:
+		d.Type = primitives[t.Type]
+	}
+	d.Default = c.value(where, d.Type, t.DefaultValue)
+	for _, e := range t.Enum {
+		if v := c.value(where, d.Type, e); v != nil {
+			d.Enum = append(d.Enum, v)
+		}
+	}
+	d.Minimum = c.number(where, t.Minimum)
+	d.Maximum = c.number(where, t.Maximum)
+	return d
+}
+
+// schema converts a data type into a schema
+func (c *converter) schema(where string, t *DataType) *swagger2.Schema {
+	return &swagger2.Schema{ItemsDef: *c.items(where, t)}
+}
+
+// value converts a default or enum value, which Swagger 1.2 writes as a string, to the type it is a value of
+func (c *converter) value(where, typ string, v interface{}) interface{} {
+	text, ok := v.(string)
+	if !ok {
+		return v
+	}
+	var err error
+	switch typ {
+	case "integer":
+		var i int64
+		if i, err = strconv.ParseInt(text, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		var f float64
+		if f, err = strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			return b
+		}
+	default:
+		return v
+	}
+	c.lossy(where, "%q is not a valid %s; dropped", text, typ)
+	return nil
+}
+
+// number parses a minimum or maximum, which Swagger 1.2 writes as a string
+func (c *converter) number(where, s string) *float64 {
+	if s == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		c.lossy(where, "%q is not a number; dropped", s)
+		return nil
+	}
+	return &f
+}
+
+// model converts a model into a definition. Sub types become definitions that extend their parent with allOf.
+func (c *converter) model(name string, m Model) {
+	where := "models." + name
+	if c.swag.Definitions == nil {
+		c.swag.Definitions = make(swagger2.Definitions)
+	}
+	s := swagger2.Schema{
+		ItemsDef:      swagger2.ItemsDef{Type: "object"},
+		Description:   m.Description,
+		Required:      m.Required,
+		Discriminator: m.Discriminator,
+	}
+	if len(m.Properties) > 0 {
+		s.Properties = make(map[string]swagger2.Schema)
+		for n, p := range m.Properties {
+			prop := *c.schema(where+"."+n, &p.DataType)
+			prop.Description = p.Description
+			s.Properties[n] = prop
+		}
+	}
+	if existing, ok := c.swag.Definitions[name]; ok && len(existing.AllOf) > 0 {
+		// a parent was already converted and declared this model as its sub type
+		existing.AllOf = append(existing.AllOf[:1], s)
+		s = existing
+	}
+	c.swag.Definitions[name] = s
+	for _, sub := range m.SubTypes {
+		child := swagger2.Schema{AllOf: []swagger2.Schema{{ItemsDef: swagger2.ItemsDef{Ref: swagger2.MakeRef("definitions", name)}}}}
+		if existing, ok := c.swag.Definitions[sub]; ok {
+			child.AllOf = append(child.AllOf, existing)
+		}
+		c.swag.Definitions[sub] = child
+	}
+}
+
+// boolPtr returns a pointer to the given value
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,112 @@
+package swagger12
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+func TestImport(t *testing.T) {
+	buf, err := ioutil.ReadFile("../examples/swagger12/api-docs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listing, err := LoadListing(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swag, lossy, err := Import(listing, DirLoader("../examples/swagger12"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := swag.Validate(); len(errs) > 0 {
+		t.Errorf("imported document does not validate:\n%s", swagger2.ErrorList(errs).Indent("\t"))
+	}
+	if swag.Host != "petstore.swagger.wordnik.com" || swag.BasePath != "/api" {
+		t.Errorf("unexpected host %q and base path %q", swag.Host, swag.BasePath)
+	}
+
+	get := swag.Paths["/pet/{petId}"].Get
+	if get == nil || get.OperationId != "getPetById" || get.Tags[0] != "pet" {
+		t.Fatalf("operation was not converted: %+v", get)
+	}
+	if r := get.Responses["200"]; r.Schema == nil || r.Schema.Ref != "#/definitions/Pet" {
+		t.Errorf("return type should become the 200 response: %+v", r)
+	}
+	if p := get.Parameters[0]; *p.Maximum != 100000 || p.In != "path" {
+		t.Errorf("string limits were not converted: %+v", p)
+	}
+	if sec := get.Security; len(sec) != 1 || sec[0]["oauth2"][0] != "read:pets" {
+		t.Errorf("declaration authorizations should apply: %+v", sec)
+	}
+
+	find := swag.Paths["/pet/findByStatus"].Get
+	if p := find.Parameters[0]; p.Type != "array" || p.Items.Enum == nil || p.CollectionFormat != "csv" {
+		t.Errorf("allowMultiple was not converted to an array: %+v", p)
+	}
+	upload := swag.Paths["/pet/uploadImage"].Post
+	if p := upload.Parameters[1]; p.In != "formData" || p.Type != "file" {
+		t.Errorf("File body was not converted to a file upload: %+v", p)
+	}
+	if order := swag.Paths["/store/order/{orderId}"].Get; order.Security != nil {
+		t.Errorf("empty authorizations should clear security: %+v", order.Security)
+	}
+
+	if d := swag.Definitions["Pet"].Properties["category"]; d.Ref != "#/definitions/Category" {
+		t.Errorf("model reference was not converted: %+v", d)
+	}
+	rush := swag.Definitions["RushOrder"]
+	if len(rush.AllOf) != 2 || rush.AllOf[0].Ref != "#/definitions/Order" || rush.AllOf[1].Properties["deliverBy"].Format != "date-time" {
+		t.Errorf("sub type was not converted to allOf: %+v", rush)
+	}
+	if sd := swag.SecurityDefinitions["oauth2"]; sd.Flow != "accessCode" || sd.Scopes["write:pets"] == "" {
+		t.Errorf("oauth2 authorization was not converted: %+v", sd)
+	}
+	if sd := swag.SecurityDefinitions["api_key"]; sd.Name != "api_key" || sd.In != "header" {
+		t.Errorf("api key authorization was not converted: %+v", sd)
+	}
+	if text := swagger2.ErrorList(lossy).String(); !strings.Contains(text, "implicit") {
+		t.Errorf("expected the dropped implicit grant to be reported, got:\n%s", text)
+	}
+}
+
+func TestImportValues(t *testing.T) {
+	listing, err := LoadListing([]byte(`{"swaggerVersion": "1.2", "apis": [{"path": "/pets"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decl := `{
+  "swaggerVersion": "1.2",
+  "basePath": "http://example.com/api",
+  "resourcePath": "/pets",
+  "apis": [{
+    "path": "/pets",
+    "operations": [{
+      "method": "GET",
+      "nickname": "listPets",
+      "type": "void",
+      "parameters": [
+        {"name": "limit", "paramType": "query", "type": "integer", "defaultValue": "10", "enum": ["10", "20"]},
+        {"name": "ratio", "paramType": "query", "type": "number", "defaultValue": "0.5"},
+        {"name": "all", "paramType": "query", "type": "boolean", "defaultValue": "true"},
+        {"name": "sort", "paramType": "query", "type": "string", "defaultValue": "name"},
+        {"name": "page", "paramType": "query", "type": "integer", "defaultValue": "first"}
+      ]
+    }]
+  }]
+}`
+	swag, lossy, err := Import(listing, LoaderFunc(func(path string) ([]byte, error) { return []byte(decl), nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := swag.Paths["/pets"].Get.Parameters
+	if params[0].Default != int64(10) || params[0].Enum[1] != int64(20) || params[1].Default != 0.5 || params[2].Default != true ||
+		params[3].Default != "name" || params[4].Default != nil {
+		t.Errorf("defaults were not converted: %+v", params)
+	}
+	if text := swagger2.ErrorList(lossy).String(); !strings.Contains(text, `"first" is not a valid integer`) {
+		t.Errorf("expected the invalid default to be reported, got:\n%s", text)
+	}
+}
@@ -0,0 +1,25 @@
+package swagger12
+
+import (
+	"encoding/json"
+)
+
+// LoadListing parses the incoming byte array as a Swagger 1.2 resource listing
+func LoadListing(in []byte) (*ResourceListing, error) {
+	var l ResourceListing
+	err := json.Unmarshal(in, &l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, err
+}
+
+// LoadDeclaration parses the incoming byte array as a Swagger 1.2 API declaration
+func LoadDeclaration(in []byte) (*ApiDeclaration, error) {
+	var d ApiDeclaration
+	err := json.Unmarshal(in, &d)
+	if err != nil {
+		return nil, err
+	}
+	return &d, err
+}
@@ -0,0 +1,56 @@
+package swagger12
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Loader fetches the API declaration for a resource path from the resource listing.
+type Loader interface {
+	Load(path string) ([]byte, error)
+}
+
+// LoaderFunc adapts an ordinary function to the Loader interface.
+type LoaderFunc func(path string) ([]byte, error)
+
+// Load calls f(path).
+func (f LoaderFunc) Load(path string) ([]byte, error) {
+	return f(path)
+}
+
+// DirLoader reads API declarations from files under dir. A resource path such as "/pet" is read from
+// "pet" or, failing that, "pet.json".
+func DirLoader(dir string) Loader {
+	return LoaderFunc(func(path string) ([]byte, error) {
+		name := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, "/")))
+		buf, err := ioutil.ReadFile(name)
+		if os.IsNotExist(err) && filepath.Ext(name) == "" {
+			buf, err = ioutil.ReadFile(name + ".json")
+		}
+		return buf, err
+	})
+}
+
+// HttpLoader fetches API declarations relative to the URL the resource listing was served from, such as
+// "http://petstore.swagger.wordnik.com/api/api-docs".
+func HttpLoader(listingUrl string, client *http.Client) Loader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := strings.TrimSuffix(listingUrl, "/")
+	return LoaderFunc(func(path string) ([]byte, error) {
+		resp, err := client.Get(base + path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: %s", base+path, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	})
+}
@@ -0,0 +1,156 @@
+// Package swagger12 reads Swagger 1.2 resource listings and API declarations and assembles them into Swagger 2 documents.
+package swagger12
+
+// ResourceListing is the root document of a Swagger 1.2 specification. It lists the resources of the API, each of which is described by its own API Declaration.
+type ResourceListing struct {
+	SwaggerVersion string                   `json:"swaggerVersion"`           // Required. Specifies the Swagger Specification version being used. The value MUST be "1.2".
+	Apis           []Resource               `json:"apis"`                     // Required. Lists the resources to be described by this specification implementation.
+	ApiVersion     string                   `json:"apiVersion,omitempty"`     // Provides the version of the application API (not to be confused by the specification version).
+	Info           *Info                    `json:"info,omitempty"`           // Provides metadata about the API.
+	Authorizations map[string]Authorization `json:"authorizations,omitempty"` // Provides information about the authorization schemes allowed on the API.
+}
+
+// Resource describes a single resource in the listing. The path locates the resource's API Declaration.
+type Resource struct {
+	Path        string `json:"path"`                  // Required. A relative path to the API declaration from the path used to retrieve this Resource Listing.
+	Description string `json:"description,omitempty"` // A short description of the resource.
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title             string `json:"title"`                       // Required. The title of the application.
+	Description       string `json:"description"`                 // Required. A short description of the application.
+	TermsOfServiceUrl string `json:"termsOfServiceUrl,omitempty"` // A URL to the Terms of Service of the API.
+	Contact           string `json:"contact,omitempty"`           // An email to be used for API-related correspondence.
+	License           string `json:"license,omitempty"`           // The license name used for the API.
+	LicenseUrl        string `json:"licenseUrl,omitempty"`        // A URL to the license used for the API.
+}
+
+// Authorization describes an authorization scheme that can be used by operations.
+type Authorization struct {
+	Type       string      `json:"type"`                 // Required. The type of the authorization scheme. Values MUST be either "basicAuth", "apiKey" or "oauth2".
+	PassAs     string      `json:"passAs,omitempty"`     // Required for apiKey. Denotes how the API key must be passed. Valid values are "header" or "query".
+	Keyname    string      `json:"keyname,omitempty"`    // Required for apiKey. The name of the header or query parameter to be used when passing the API key.
+	Scopes     []Scope     `json:"scopes,omitempty"`     // A list of supported OAuth2 scopes.
+	GrantTypes *GrantTypes `json:"grantTypes,omitempty"` // Required for oauth2. Detailed information about the grant types supported by the OAuth2 authorization scheme.
+}
+
+// Scope describes an OAuth2 authorization scope.
+type Scope struct {
+	Scope       string `json:"scope"`                 // Required. The name of the scope.
+	Description string `json:"description,omitempty"` // A short description of the scope.
+}
+
+// GrantTypes provides details regarding the OAuth2 grant types that are supported by the API.
+type GrantTypes struct {
+	Implicit          *ImplicitGrant          `json:"implicit,omitempty"`           // The Implicit Grant flow definition.
+	AuthorizationCode *AuthorizationCodeGrant `json:"authorization_code,omitempty"` // The Authorization Code Grant flow definition.
+}
+
+// ImplicitGrant provides details regarding the OAuth2 implicit grant type.
+type ImplicitGrant struct {
+	LoginEndpoint Endpoint `json:"loginEndpoint"`       // Required. The login endpoint definition.
+	TokenName     string   `json:"tokenName,omitempty"` // An optional alternative name to standard "access_token" OAuth2 parameter.
+}
+
+// AuthorizationCodeGrant provides details regarding the OAuth2 authorization code grant type.
+type AuthorizationCodeGrant struct {
+	TokenRequestEndpoint Endpoint `json:"tokenRequestEndpoint"` // Required. The token request endpoint definition.
+	TokenEndpoint        Endpoint `json:"tokenEndpoint"`        // Required. The token endpoint definition.
+}
+
+// Endpoint describes an OAuth2 endpoint.
+type Endpoint struct {
+	Url              string `json:"url"`                        // Required. The URL of the endpoint.
+	TokenName        string `json:"tokenName,omitempty"`        // An optional alternative name to the standard token parameter.
+	ClientIdName     string `json:"clientIdName,omitempty"`     // An optional alternative name to the standard "client_id" OAuth2 parameter.
+	ClientSecretName string `json:"clientSecretName,omitempty"` // An optional alternative name to the standard "client_secret" OAuth2 parameter.
+}
+
+// ApiDeclaration describes the operations available on a single resource.
+type ApiDeclaration struct {
+	SwaggerVersion string             `json:"swaggerVersion"`           // Required. Specifies the Swagger Specification version being used. The value MUST be "1.2".
+	ApiVersion     string             `json:"apiVersion,omitempty"`     // Provides the version of the application API.
+	BasePath       string             `json:"basePath"`                 // Required. The root URL serving the API.
+	ResourcePath   string             `json:"resourcePath,omitempty"`   // The relative path to the resource, from the basePath, which this API Specification describes.
+	Apis           []Api              `json:"apis"`                     // Required. A list of the APIs exposed on this resource.
+	Models         map[string]Model   `json:"models,omitempty"`         // A list of the models available to this resource.
+	Produces       []string           `json:"produces,omitempty"`       // A list of MIME types the APIs on this resource can produce.
+	Consumes       []string           `json:"consumes,omitempty"`       // A list of MIME types the APIs on this resource can consume.
+	Authorizations map[string][]Scope `json:"authorizations,omitempty"` // A list of authorizations schemes required for the operations listed in this API declaration.
+}
+
+// Api describes the operations on a single path.
+type Api struct {
+	Path        string      `json:"path"`                  // Required. The relative path to the operation, from the basePath.
+	Description string      `json:"description,omitempty"` // A short description of the resource.
+	Operations  []Operation `json:"operations"`            // Required. A list of the API operations available on this path.
+}
+
+// DataType holds the fields used to describe a type, shared by operations, parameters and model properties.
+type DataType struct {
+	Type         string      `json:"type,omitempty"`         // The return type of the operation, or the type of the parameter or property. Either a primitive, a model id, "array", "void" or "File".
+	Ref          string      `json:"$ref,omitempty"`         // The model id, used instead of type for model references.
+	Format       string      `json:"format,omitempty"`       // Fine-tuned primitive type definition.
+	DefaultValue interface{} `json:"defaultValue,omitempty"` // The default value to be used for the field.
+	Enum         []string    `json:"enum,omitempty"`         // A fixed list of possible values.
+	Minimum      string      `json:"minimum,omitempty"`      // The minimum valid value for the type, inclusive.
+	Maximum      string      `json:"maximum,omitempty"`      // The maximum valid value for the type, inclusive.
+	Items        *Items      `json:"items,omitempty"`        // Required if type is "array". The type definition of the values in the container.
+	UniqueItems  bool        `json:"uniqueItems,omitempty"`  // A flag to note whether the container allows duplicate values or not.
+}
+
+// Items describes the values of a container.
+type Items struct {
+	Type   string `json:"type,omitempty"`   // The primitive type of the values.
+	Ref    string `json:"$ref,omitempty"`   // The model id of the values.
+	Format string `json:"format,omitempty"` // Fine-tuned primitive type definition.
+}
+
+// Operation describes a single operation on a path.
+type Operation struct {
+	DataType
+	Method           string             `json:"method"`                     // Required. The HTTP method required to invoke this operation.
+	Summary          string             `json:"summary,omitempty"`          // A short summary of what the operation does.
+	Notes            string             `json:"notes,omitempty"`            // A verbose explanation of the operation behavior.
+	Nickname         string             `json:"nickname"`                   // Required. A unique id for the operation that can be used by tools reading the output for further and easier manipulation.
+	Authorizations   map[string][]Scope `json:"authorizations,omitempty"`   // A list of authorizations required to execute this operation.
+	Parameters       []Parameter        `json:"parameters"`                 // Required. The inputs to the operation.
+	ResponseMessages []ResponseMessage  `json:"responseMessages,omitempty"` // Lists the possible response statuses that can return from the operation.
+	Produces         []string           `json:"produces,omitempty"`         // A list of MIME types this operation can produce.
+	Consumes         []string           `json:"consumes,omitempty"`         // A list of MIME types this operation can consume.
+	Deprecated       string             `json:"deprecated,omitempty"`       // Declares this operation to be deprecated. Value SHOULD be "true" or "false".
+}
+
+// Parameter describes a single parameter to be sent in an operation.
+type Parameter struct {
+	DataType
+	ParamType     string `json:"paramType"`               // Required. The type of the parameter. Values MUST be one of "path", "query", "body", "header" or "form".
+	Name          string `json:"name"`                    // Required. The unique name for the parameter.
+	Description   string `json:"description,omitempty"`   // Recommended. A brief description of this parameter.
+	Required      bool   `json:"required,omitempty"`      // A flag to note whether this parameter is required.
+	AllowMultiple bool   `json:"allowMultiple,omitempty"` // Another way to allow multiple values for a "query", "header" or "path" parameter.
+}
+
+// ResponseMessage describes a possible response status of an operation.
+type ResponseMessage struct {
+	Code          int    `json:"code"`                    // Required. The HTTP status code returned.
+	Message       string `json:"message"`                 // Required. The explanation for the status code.
+	ResponseModel string `json:"responseModel,omitempty"` // The return type for the given response.
+}
+
+// Model describes a data type used by the operations of a resource.
+type Model struct {
+	Id            string              `json:"id"`                      // Required. A unique identifier for the model.
+	Description   string              `json:"description,omitempty"`   // A brief description of this model.
+	Required      []string            `json:"required,omitempty"`      // A definition of which properties MUST exist when a model instance is produced.
+	Properties    map[string]Property `json:"properties"`              // Required. A list of properties (fields) that are part of the model.
+	SubTypes      []string            `json:"subTypes,omitempty"`      // List of the model ids that inherit from this model.
+	Discriminator string              `json:"discriminator,omitempty"` // MUST be included only if subTypes is included. This field allows for polymorphism within the described inherited models.
+}
+
+// Property describes a single field of a model.
+type Property struct {
+	DataType
+	Description string `json:"description,omitempty"` // A brief description of this property.
+}
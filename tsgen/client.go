@@ -0,0 +1,507 @@
+package tsgen
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Client generates a TypeScript module with a Client class that has a method for each operation of the document.
+// The methods send their request with fetch, take the parameters as one object and resolve to the body of a
+// successful response; other responses are thrown as an ApiError. The types come from the module Types generates.
+func Client(swag *swagger2.Swagger, opts Options) ([]byte, error) {
+	g := newGenerator(swag, opts)
+	c := &clientGen{generator: g, creds: make(map[string][]string)}
+	c.client()
+	return c.source(), nil
+}
+
+// clientGen adds the state of client generation to a generator
+type clientGen struct {
+	*generator
+	creds map[string][]string // security definition to the options holding its credentials
+}
+
+// operation is an operation with its parameters resolved
+type operation struct {
+	name     string // method name
+	method   string
+	path     string
+	op       *swagger2.Operation
+	params   []*param // in path, query, header, formData and body order
+	consumes []string
+	security []swagger2.Security
+}
+
+// param is a parameter with the key it has in the parameters object of a method
+type param struct {
+	*swagger2.Parameter
+	key      string
+	typ      string
+	required bool
+}
+
+// pathParam matches a templated path segment
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// members are the names the Client class uses itself
+var members = []string{"constructor", "options", "send", "receive", "authorize", "credentials"}
+
+// client writes the module: the imports of the types it uses, the options, the error and the class
+func (c *clientGen) client() {
+	title := c.swag.Info.Title
+	if title == "" {
+		title = "the API"
+	}
+	methods := make(namer)
+	for _, m := range members {
+		methods[m] = true
+	}
+	fmt.Fprintf(&c.decls, "/** DEFAULT_BASE_URL is where the API is served according to its document. */\n")
+	fmt.Fprintf(&c.decls, "export const DEFAULT_BASE_URL = %s;\n\n", literal(c.swag.BaseURL()))
+	c.decls.WriteString("/** ClientOptions configures a Client. */\n")
+	c.decls.WriteString("export interface ClientOptions {\n")
+	c.decls.WriteString("  /** Where the API is served; defaults to DEFAULT_BASE_URL. */\n  baseUrl?: string;\n")
+	c.decls.WriteString("  /** Sends the requests; defaults to the global fetch. */\n  fetch?: typeof fetch;\n")
+	c.decls.WriteString("  /** Headers sent with every request. */\n  headers?: Record<string, string>;\n")
+	c.credentialOptions()
+	c.decls.WriteString("}\n\n")
+	c.decls.WriteString(apiError)
+	fmt.Fprintf(&c.decls, "/** Client calls the operations of %s. */\n", strings.ReplaceAll(title, "*/", "*\\/"))
+	c.decls.WriteString("export class Client {\n  private readonly options: ClientOptions;\n\n")
+	c.decls.WriteString("  constructor(options: ClientOptions = {}) {\n    this.options = options;\n  }\n")
+	for _, o := range c.operations(methods) {
+		c.decls.WriteString(c.operation(o))
+	}
+	c.authorize()
+	authorize := ""
+	if len(c.creds) > 0 {
+		authorize = "this.authorize(security, query, headers);\n    "
+	}
+	c.decls.WriteString(strings.Replace(clientRuntime, "AUTHORIZE\n    ", authorize, 1))
+	c.decls.WriteString("}\n")
+}
+
+// source returns the module with its header and the imports of the types it uses
+func (c *clientGen) source() []byte {
+	imports := make([]string, 0, len(c.used))
+	for name := range c.used {
+		imports = append(imports, c.names[name])
+	}
+	sort.Strings(imports)
+	var b strings.Builder
+	b.WriteString("// Code generated by swaggen. DO NOT EDIT.\n\n")
+	if len(imports) > 0 {
+		fmt.Fprintf(&b, "import type { %s } from %s;\n\n", strings.Join(imports, ", "), literal(c.opts.TypesModule))
+	}
+	b.Write(c.decls.Bytes())
+	return []byte(b.String())
+}
+
+// securityNames returns the names of the security definitions in sorted order
+func (c *clientGen) securityNames() []string {
+	names := make([]string, 0, len(c.swag.SecurityDefinitions))
+	for n := range c.swag.SecurityDefinitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// credentialOptions writes an option for each credential the security definitions need
+func (c *clientGen) credentialOptions() {
+	opts := make(namer)
+	for _, m := range []string{"baseUrl", "fetch", "headers"} {
+		opts[m] = true
+	}
+	for _, name := range c.securityNames() {
+		def := c.swag.SecurityDefinitions[name]
+		base := memberName(name)
+		switch def.Type {
+		case "apiKey":
+			o := opts.unique(base)
+			where := "header"
+			if def.In == "query" {
+				where = "query parameter"
+			}
+			fmt.Fprintf(&c.decls, "  /** The API key for %s, sent in the %s %s. */\n  %s?: string;\n", name, def.Name, where, o)
+			c.creds[name] = []string{o}
+		case "basic":
+			user, pass := opts.unique(base+"Username"), opts.unique(base+"Password")
+			fmt.Fprintf(&c.decls, "  /** The user name for %s. */\n  %s?: string;\n", name, user)
+			fmt.Fprintf(&c.decls, "  /** The password for %s. */\n  %s?: string;\n", name, pass)
+			c.creds[name] = []string{user, pass}
+		case "oauth2":
+			o := opts.unique(base + "Token")
+			fmt.Fprintf(&c.decls, "  /** The OAuth2 access token for %s. */\n  %s?: string;\n", name, o)
+			c.creds[name] = []string{o}
+		}
+	}
+}
+
+// authorize writes the methods that add the credentials of the first security requirement the options can meet
+func (c *clientGen) authorize() {
+	if len(c.creds) == 0 {
+		return
+	}
+	c.decls.WriteString(`
+  private authorize(security: string[][], query: URLSearchParams, headers: Record<string, string>): void {
+    for (const requirement of security) {
+      if (requirement.every((scheme) => this.credentials(scheme, query, headers, false))) {
+        requirement.forEach((scheme) => this.credentials(scheme, query, headers, true));
+        return;
+      }
+    }
+  }
+
+  private credentials(scheme: string, query: URLSearchParams, headers: Record<string, string>, apply: boolean): boolean {
+    switch (scheme) {
+`)
+	for _, name := range c.securityNames() {
+		def := c.swag.SecurityDefinitions[name]
+		opts := c.creds[name]
+		if opts == nil {
+			continue
+		}
+		value := access("this.options", opts[0])
+		fmt.Fprintf(&c.decls, "      case %s:\n", literal(name))
+		fmt.Fprintf(&c.decls, "        if (%s === undefined) return false;\n", value)
+		switch {
+		case def.Type == "apiKey" && def.In == "query":
+			fmt.Fprintf(&c.decls, "        if (apply) query.set(%s, %s);\n", literal(def.Name), value)
+		case def.Type == "apiKey":
+			fmt.Fprintf(&c.decls, "        if (apply) headers[%s] = %s;\n", literal(def.Name), value)
+		case def.Type == "basic":
+			fmt.Fprintf(&c.decls, "        if (apply) headers[\"Authorization\"] = \"Basic \" + btoa(%s + \":\" + (%s ?? \"\"));\n", value, access("this.options", opts[1]))
+		default:
+			fmt.Fprintf(&c.decls, "        if (apply) headers[\"Authorization\"] = \"Bearer \" + %s;\n", value)
+		}
+		c.decls.WriteString("        return true;\n")
+	}
+	c.decls.WriteString("    }\n    return false;\n  }\n")
+}
+
+// operations collects the operations of the document sorted by path and method, naming their methods
+func (c *clientGen) operations(methods namer) []*operation {
+	paths := make([]string, 0, len(c.swag.Paths))
+	for p := range c.swag.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	ops := make([]*operation, 0)
+	for _, path := range paths {
+		item := c.swag.Paths[path]
+		for _, method := range swagger2.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			o := &operation{method: method, path: path, op: op, consumes: c.swag.Consumes, security: c.swag.Security}
+			if op.OperationId != "" {
+				o.name = methods.unique(memberName(op.OperationId))
+			} else {
+				o.name = methods.unique(memberName(method + " " + pathParam.ReplaceAllString(path, "by $1")))
+			}
+			if op.Consumes != nil {
+				o.consumes = op.Consumes
+			}
+			if op.Security != nil {
+				o.security = op.Security
+			}
+			o.params = c.params(o, &item)
+			ops = append(ops, o)
+		}
+	}
+	return ops
+}
+
+// params resolves the parameters of an operation and those of its path
+func (c *clientGen) params(o *operation, item *swagger2.PathItem) []*param {
+	params := c.swag.OperationParameters(o.path, item, o.op)
+	all := make([]*swagger2.Parameter, len(params))
+	for i := range params {
+		all[i] = &params[i]
+	}
+	hasBody := false
+	for _, p := range all {
+		hasBody = hasBody || p.In == "body"
+	}
+	keys := make(namer)
+	list := make([]*param, 0, len(all))
+	for _, p := range all {
+		if hasBody && p.In == "formData" {
+			continue // form parameters cannot be sent with a body
+		}
+		pr := &param{Parameter: p, key: keys.unique(p.Name), required: p.In == "path" || (p.Required != nil && *p.Required)}
+		if p.In == "body" && p.Schema != nil {
+			pr.typ = c.schemaType(p.Schema, "  ")
+		} else {
+			pr.typ = c.itemsType(&p.ItemsDef, "  ")
+		}
+		list = append(list, pr)
+	}
+	return list
+}
+
+// operation writes the method for an operation
+func (c *clientGen) operation(o *operation) string {
+	var b strings.Builder
+	text := strings.TrimSpace(o.op.Summary)
+	if desc := strings.TrimSpace(o.op.Description); desc != "" && desc != text {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += desc
+	}
+	if text == "" {
+		text = "Calls " + strings.ToUpper(o.method) + " " + o.path + "."
+	}
+	b.WriteString("\n")
+	b.WriteString(doc("  ", text, o.op.Deprecated))
+
+	fields := make([]string, 0, len(o.params))
+	optional := true
+	for _, p := range o.params {
+		mark := "?"
+		if p.required {
+			mark = ""
+			optional = false
+		}
+		fields = append(fields, propertyName(p.key)+mark+": "+p.typ)
+	}
+	args := "init?: RequestInit"
+	switch {
+	case len(fields) > 0 && optional:
+		args = "params: { " + strings.Join(fields, "; ") + " } = {}, " + args
+	case len(fields) > 0:
+		args = "params: { " + strings.Join(fields, "; ") + " }, " + args
+	}
+	returns, statuses, binary := c.results(o)
+	fmt.Fprintf(&b, "  async %s(%s): Promise<%s> {\n", o.name, args, returns)
+	b.WriteString("    const query = new URLSearchParams();\n")
+	b.WriteString("    const headers: Record<string, string> = {};\n")
+
+	path := o.path
+	form, multipart := false, lists.Contains(o.consumes, "multipart/form-data")
+	var body *param
+	for _, p := range o.params {
+		value := access("params", p.key)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent("+valueText(p, value)+")}")
+		case "query":
+			b.WriteString(set(p, value, "query.append(%s, %s);"))
+		case "header":
+			b.WriteString(set(p, value, "headers[%s] = %s;"))
+		case "formData":
+			form = true
+			multipart = multipart || p.Type == "file"
+		case "body":
+			body = p
+		}
+	}
+	switch {
+	case body != nil:
+		encode := "String(" + access("params", body.key) + ")"
+		media := mediaType(o.consumes, "application/json")
+		if strings.Contains(media, "json") {
+			encode = "JSON.stringify(" + access("params", body.key) + ")"
+		}
+		if !body.required {
+			encode = access("params", body.key) + " === undefined ? undefined : " + encode
+		}
+		fmt.Fprintf(&b, "    headers[\"Content-Type\"] = %s;\n", literal(media))
+		fmt.Fprintf(&b, "    const body = %s;\n", encode)
+	case form && multipart:
+		b.WriteString("    const body = new FormData();\n")
+	case form:
+		b.WriteString("    const body = new URLSearchParams();\n")
+	}
+	if form {
+		for _, p := range o.params {
+			if p.In != "formData" {
+				continue
+			}
+			value := access("params", p.key)
+			b.WriteString(set(p, value, "body.append(%s, %s);"))
+		}
+	}
+	if path == o.path {
+		path = literal(path)
+	} else {
+		path = "`" + path + "`"
+	}
+	sendBody := "undefined"
+	if body != nil || form {
+		sendBody = "body"
+	}
+	fmt.Fprintf(&b, "    const res = await this.send(%s, %s, query, headers, %s, %s, init);\n",
+		literal(strings.ToUpper(o.method)), path, sendBody, c.securityLiteral(o.security))
+	receive := fmt.Sprintf("await this.receive(res, [%s], %t)", strings.Join(statuses, ", "), binary)
+	if returns == "void" {
+		fmt.Fprintf(&b, "    %s;\n", receive)
+	} else {
+		fmt.Fprintf(&b, "    return (%s) as %s;\n", receive, returns)
+	}
+	b.WriteString("  }\n")
+	return b.String()
+}
+
+// results returns the type a method resolves to, the successful statuses it expects, which are any 2xx status if
+// the list is empty, and whether the response is a file
+func (c *clientGen) results(o *operation) (string, []string, bool) {
+	codes := make([]string, 0, len(o.op.Responses))
+	for code := range o.op.Responses {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	candidates := codes
+	if len(codes) == 0 {
+		if _, ok := o.op.Responses["default"]; ok {
+			candidates = []string{"default"}
+		}
+	}
+	types := make([]string, 0)
+	binary := false
+	for _, code := range candidates {
+		r := o.op.Responses[code]
+		t := "void"
+		if r.Schema != nil {
+			t = c.schemaType(r.Schema, "  ")
+			binary = binary || (r.Schema.Type == "file" && r.Schema.Ref == "")
+		}
+		if !lists.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return "void", codes, false
+	}
+	return strings.Join(types, " | "), codes, binary
+}
+
+// securityLiteral writes the security requirements of an operation as arrays of the schemes each one needs
+func (c *clientGen) securityLiteral(security []swagger2.Security) string {
+	if len(c.creds) == 0 {
+		return "[]"
+	}
+	list := make([]string, 0, len(security))
+	for _, req := range security {
+		names := make([]string, 0, len(req))
+		for n := range req {
+			names = append(names, literal(n))
+		}
+		sort.Strings(names)
+		list = append(list, "["+strings.Join(names, ", ")+"]")
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+// set writes the statement that adds a parameter's value using format, which takes the name and the text of the
+// value. Optional parameters are only added when they are set, and multi arrays add each of their values.
+func set(p *param, value, format string) string {
+	var stmt string
+	if p.Type == "array" && separator(p) == "" {
+		stmt = fmt.Sprintf("for (const item of %s) "+format, value, literal(p.Name), "String(item)")
+	} else {
+		stmt = fmt.Sprintf(format, literal(p.Name), valueText(p, value))
+	}
+	if !p.required {
+		stmt = fmt.Sprintf("if (%s !== undefined) %s", value, stmt)
+	}
+	return "    " + stmt + "\n"
+}
+
+// valueText returns the expression that writes a parameter's value as text
+func valueText(p *param, value string) string {
+	switch {
+	case p.Type == "file":
+		return value
+	case p.Type == "array" && separator(p) != "":
+		return value + ".join(" + literal(separator(p)) + ")"
+	}
+	return "String(" + value + ")"
+}
+
+// separator returns the text that separates the values of an array parameter, or "" if each value is sent as a
+// separate parameter
+func separator(p *param) string {
+	if p.CollectionFormat == "multi" && (p.In == "query" || p.In == "formData") {
+		return ""
+	}
+	if sep, ok := map[string]string{"ssv": " ", "tsv": "\t", "pipes": "|"}[p.CollectionFormat]; ok {
+		return sep
+	}
+	return ","
+}
+
+// mediaType picks the media type to use from a list, preferring JSON
+func mediaType(types []string, fallback string) string {
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			return t
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return fallback
+}
+
+// apiError is the error class thrown for unsuccessful responses
+const apiError = `/** ApiError is thrown when the server responds with a status code the operation does not declare as a success. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly headers: Headers;
+  readonly body: unknown;
+
+  constructor(status: number, headers: Headers, body: unknown) {
+    super("unexpected status " + status);
+    this.name = "ApiError";
+    this.status = status;
+    this.headers = headers;
+    this.body = body;
+  }
+}
+
+`
+
+// clientRuntime holds the methods every client shares
+const clientRuntime = `
+  private async send(
+    method: string,
+    path: string,
+    query: URLSearchParams,
+    headers: Record<string, string>,
+    body: BodyInit | undefined,
+    security: string[][],
+    init?: RequestInit,
+  ): Promise<Response> {
+    AUTHORIZE
+    const search = query.toString();
+    const url = (this.options.baseUrl ?? DEFAULT_BASE_URL).replace(/\/$/, "") + path + (search ? "?" + search : "");
+    const send = this.options.fetch ?? fetch;
+    return send(url, { ...init, method, headers: { ...this.options.headers, ...headers }, body });
+  }
+
+  private async receive(res: Response, statuses: number[], binary: boolean): Promise<unknown> {
+    let body: unknown;
+    if (binary && res.ok) {
+      body = await res.blob();
+    } else {
+      const text = await res.text();
+      body = text !== "" && (res.headers.get("Content-Type") ?? "").includes("json") ? JSON.parse(text) : text;
+    }
+    if (statuses.length > 0 ? !statuses.includes(res.status) : !res.ok) {
+      throw new ApiError(res.status, res.headers, body);
+    }
+    return body;
+  }
+`
@@ -0,0 +1,100 @@
+package tsgen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// identifier matches the names that can be written without quotes as TypeScript property names
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// words splits a name into words on punctuation
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// typeName turns an arbitrary name into a TypeScript type name, such as "pet_status" into "PetStatus". Names that
+// are identifiers already keep their own casing apart from the first letter.
+func typeName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	id := b.String()
+	if id == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// memberName turns an arbitrary name into a camel case member name, such as "FindPetById" into "findPetById"
+func memberName(name string) string {
+	id := typeName(name)
+	r := []rune(id)
+	return string(unicode.ToLower(r[0])) + string(r[1:])
+}
+
+// propertyName returns a property name as it can be written in a type literal or property access
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// access returns the expression that reads a property of an object
+func access(object, name string) string {
+	if identifier.MatchString(name) {
+		return object + "." + name
+	}
+	return object + "[" + strconv.Quote(name) + "]"
+}
+
+// namer hands out identifiers that are unique within a generated file
+type namer map[string]bool
+
+// unique returns the identifier, adding a number to it if it is already taken
+func (n namer) unique(id string) string {
+	candidate := id
+	for i := 2; n[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", id, i)
+	}
+	n[candidate] = true
+	return candidate
+}
+
+// doc formats text as a JSDoc comment, returning "" if there is no text
+func doc(indent, text string, deprecated bool) string {
+	text = strings.TrimSpace(text)
+	if text == "" && !deprecated {
+		return ""
+	}
+	text = strings.ReplaceAll(text, "*/", "*\\/")
+	lines := make([]string, 0)
+	if text != "" {
+		lines = strings.Split(text, "\n")
+	}
+	if deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	if len(lines) == 1 {
+		return indent + "/** " + strings.TrimSpace(lines[0]) + " */\n"
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(indent+" * "+strings.TrimRight(line, " \t\r"), " "))
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
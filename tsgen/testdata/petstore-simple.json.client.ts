@@ -0,0 +1,105 @@
+// Code generated by swaggen. DO NOT EDIT.
+
+import type { Pet, PetInput } from "./types";
+
+/** DEFAULT_BASE_URL is where the API is served according to its document. */
+export const DEFAULT_BASE_URL = "http://petstore.swagger.wordnik.com/api";
+
+/** ClientOptions configures a Client. */
+export interface ClientOptions {
+  /** Where the API is served; defaults to DEFAULT_BASE_URL. */
+  baseUrl?: string;
+  /** Sends the requests; defaults to the global fetch. */
+  fetch?: typeof fetch;
+  /** Headers sent with every request. */
+  headers?: Record<string, string>;
+}
+
+/** ApiError is thrown when the server responds with a status code the operation does not declare as a success. */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly headers: Headers;
+  readonly body: unknown;
+
+  constructor(status: number, headers: Headers, body: unknown) {
+    super("unexpected status " + status);
+    this.name = "ApiError";
+    this.status = status;
+    this.headers = headers;
+    this.body = body;
+  }
+}
+
+/** Client calls the operations of Swagger Petstore. */
+export class Client {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions = {}) {
+    this.options = options;
+  }
+
+  /** Returns all pets from the system that the user has access to */
+  async findPets(params: { tags?: string[]; limit?: number } = {}, init?: RequestInit): Promise<Pet[]> {
+    const query = new URLSearchParams();
+    const headers: Record<string, string> = {};
+    if (params.tags !== undefined) query.append("tags", params.tags.join(","));
+    if (params.limit !== undefined) query.append("limit", String(params.limit));
+    const res = await this.send("GET", "/pets", query, headers, undefined, [], init);
+    return (await this.receive(res, [200], false)) as Pet[];
+  }
+
+  /** Creates a new pet in the store.  Duplicates are allowed */
+  async addPet(params: { pet: PetInput }, init?: RequestInit): Promise<Pet> {
+    const query = new URLSearchParams();
+    const headers: Record<string, string> = {};
+    headers["Content-Type"] = "application/json";
+    const body = JSON.stringify(params.pet);
+    const res = await this.send("POST", "/pets", query, headers, body, [], init);
+    return (await this.receive(res, [200], false)) as Pet;
+  }
+
+  /** Returns a user based on a single ID, if the user does not have access to the pet */
+  async findPetById(params: { id: number }, init?: RequestInit): Promise<Pet> {
+    const query = new URLSearchParams();
+    const headers: Record<string, string> = {};
+    const res = await this.send("GET", `/pets/${encodeURIComponent(String(params.id))}`, query, headers, undefined, [], init);
+    return (await this.receive(res, [200], false)) as Pet;
+  }
+
+  /** deletes a single pet based on the ID supplied */
+  async deletePet(params: { id: number }, init?: RequestInit): Promise<void> {
+    const query = new URLSearchParams();
+    const headers: Record<string, string> = {};
+    const res = await this.send("DELETE", `/pets/${encodeURIComponent(String(params.id))}`, query, headers, undefined, [], init);
+    await this.receive(res, [204], false);
+  }
+
+  private async send(
+    method: string,
+    path: string,
+    query: URLSearchParams,
+    headers: Record<string, string>,
+    body: BodyInit | undefined,
+    security: string[][],
+    init?: RequestInit,
+  ): Promise<Response> {
+    const search = query.toString();
+    const url = (this.options.baseUrl ?? DEFAULT_BASE_URL).replace(/\/$/, "") + path + (search ? "?" + search : "");
+    const send = this.options.fetch ?? fetch;
+    return send(url, { ...init, method, headers: { ...this.options.headers, ...headers }, body });
+  }
+
+  private async receive(res: Response, statuses: number[], binary: boolean): Promise<unknown> {
+    let body: unknown;
+    if (binary && res.ok) {
+      body = await res.blob();
+    } else {
+      const text = await res.text();
+      body = text !== "" && (res.headers.get("Content-Type") ?? "").includes("json") ? JSON.parse(text) : text;
+    }
+    if (statuses.length > 0 ? !statuses.includes(res.status) : !res.ok) {
+      throw new ApiError(res.status, res.headers, body);
+    }
+    return body;
+  }
+}
@@ -0,0 +1,182 @@
+package tsgen
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/babelrpc/swagger2"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// petstores loads the petstore examples shipped with the parent package
+func petstores(t *testing.T) map[string]*swagger2.Swagger {
+	docs := make(map[string]*swagger2.Swagger)
+	for _, ext := range []string{"json", "yaml"} {
+		files, err := filepath.Glob("../examples/" + ext + "/petstore*." + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var swag *swagger2.Swagger
+			if ext == "json" {
+				swag, err = swagger2.LoadJson(buf)
+			} else {
+				swag, err = swagger2.LoadYaml(buf)
+			}
+			if err != nil {
+				t.Fatalf("Unable to parse file \"%s\": %s", file, err)
+			}
+			docs[file] = swag
+		}
+	}
+	return docs
+}
+
+// golden compares generated source with the golden file, or rewrites the file when -update is given
+func golden(t *testing.T, name string, src []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("%s does not match the generated source (run go test -update if the change is expected):\n%s", path, src)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	for file, swag := range petstores(t) {
+		base := filepath.Base(file)
+		types, err := Types(swag, Options{})
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		golden(t, base+".types.ts", types)
+		client, err := Client(swag, Options{})
+		if err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		golden(t, base+".client.ts", client)
+	}
+}
+
+const zoo = `
+swagger: "2.0"
+info: {title: Zoo, version: "1.0"}
+host: zoo.example.com
+schemes: [http, https]
+securityDefinitions:
+  key: {type: apiKey, name: key, in: query}
+  basic: {type: basic}
+paths:
+  /animals/{id}/photos:
+    parameters:
+      - {name: id, in: path, required: true, type: integer}
+    get:
+      operationId: listPhotos
+      security: [{key: []}, {basic: []}]
+      parameters:
+        - {name: size, in: query, type: array, items: {type: string, enum: [s, m]}, collectionFormat: multi}
+        - {name: X-Trace, in: header, required: true, type: string}
+      responses:
+        200:
+          description: The photos.
+          schema: {type: array, items: {type: string, format: byte}}
+    post:
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: photo, in: formData, required: true, type: file}
+      responses:
+        default:
+          description: The animal.
+          schema: {$ref: "#/definitions/Animal"}
+definitions:
+  Animal:
+    type: object
+    discriminator: kind
+    required: [kind, name]
+    properties:
+      kind: {type: string}
+      name: {type: string, description: The name it answers to.}
+      tags: {type: object, additionalProperties: {type: string}}
+      x-id: {type: integer, readOnly: true}
+  Cat:
+    allOf:
+      - $ref: "#/definitions/Animal"
+      - properties:
+          lives: {type: integer, enum: [7, 9]}
+  Dog:
+    allOf:
+      - $ref: "#/definitions/Animal"
+      - properties:
+          owner: {type: string, x-nullable: true}
+  Keeper:
+    type: object
+    properties:
+      animal: {$ref: "#/definitions/Animal"}
+      diet: {type: string, enum: [meat, fish]}
+`
+
+func TestGenerate(t *testing.T) {
+	swag, err := swagger2.LoadYaml([]byte(zoo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types, err := Types(swag, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := Client(swag, Options{TypesModule: "./zoo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for src, wants := range map[string][]string{
+		string(types): {
+			`kind: "Animal" | "Cat" | "Dog";`,
+			"/** The name it answers to. */\n  name: string;",
+			"tags?: { [key: string]: string };",
+			`readonly "x-id"?: number;`,
+			"export type Cat = Animal & {\n  lives?: 7 | 9;\n} & { kind: \"Cat\" };",
+			"owner?: string | null;",
+			"export type AnimalUnion = Cat | Dog;",
+			`diet?: "meat" | "fish";`,
+		},
+		string(client): {
+			`import type { Animal } from "./zoo";`,
+			`export const DEFAULT_BASE_URL = "https://zoo.example.com";`,
+			"key?: string;",
+			"basicUsername?: string;",
+			`async listPhotos(params: { id: number; size?: ("s" | "m")[]; "X-Trace": string }, init?: RequestInit): Promise<string[]> {`,
+			`if (params.size !== undefined) for (const item of params.size) query.append("size", String(item));`,
+			`headers["X-Trace"] = String(params["X-Trace"]);`,
+			"`/animals/${encodeURIComponent(String(params.id))}/photos`",
+			`[["key"], ["basic"]]`,
+			`if (apply) query.set("key", this.options.key);`,
+			`async postAnimalsByIdPhotos(params: { id: number; photo: Blob }, init?: RequestInit): Promise<Animal> {`,
+			"const body = new FormData();",
+			`body.append("photo", params.photo);`,
+			"await this.receive(res, [], false)",
+		},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(src, want) {
+				t.Errorf("expected %q in:\n%s", want, src)
+			}
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Package tsgen generates TypeScript source code from Swagger 2 documents, for web frontends consuming an API.
+package tsgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/babelrpc/swagger2"
+	"github.com/babelrpc/swagger2/internal/lists"
+)
+
+// Options controls code generation.
+type Options struct {
+	TypesModule string // The module the client imports the types from; defaults to "./types"
+}
+
+// Types generates a TypeScript module declaring a type for each of the document's definitions. Objects become
+// interfaces, enums unions of string literals, and definitions with a discriminator a union of the definitions
+// that extend them.
+func Types(swag *swagger2.Swagger, opts Options) ([]byte, error) {
+	g := newGenerator(swag, opts)
+	g.types()
+	return g.source(), nil
+}
+
+// reserved are the global types that the generated code uses and the names the client module exports, which
+// definitions are not named after
+var reserved = []string{
+	"ApiError", "Array", "Blob", "Client", "ClientOptions", "Date", "Error", "FormData", "Headers", "Object", "Promise",
+	"Record", "RequestInit", "Response", "URLSearchParams",
+}
+
+// generator holds the state of code generation for a document
+type generator struct {
+	swag     *swagger2.Swagger
+	opts     Options
+	ids      namer             // identifiers in use at module level
+	names    map[string]string // definition name to TypeScript type name
+	subtypes map[string][]string
+	used     map[string]bool // definitions referred to by the code written so far
+	decls    bytes.Buffer
+}
+
+// newGenerator names every definition up front so references can be written in any order
+func newGenerator(swag *swagger2.Swagger, opts Options) *generator {
+	if opts.TypesModule == "" {
+		opts.TypesModule = "./types"
+	}
+	g := &generator{
+		swag:     swag,
+		opts:     opts,
+		ids:      make(namer),
+		names:    make(map[string]string),
+		subtypes: make(map[string][]string),
+		used:     make(map[string]bool),
+	}
+	for _, id := range reserved {
+		g.ids[id] = true
+	}
+	for _, name := range g.definitionNames() {
+		g.names[name] = g.ids.unique(typeName(name))
+	}
+	for _, name := range g.definitionNames() {
+		for _, base := range g.bases(g.swag.Definitions[name]) {
+			g.subtypes[base] = append(g.subtypes[base], name)
+		}
+	}
+	return g
+}
+
+// definitionNames returns the definition names in sorted order
+func (g *generator) definitionNames() []string {
+	names := make([]string, 0, len(g.swag.Definitions))
+	for n := range g.swag.Definitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bases returns the definitions with a discriminator that a schema extends through allOf
+func (g *generator) bases(s swagger2.Schema) []string {
+	list := make([]string, 0)
+	for _, part := range s.AllOf {
+		name, _ := g.swag.Definitions.RefName(part.Ref)
+		if base, ok := g.swag.Definitions[name]; ok && base.Discriminator != "" {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// source returns the module with its header
+func (g *generator) source() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by swaggen. DO NOT EDIT.\n")
+	if decls := bytes.TrimRight(g.decls.Bytes(), "\n"); len(decls) > 0 {
+		b.WriteString("\n")
+		b.Write(decls)
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// types declares a type for every definition
+func (g *generator) types() {
+	for _, name := range g.definitionNames() {
+		def := g.swag.Definitions[name]
+		g.declare(name, &def)
+	}
+}
+
+// declare writes the type for a definition, and the union of its subtypes if it has a discriminator
+func (g *generator) declare(name string, s *swagger2.Schema) {
+	id := g.names[name]
+	text := s.Description
+	if text == "" {
+		text = s.Title
+	}
+	g.decls.WriteString(doc("", text, false))
+	pins := make([]string, 0)
+	for _, base := range g.bases(*s) {
+		prop := g.swag.Definitions[base].Discriminator
+		pins = append(pins, fmt.Sprintf("{ %s: %s }", propertyName(prop), literal(name)))
+	}
+	switch {
+	case len(s.AllOf) > 0:
+		fmt.Fprintf(&g.decls, "export type %s = %s;\n\n", id, strings.Join(append(g.parts(s, ""), pins...), " & "))
+	case s.Ref == "" && len(s.Properties) > 0:
+		fmt.Fprintf(&g.decls, "export interface %s %s\n\n", id, g.object(s, "", name))
+	default:
+		fmt.Fprintf(&g.decls, "export type %s = %s;\n\n", id, g.schemaType(s, ""))
+	}
+	if subs := g.subtypes[name]; len(subs) > 0 {
+		union := g.ids.unique(id + "Union")
+		members := make([]string, 0, len(subs))
+		for _, sub := range subs {
+			members = append(members, g.names[sub])
+		}
+		g.decls.WriteString(doc("", fmt.Sprintf("%s is any of the definitions that extend %s, told apart by %s.", union, id, s.Discriminator), false))
+		fmt.Fprintf(&g.decls, "export type %s = %s;\n\n", union, strings.Join(members, " | "))
+	}
+}
+
+// parts returns the types that an allOf schema intersects, followed by its own properties
+func (g *generator) parts(s *swagger2.Schema, indent string) []string {
+	list := make([]string, 0, len(s.AllOf)+1)
+	for i := range s.AllOf {
+		list = append(list, group(g.schemaType(&s.AllOf[i], indent)))
+	}
+	if len(s.Properties) > 0 {
+		list = append(list, g.object(s, indent, ""))
+	}
+	return list
+}
+
+// object writes a type literal for the properties of a schema. Properties that are not required are optional.
+// defName names the definition the schema is for, if any, so that its discriminator lists the possible values.
+func (g *generator) object(s *swagger2.Schema, indent, defName string) string {
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("{\n")
+	inner := indent + "  "
+	for _, n := range names {
+		p := s.Properties[n]
+		b.WriteString(doc(inner, p.Description, false))
+		b.WriteString(inner)
+		if p.ReadOnly != nil && *p.ReadOnly {
+			b.WriteString("readonly ")
+		}
+		b.WriteString(propertyName(n))
+		if !lists.Contains(s.Required, n) {
+			b.WriteString("?")
+		}
+		b.WriteString(": ")
+		if n == s.Discriminator && defName != "" && len(g.subtypes[defName]) > 0 && p.Ref == "" && p.Enum == nil {
+			b.WriteString(g.discriminatorType(defName))
+		} else {
+			b.WriteString(g.schemaType(&p, inner))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// discriminatorType returns the type of a discriminator property: the names of the definition and those that
+// extend it
+func (g *generator) discriminatorType(name string) string {
+	values := []string{literal(name)}
+	for _, sub := range g.subtypes[name] {
+		values = append(values, literal(sub))
+	}
+	return strings.Join(values, " | ")
+}
+
+// schemaType returns the type for a schema, writing object types inline at the given indentation
+func (g *generator) schemaType(s *swagger2.Schema, indent string) string {
+	var t string
+	switch {
+	case s.Ref != "":
+		t = g.ref(s.Ref)
+	case len(s.AllOf) > 0:
+		t = strings.Join(g.parts(s, indent), " & ")
+	case len(s.Properties) > 0:
+		t = g.object(s, indent, "")
+	default:
+		t = g.itemsType(&s.ItemsDef, indent)
+	}
+	if s.Extensions.Bool("x-nullable") {
+		t += " | null"
+	}
+	return t
+}
+
+// itemsType returns the type for the simple schemas of parameters, array items and map values
+func (g *generator) itemsType(d *swagger2.ItemsDef, indent string) string {
+	if d.Ref != "" {
+		return g.ref(d.Ref)
+	}
+	if d.Enum != nil && d.Type != "array" && d.Type != "object" {
+		if values := literals(d.Enum); values != "" {
+			return values
+		}
+	}
+	switch d.Type {
+	case "integer", "number":
+		return "number"
+	case "string":
+		return "string"
+	case "boolean":
+		return "boolean"
+	case "file":
+		return "Blob"
+	case "array":
+		if d.Items == nil {
+			return "unknown[]"
+		}
+		return group(g.itemsType(d.Items, indent)) + "[]"
+	case "object":
+		if d.AdditionalProperties != nil {
+			return "{ [key: string]: " + g.itemsType(d.AdditionalProperties, indent) + " }"
+		}
+		return "{ [key: string]: unknown }"
+	}
+	return "unknown"
+}
+
+// ref returns the type name for a reference to a definition, recording that it is used
+func (g *generator) ref(ref string) string {
+	name, _ := g.swag.Definitions.RefName(ref)
+	if id, ok := g.names[name]; ok {
+		g.used[name] = true
+		return id
+	}
+	return "unknown"
+}
+
+// group puts parentheses around a union or intersection type so that it can be combined with other types
+func group(t string) string {
+	if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") || !strings.Contains(t, " ") {
+		return t
+	}
+	return "(" + t + ")"
+}
+
+// literals returns the union of the literal types for enum values, or "" if a value has no literal type
+func literals(values []interface{}) string {
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		switch v.(type) {
+		case string, bool, int, int64, float64, uint64:
+			list = append(list, literal(v))
+		default:
+			return ""
+		}
+	}
+	return strings.Join(list, " | ")
+}
+
+// literal writes a string, number or boolean value as a TypeScript literal
+func literal(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "unknown"
+	}
+	return string(b)
+}
@@ -372,6 +372,10 @@ func (s *Documentation) Validate() []error {
 // Validate confirms that the node is set up correctly
 func (s *Parameter) Validate() []error {
 	errs := make([]error, 0)
+	// A reference to a shared parameter, validated with the parameters of the document
+	if s.Ref != "" {
+		return errs
+	}
 	// Required. The name of the parameter. Parameter names are case sensitive. If in is "path", the name field MUST correspond to the associated path segment from the path field in the Paths Object. See Path Templating for further information. For all other cases, the name corresponds to the parameter name used based on the in property.
 	if strings.TrimSpace(s.Name) == "" {
 		errs = append(errs, errors.New("parameter: name is required"))
@@ -563,23 +567,23 @@ func (s *SecurityDefinition) Validate() []error {
 	// A short description for security scheme.
 	// s.Description - not required
 	// Required. The name of the header or query parameter to be used.
-	if strings.TrimSpace(s.Name) == "" {
+	if s.Type == "apiKey" && strings.TrimSpace(s.Name) == "" {
 		errs = append(errs, errors.New("securityDefinition.name is required"))
 	}
 	// Required The location of the API key. Valid values are "query" or "header".
-	if s.In != "query" && s.In != "header" {
+	if s.Type == "apiKey" && s.In != "query" && s.In != "header" {
 		errs = append(errs, errors.New("securityDefinition.in must be \"query\" or \"header\""))
 	}
 	// Required. The flow used by the OAuth2 security scheme. Valid values are "implicit", "password", "application" or "accessCode".
-	if s.Flow != "implicit" && s.Flow != "password" && s.Flow != "application" && s.Flow != "accessCode" {
+	if s.Type == "oauth2" && s.Flow != "implicit" && s.Flow != "password" && s.Flow != "application" && s.Flow != "accessCode" {
 		errs = append(errs, errors.New("securityDefinition.flow must be \"implicit\", \"password\", \"application\", or \"accessCode\""))
 	}
 	// Required. The authorization URL to be used for this flow. This SHOULD be in the form of a URL.
-	if s.AuthorizationUrl == "" || !isUrl(s.AuthorizationUrl) {
+	if (s.Flow == "implicit" || s.Flow == "accessCode") && (s.AuthorizationUrl == "" || !isUrl(s.AuthorizationUrl)) {
 		errs = append(errs, errors.New("securityDefinition.authorizationUrl: "+s.AuthorizationUrl+" is not a valid URL"))
 	}
 	// Required. The token URL to be used for this flow. This SHOULD be in the form of a URL.
-	if s.TokenUrl == "" || !isUrl(s.TokenUrl) {
+	if (s.Flow == "password" || s.Flow == "application" || s.Flow == "accessCode") && (s.TokenUrl == "" || !isUrl(s.TokenUrl)) {
 		errs = append(errs, errors.New("securityDefinition.tokenUrl: "+s.TokenUrl+" is not a valid URL"))
 	}
 	// Required. The available scopes for the OAuth2 security scheme.
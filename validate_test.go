@@ -0,0 +1,46 @@
+package swagger2
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSecurityDefinitionValidate(t *testing.T) {
+	tests := []struct {
+		def  SecurityDefinition
+		errs string
+	}{
+		{SecurityDefinition{Type: "basic"}, "[]"},
+		{SecurityDefinition{Type: "apiKey", Name: "X-Key", In: "header"}, "[]"},
+		{SecurityDefinition{Type: "apiKey", In: "body"}, "[securityDefinition.name is required securityDefinition.in must be \"query\" or \"header\"]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "implicit", AuthorizationUrl: "https://example.com/auth"}, "[]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "password", TokenUrl: "https://example.com/token"}, "[]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "application", TokenUrl: "https://example.com/token"}, "[]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "accessCode", AuthorizationUrl: "https://example.com/auth", TokenUrl: "https://example.com/token"}, "[]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "implicit"}, "[securityDefinition.authorizationUrl:  is not a valid URL]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "accessCode", AuthorizationUrl: "https://example.com/auth"}, "[securityDefinition.tokenUrl:  is not a valid URL]"},
+		{SecurityDefinition{Type: "oauth2", Flow: "magic"}, "[securityDefinition.flow must be \"implicit\", \"password\", \"application\", or \"accessCode\"]"},
+		{SecurityDefinition{Type: "token"}, "[securityDefinition.type must be \"basic\", \"apiKey\", or \"oauth2\"]"},
+	}
+	for _, test := range tests {
+		if errs := fmt.Sprint(test.def.Validate()); errs != test.errs {
+			t.Errorf("%+v: unexpected errors %s", test.def, errs)
+		}
+	}
+}
+
+func TestParameterValidate(t *testing.T) {
+	tests := []struct {
+		param Parameter
+		errs  string
+	}{
+		{Parameter{ItemsDef: ItemsDef{Ref: "#/parameters/limit"}}, "[]"},
+		{Parameter{Name: "limit", In: "query", ItemsDef: ItemsDef{Type: "integer"}}, "[]"},
+		{Parameter{In: "query", ItemsDef: ItemsDef{Type: "integer"}}, "[parameter: name is required]"},
+	}
+	for _, test := range tests {
+		if errs := fmt.Sprint(test.param.Validate()); errs != test.errs {
+			t.Errorf("%+v: unexpected errors %s", test.param, errs)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package swagger2
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// SkipChildren can be returned by Visitor.Enter to skip the nodes below the one entered. It is not returned by
+// Walk.
+var SkipChildren = errors.New("skip children")
+
+// Visitor is called by Walk for each node of a document. Enter is called before the nodes below it and Leave
+// after them, and Leave is called for every node entered, even when its children are skipped. Any error other than
+// SkipChildren stops the walk.
+type Visitor interface {
+	Enter(c *Cursor) error
+	Leave(c *Cursor) error
+}
+
+// Cursor is the position of Walk in a document.
+type Cursor struct {
+	Node    interface{}   // The node: *Swagger, *Info, *PathItem, *Operation, *Parameter, *Response, *Header, *Schema, *ItemsDef, *SecurityDefinition or *Tag
+	Pointer string        // The JSON Pointer of the node, such as "/paths/~1pets/get"
+	Parents []interface{} // The nodes above, starting with the document
+}
+
+// Parent returns the node just above, or nil for the document.
+func (c *Cursor) Parent() interface{} {
+	if len(c.Parents) == 0 {
+		return nil
+	}
+	return c.Parents[len(c.Parents)-1]
+}
+
+// Replace replaces the node in the document with another one of the same type, such as a *Schema for a *Schema.
+// When called from Enter, Walk goes on with the nodes below the new one.
+func (c *Cursor) Replace(node interface{}) {
+	reflect.ValueOf(c.Node).Elem().Set(reflect.ValueOf(node).Elem())
+}
+
+// Hook holds the functions that Hooks calls when entering and leaving a type of node. Either can be nil.
+type Hook struct {
+	Enter func(c *Cursor) error
+	Leave func(c *Cursor) error
+}
+
+// Hooks is a Visitor with hooks for each type of node, such as one that only looks at operations.
+type Hooks struct {
+	Swagger            Hook
+	Info               Hook
+	PathItem           Hook
+	Operation          Hook
+	Parameter          Hook
+	Response           Hook
+	Header             Hook
+	Schema             Hook
+	Items              Hook
+	SecurityDefinition Hook
+	Tag                Hook
+}
+
+// hook returns the hook of the type of a node
+func (h *Hooks) hook(node interface{}) Hook {
+	switch node.(type) {
+	case *Swagger:
+		return h.Swagger
+	case *Info:
+		return h.Info
+	case *PathItem:
+		return h.PathItem
+	case *Operation:
+		return h.Operation
+	case *Parameter:
+		return h.Parameter
+	case *Response:
+		return h.Response
+	case *Header:
+		return h.Header
+	case *Schema:
+		return h.Schema
+	case *ItemsDef:
+		return h.Items
+	case *SecurityDefinition:
+		return h.SecurityDefinition
+	case *Tag:
+		return h.Tag
+	}
+	return Hook{}
+}
+
+// Enter calls the Enter hook of the type of the node.
+func (h *Hooks) Enter(c *Cursor) error {
+	if f := h.hook(c.Node).Enter; f != nil {
+		return f(c)
+	}
+	return nil
+}
+
+// Leave calls the Leave hook of the type of the node.
+func (h *Hooks) Leave(c *Cursor) error {
+	if f := h.hook(c.Node).Leave; f != nil {
+		return f(c)
+	}
+	return nil
+}
+
+// Walk visits the nodes of a document depth first: its info, then its paths in sorted order with the parameters
+// of each path item and its operations in the order of Methods, and below each operation its parameters and its
+// responses with their headers. Then come the definitions, shared parameters and responses, security definitions
+// and tags. Below parameters, responses, headers and schemas, Walk visits their schemas, items, additional
+// properties, allOf schemas and properties. Members of maps are visited in sorted order, and changes made to them,
+// including by Cursor.Replace, are stored back in the document.
+func Walk(s *Swagger, v Visitor) error {
+	w := &walker{v: v, parents: make([]interface{}, 0)}
+	err := w.swagger(s)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+// walker holds the state of a walk
+type walker struct {
+	v       Visitor
+	parents []interface{}
+}
+
+// visit enters a node, walks the nodes below it and leaves it
+func (w *walker) visit(ptr string, node interface{}, children func() error) error {
+	c := &Cursor{Node: node, Pointer: ptr, Parents: append([]interface{}(nil), w.parents...)}
+	err := w.v.Enter(c)
+	if err != nil && err != SkipChildren {
+		return err
+	}
+	if err == nil {
+		w.parents = append(w.parents, node)
+		err = children()
+		w.parents = w.parents[:len(w.parents)-1]
+		if err != nil {
+			return err
+		}
+	}
+	if err := w.v.Leave(c); err != nil && err != SkipChildren {
+		return err
+	}
+	return nil
+}
+
+func (w *walker) swagger(s *Swagger) error {
+	return w.visit("", s, func() error {
+		if err := w.visit("/info", &s.Info, func() error { return nil }); err != nil {
+			return err
+		}
+		for _, k := range keys(s.Paths) {
+			item := s.Paths[k]
+			err := w.pathItem(pointer("/paths", k), &item)
+			s.Paths[k] = item
+			if err != nil {
+				return err
+			}
+		}
+		for _, k := range keys(s.Definitions) {
+			def := s.Definitions[k]
+			err := w.schema(pointer("/definitions", k), &def)
+			s.Definitions[k] = def
+			if err != nil {
+				return err
+			}
+		}
+		for _, k := range keys(s.Parameters) {
+			p := s.Parameters[k]
+			err := w.parameter(pointer("/parameters", k), &p)
+			s.Parameters[k] = p
+			if err != nil {
+				return err
+			}
+		}
+		if err := w.responses("/responses", s.Responses); err != nil {
+			return err
+		}
+		for _, k := range keys(s.SecurityDefinitions) {
+			sd := s.SecurityDefinitions[k]
+			err := w.visit(pointer("/securityDefinitions", k), &sd, func() error { return nil })
+			s.SecurityDefinitions[k] = sd
+			if err != nil {
+				return err
+			}
+		}
+		for i := range s.Tags {
+			if err := w.visit(pointer("/tags", strconv.Itoa(i)), &s.Tags[i], func() error { return nil }); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (w *walker) pathItem(ptr string, item *PathItem) error {
+	return w.visit(ptr, item, func() error {
+		if err := w.parameters(ptr, item.Parameters); err != nil {
+			return err
+		}
+		for _, m := range Methods {
+			if op := item.Operation(m); op != nil {
+				if err := w.operation(pointer(ptr, m), op); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (w *walker) operation(ptr string, op *Operation) error {
+	return w.visit(ptr, op, func() error {
+		if err := w.parameters(ptr, op.Parameters); err != nil {
+			return err
+		}
+		return w.responses(pointer(ptr, "responses"), op.Responses)
+	})
+}
+
+func (w *walker) parameters(ptr string, params []Parameter) error {
+	for i := range params {
+		if err := w.parameter(pointer(ptr, "parameters", strconv.Itoa(i)), &params[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) parameter(ptr string, p *Parameter) error {
+	return w.visit(ptr, p, func() error {
+		if p.Schema != nil {
+			if err := w.schema(pointer(ptr, "schema"), p.Schema); err != nil {
+				return err
+			}
+		}
+		return w.itemsChildren(ptr, &p.ItemsDef)
+	})
+}
+
+func (w *walker) responses(ptr string, responses Responses) error {
+	for _, k := range keys(responses) {
+		r := responses[k]
+		err := w.visit(pointer(ptr, k), &r, func() error {
+			if r.Schema != nil {
+				if err := w.schema(pointer(ptr, k, "schema"), r.Schema); err != nil {
+					return err
+				}
+			}
+			for _, h := range keys(r.Headers) {
+				header := r.Headers[h]
+				at := pointer(ptr, k, "headers", h)
+				err := w.visit(at, &header, func() error { return w.itemsChildren(at, &header.ItemsDef) })
+				r.Headers[h] = header
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		responses[k] = r
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) schema(ptr string, s *Schema) error {
+	return w.visit(ptr, s, func() error {
+		if err := w.itemsChildren(ptr, &s.ItemsDef); err != nil {
+			return err
+		}
+		for i := range s.AllOf {
+			if err := w.schema(pointer(ptr, "allOf", strconv.Itoa(i)), &s.AllOf[i]); err != nil {
+				return err
+			}
+		}
+		for _, k := range keys(s.Properties) {
+			p := s.Properties[k]
+			err := w.schema(pointer(ptr, "properties", k), &p)
+			s.Properties[k] = p
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// itemsChildren walks the items and additional properties of a parameter, header, schema or items
+func (w *walker) itemsChildren(ptr string, i *ItemsDef) error {
+	if i.Items != nil {
+		if err := w.items(pointer(ptr, "items"), i.Items); err != nil {
+			return err
+		}
+	}
+	if i.AdditionalProperties != nil {
+		return w.items(pointer(ptr, "additionalProperties"), i.AdditionalProperties)
+	}
+	return nil
+}
+
+func (w *walker) items(ptr string, i *ItemsDef) error {
+	return w.visit(ptr, i, func() error { return w.itemsChildren(ptr, i) })
+}
@@ -0,0 +1,128 @@
+package swagger2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const walkShelter = `
+swagger: "2.0"
+info: {title: Pet Shelter, version: "1.0"}
+securityDefinitions:
+  key: {type: apiKey, name: X-Key, in: header}
+tags:
+  - {name: pets}
+parameters:
+  limit: {name: limit, in: query, type: array, items: {type: integer}}
+paths:
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, type: string}
+    get:
+      parameters:
+        - $ref: "#/parameters/limit"
+      responses:
+        200:
+          description: OK
+          headers:
+            X-Rate: {type: integer}
+          schema: {$ref: "#/definitions/Pet"}
+    delete:
+      responses:
+        204: {description: Deleted.}
+definitions:
+  Pet:
+    type: object
+    properties:
+      tags: {type: array, items: {type: string}}
+      name: {type: string}
+  NewPet:
+    allOf:
+      - $ref: "#/definitions/Pet"
+`
+
+func TestWalk(t *testing.T) {
+	swag, err := LoadYaml([]byte(walkShelter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	visits := make([]string, 0)
+	depth := 0
+	err = Walk(swag, &Hooks{
+		Swagger:   Hook{Leave: func(c *Cursor) error { visits = append(visits, "done"); return nil }},
+		Operation: Hook{Enter: func(c *Cursor) error { visits = append(visits, "operation "+c.Pointer); return nil }},
+		Parameter: Hook{Enter: func(c *Cursor) error {
+			visits = append(visits, fmt.Sprintf("parameter %s under %T", c.Pointer, c.Parent()))
+			return nil
+		}},
+		Response: Hook{Enter: func(c *Cursor) error { visits = append(visits, "response "+c.Pointer); return SkipChildren }},
+		Schema: Hook{
+			Enter: func(c *Cursor) error {
+				depth++
+				visits = append(visits, fmt.Sprintf("schema %s at %d/%d", c.Pointer, depth, len(c.Parents)))
+				return nil
+			},
+			Leave: func(c *Cursor) error { depth--; return nil },
+		},
+		Items: Hook{Enter: func(c *Cursor) error { visits = append(visits, "items "+c.Pointer); return nil }},
+		Tag:   Hook{Enter: func(c *Cursor) error { visits = append(visits, "tag "+c.Node.(*Tag).Name); return nil }},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"parameter /paths/~1pets~1{id}/parameters/0 under *swagger2.PathItem",
+		"operation /paths/~1pets~1{id}/get",
+		"parameter /paths/~1pets~1{id}/get/parameters/0 under *swagger2.Operation",
+		"response /paths/~1pets~1{id}/get/responses/200",
+		"operation /paths/~1pets~1{id}/delete",
+		"response /paths/~1pets~1{id}/delete/responses/204",
+		"schema /definitions/NewPet at 1/1",
+		"schema /definitions/NewPet/allOf/0 at 2/2",
+		"schema /definitions/Pet at 1/1",
+		"schema /definitions/Pet/properties/name at 2/2",
+		"schema /definitions/Pet/properties/tags at 2/2",
+		"items /definitions/Pet/properties/tags/items",
+		"parameter /parameters/limit under *swagger2.Swagger",
+		"items /parameters/limit/items",
+		"tag pets",
+		"done",
+	}
+	if strings.Join(visits, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected visits\n%s", strings.Join(visits, "\n"))
+	}
+
+	err = Walk(swag, &Hooks{
+		Schema: Hook{Enter: func(c *Cursor) error {
+			if s := c.Node.(*Schema); s.Type == "string" {
+				c.Replace(&Schema{ItemsDef: ItemsDef{Type: "string", Format: "uuid"}})
+			}
+			return nil
+		}},
+		Header: Hook{Enter: func(c *Cursor) error {
+			c.Node.(*Header).Description = "The rate limit."
+			return nil
+		}},
+		Operation: Hook{Leave: func(c *Cursor) error {
+			c.Node.(*Operation).Deprecated = true
+			return nil
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := swag.Paths["/pets/{id}"]
+	if swag.Definitions["Pet"].Properties["name"].Format != "uuid" || item.Get.Responses["200"].Headers["X-Rate"].Description == "" ||
+		!item.Get.Deprecated || !item.Delete.Deprecated {
+		t.Errorf("unexpected document %+v", swag)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = Walk(swag, &Hooks{Operation: Hook{Enter: func(c *Cursor) error { count++; return stop }}})
+	if err != stop || count != 1 {
+		t.Errorf("unexpected error %v after %d operations", err, count)
+	}
+}